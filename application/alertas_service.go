@@ -1,20 +1,25 @@
 package application
 
 import (
+	"time"
+
 	"github.com/Mishka-GDI-Back/domain"
 )
 
 type AlertasService interface {
-	GetAlertasActivas(limiteStock int) ([]domain.AlertaStockBajo, error)
+	GetAlertasActivas(limiteStock, diasQuiebre, nivel int) (*domain.AlertasActivas, error)
 	GetStockBajo(limite int) ([]domain.AlertaStockBajo, error)
+	GetQuiebrePrevisto(dias int) ([]domain.ReporteCobertura, error)
 }
 
 type alertasService struct {
-	repo domain.AlertasRepository
+	repo          domain.AlertasRepository
+	reportesRepo  domain.ReportesRepository
+	categoriaRepo domain.CategoriaRepository
 }
 
-func NewAlertasService(repo domain.AlertasRepository) AlertasService {
-	return &alertasService{repo: repo}
+func NewAlertasService(repo domain.AlertasRepository, reportesRepo domain.ReportesRepository, categoriaRepo domain.CategoriaRepository) AlertasService {
+	return &alertasService{repo: repo, reportesRepo: reportesRepo, categoriaRepo: categoriaRepo}
 }
 
 func (s *alertasService) GetStockBajo(limite int) ([]domain.AlertaStockBajo, error) {
@@ -24,6 +29,49 @@ func (s *alertasService) GetStockBajo(limite int) ([]domain.AlertaStockBajo, err
 	return s.repo.GetStockBajo(limite)
 }
 
-func (s *alertasService) GetAlertasActivas(limiteStock int) ([]domain.AlertaStockBajo, error) {
-	return s.GetStockBajo(limiteStock)
+// GetQuiebrePrevisto retorna los productos que, al ritmo de venta de los últimos
+// 30 días, se quedarán sin stock dentro de los próximos 'dias' días
+func (s *alertasService) GetQuiebrePrevisto(dias int) ([]domain.ReporteCobertura, error) {
+	if dias <= 0 {
+		dias = diasQuiebreDefault
+	}
+	params, err := normalizarParametrosCobertura(domain.ParametrosCobertura{Ventanas: []int{ventanaBaseDefault}})
+	if err != nil {
+		return nil, err
+	}
+	ventas, err := s.reportesRepo.GetVentasPorVentana(params.Ventanas)
+	if err != nil {
+		return nil, err
+	}
+	var alertas []domain.ReporteCobertura
+	for _, item := range calcularCobertura(ventas, params, time.Now()) {
+		if item.DiasCobertura == nil || *item.DiasCobertura > float64(dias) {
+			continue
+		}
+		alertas = append(alertas, item)
+	}
+	return alertas, nil
+}
+
+// GetAlertasActivas reúne las alertas y las cuenta por categoría, consolidadas en el
+// nivel pedido del árbol (0 = la categoría propia de cada producto)
+func (s *alertasService) GetAlertasActivas(limiteStock, diasQuiebre, nivel int) (*domain.AlertasActivas, error) {
+	if err := validarNivel(nivel); err != nil {
+		return nil, err
+	}
+	stockBajo, err := s.GetStockBajo(limiteStock)
+	if err != nil {
+		return nil, err
+	}
+	quiebre, err := s.GetQuiebrePrevisto(diasQuiebre)
+	if err != nil {
+		return nil, err
+	}
+	arbol, err := arbolCategorias(s.categoriaRepo)
+	if err != nil {
+		return nil, err
+	}
+	alertas := &domain.AlertasActivas{StockBajo: stockBajo, QuiebrePrevisto: quiebre}
+	alertas.PorCategoria = contarAlertasPorCategoria(alertas, arbol, nivel)
+	return alertas, nil
 }
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type ArchivoService interface {
+	GetByEntidad(entidad string, idEntidad int) ([]domain.Archivo, error)
+	GetByID(id int) (*domain.Archivo, error)
+	Subir(entidad string, idEntidad int, nombre, contentType string, contenido []byte, usuario string) (*domain.Archivo, error)
+	Eliminar(entidad string, idEntidad, id int) error
+	// Abrir retorna el contenido del archivo o, con miniatura, el de su miniatura
+	Abrir(id int, miniatura bool) (*domain.Archivo, io.ReadCloser, error)
+}
+
+type archivoService struct {
+	repo         domain.ArchivoRepository
+	almacen      domain.AlmacenamientoArchivos
+	imagenes     domain.ProcesadorImagenes
+	productoRepo domain.ProductoRepository
+	entradaRepo  domain.EntradaProductoRepository
+	controlRepo  domain.ControlDiarioRepository
+}
+
+func NewArchivoService(repo domain.ArchivoRepository, almacen domain.AlmacenamientoArchivos, imagenes domain.ProcesadorImagenes, productoRepo domain.ProductoRepository, entradaRepo domain.EntradaProductoRepository, controlRepo domain.ControlDiarioRepository) ArchivoService {
+	return &archivoService{repo: repo, almacen: almacen, imagenes: imagenes, productoRepo: productoRepo, entradaRepo: entradaRepo, controlRepo: controlRepo}
+}
+
+// carpetas agrupa los archivos de cada entidad en el almacenamiento
+var carpetas = map[string]string{
+	domain.EntidadProducto:      "productos",
+	domain.EntidadEntrada:       "entradas",
+	domain.EntidadControlDiario: "control-diario",
+}
+
+// verificarEntidad comprueba que exista el registro al que se adjunta el archivo
+func (s *archivoService) verificarEntidad(entidad string, idEntidad int) error {
+	if idEntidad <= 0 {
+		return &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	var err error
+	switch entidad {
+	case domain.EntidadProducto:
+		_, err = s.productoRepo.GetByID(idEntidad)
+	case domain.EntidadEntrada:
+		_, err = s.entradaRepo.GetByID(idEntidad)
+	case domain.EntidadControlDiario:
+		_, err = s.controlRepo.GetByID(idEntidad)
+	default:
+		err = &domain.ErrValidation{Field: "entidad", Message: "no admite archivos"}
+	}
+	return err
+}
+
+func (s *archivoService) GetByEntidad(entidad string, idEntidad int) ([]domain.Archivo, error) {
+	if err := s.verificarEntidad(entidad, idEntidad); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByEntidad(entidad, idEntidad)
+}
+
+func (s *archivoService) GetByID(id int) (*domain.Archivo, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.GetByID(id)
+}
+
+// Subir valida el archivo, lo guarda con un nombre aleatorio (con su miniatura si es
+// imagen) y lo registra. Si el registro falla se borra lo guardado.
+func (s *archivoService) Subir(entidad string, idEntidad int, nombre, contentType string, contenido []byte, usuario string) (*domain.Archivo, error) {
+	if err := s.verificarEntidad(entidad, idEntidad); err != nil {
+		return nil, err
+	}
+	extension, err := domain.ValidarArchivo(entidad, contentType, int64(len(contenido)))
+	if err != nil {
+		return nil, err
+	}
+	tipo := domain.TipoArchivoEntidad(entidad)
+	if tipo == domain.TipoArchivoImagen {
+		total, err := s.repo.Contar(entidad, idEntidad)
+		if err != nil {
+			return nil, err
+		}
+		if total >= domain.MaximoImagenesProducto {
+			return nil, &domain.ErrValidation{Field: "archivo", Message: fmt.Sprintf("el producto ya tiene %d imágenes; elimine alguna antes de subir otra", domain.MaximoImagenesProducto)}
+		}
+	}
+
+	archivo := &domain.Archivo{
+		Entidad:         entidad,
+		IDEntidad:       idEntidad,
+		Tipo:            tipo,
+		NombreOriginal:  domain.NombreArchivo(nombre),
+		ContentType:     contentType,
+		Tamano:          int64(len(contenido)),
+		UsuarioRegistro: usuario,
+	}
+	var miniatura []byte
+	if archivo.EsImagen() {
+		// La miniatura se genera antes de guardar nada: también valida que la imagen se pueda leer
+		if miniatura, err = s.imagenes.Miniatura(contenido); err != nil {
+			return nil, err
+		}
+	}
+	aleatorio, err := nombreAleatorio()
+	if err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("%s/%d/%s", carpetas[entidad], idEntidad, aleatorio)
+	archivo.Ruta = base + extension
+	if err := s.almacen.Guardar(archivo.Ruta, contenido); err != nil {
+		return nil, err
+	}
+	if miniatura != nil {
+		archivo.RutaMiniatura = base + "_min.jpg"
+		if err := s.almacen.Guardar(archivo.RutaMiniatura, miniatura); err != nil {
+			s.borrarContenido(archivo)
+			return nil, err
+		}
+	}
+	if err := s.repo.Create(archivo); err != nil {
+		s.borrarContenido(archivo)
+		return nil, err
+	}
+	return archivo, nil
+}
+
+// Eliminar borra el registro y después el contenido; si el contenido no se puede
+// borrar solo queda un archivo huérfano en el almacenamiento
+func (s *archivoService) Eliminar(entidad string, idEntidad, id int) error {
+	archivo, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if archivo.Entidad != entidad || archivo.IDEntidad != idEntidad {
+		return &domain.ErrNotFound{Entity: "archivo", ID: id}
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.borrarContenido(archivo)
+	return nil
+}
+
+func (s *archivoService) Abrir(id int, miniatura bool) (*domain.Archivo, io.ReadCloser, error) {
+	archivo, err := s.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	ruta := archivo.Ruta
+	if miniatura {
+		if archivo.RutaMiniatura == "" {
+			return nil, nil, &domain.ErrValidation{Field: "id", Message: "el archivo no tiene miniatura"}
+		}
+		ruta = archivo.RutaMiniatura
+	}
+	contenido, err := s.almacen.Abrir(ruta)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archivo, contenido, nil
+}
+
+func (s *archivoService) borrarContenido(archivo *domain.Archivo) {
+	for _, ruta := range []string{archivo.Ruta, archivo.RutaMiniatura} {
+		if ruta == "" {
+			continue
+		}
+		if err := s.almacen.Eliminar(ruta); err != nil {
+			log.Printf("⚠️ No se pudo eliminar el archivo %s: %v", ruta, err)
+		}
+	}
+}
+
+func nombreAleatorio() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -8,11 +8,13 @@ import (
 )
 
 type CategoriaService interface {
-	GetAll() ([]domain.Categoria, error)
+	GetAll(incluirInactivas bool) ([]domain.Categoria, error)
+	GetArbol(incluirInactivas bool) ([]*domain.NodoCategoria, error)
 	GetByID(id int) (*domain.Categoria, error)
-	Create(nombre string) (*domain.Categoria, error)
-	Update(id int, nombre string) (*domain.Categoria, error)
+	Create(nombre string, idPadre *int) (*domain.Categoria, error)
+	Update(id int, nombre string, idPadre *int) (*domain.Categoria, error)
 	Delete(id int) error
+	Restaurar(id int) (*domain.Categoria, error)
 }
 
 type categoriaService struct {
@@ -23,8 +25,17 @@ func NewCategoriaService(repo domain.CategoriaRepository) CategoriaService {
 	return &categoriaService{repo: repo}
 }
 
-func (s *categoriaService) GetAll() ([]domain.Categoria, error) {
-	return s.repo.GetAll()
+func (s *categoriaService) GetAll(incluirInactivas bool) ([]domain.Categoria, error) {
+	return s.repo.GetAll(incluirInactivas)
+}
+
+// GetArbol retorna las categorías raíz con sus subcategorías anidadas
+func (s *categoriaService) GetArbol(incluirInactivas bool) ([]*domain.NodoCategoria, error) {
+	categorias, err := s.repo.GetAll(incluirInactivas)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NuevoArbolCategorias(categorias).Raices(), nil
 }
 
 func (s *categoriaService) GetByID(id int) (*domain.Categoria, error) {
@@ -34,12 +45,15 @@ func (s *categoriaService) GetByID(id int) (*domain.Categoria, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *categoriaService) Create(nombre string) (*domain.Categoria, error) {
+func (s *categoriaService) Create(nombre string, idPadre *int) (*domain.Categoria, error) {
 	nombre = strings.TrimSpace(nombre)
 	if nombre == "" {
 		return nil, &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
 	}
-	categoria := &domain.Categoria{Nombre: nombre}
+	if err := s.validarPadre(0, idPadre); err != nil {
+		return nil, err
+	}
+	categoria := &domain.Categoria{Nombre: nombre, IDPadre: idPadre}
 	if err := s.repo.Create(categoria); err != nil {
 		if strings.Contains(err.Error(), "unique") || strings.Contains(err.Error(), "duplicate") {
 			return nil, &domain.ErrDuplicate{Entity: "categoría", Field: "nombre", Value: nombre}
@@ -49,7 +63,8 @@ func (s *categoriaService) Create(nombre string) (*domain.Categoria, error) {
 	return categoria, nil
 }
 
-func (s *categoriaService) Update(id int, nombre string) (*domain.Categoria, error) {
+// Update renombra la categoría y la mueve bajo 'idPadre' (nil la deja como raíz)
+func (s *categoriaService) Update(id int, nombre string, idPadre *int) (*domain.Categoria, error) {
 	if id <= 0 {
 		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
 	}
@@ -61,7 +76,13 @@ func (s *categoriaService) Update(id int, nombre string) (*domain.Categoria, err
 	if err != nil {
 		return nil, err
 	}
+	if !mismoPadre(categoria.IDPadre, idPadre) {
+		if err := s.validarPadre(id, idPadre); err != nil {
+			return nil, err
+		}
+	}
 	categoria.Nombre = nombre
+	categoria.IDPadre = idPadre
 	if err := s.repo.Update(categoria); err != nil {
 		if strings.Contains(err.Error(), "unique") || strings.Contains(err.Error(), "duplicate") {
 			return nil, &domain.ErrDuplicate{Entity: "categoría", Field: "nombre", Value: nombre}
@@ -71,14 +92,84 @@ func (s *categoriaService) Update(id int, nombre string) (*domain.Categoria, err
 	return categoria, nil
 }
 
+// Delete da de baja la categoría: deja de ofrecerse para productos nuevos, pero los que
+// ya la tienen la conservan
 func (s *categoriaService) Delete(id int) error {
-	if id <= 0 {
-		return &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	categoria, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if !categoria.Activo {
+		return &domain.ErrValidation{Field: "id", Message: "la categoría ya está dada de baja"}
 	}
-	if _, err := s.repo.GetByID(id); err != nil {
+	activas, err := s.repo.GetAll(false)
+	if err != nil {
 		return err
 	}
+	for _, c := range activas {
+		if c.IDPadre != nil && *c.IDPadre == id {
+			return &domain.ErrValidation{Field: "id", Message: "la categoría tiene subcategorías; muévalas o délas de baja primero"}
+		}
+	}
 	return s.repo.Delete(id)
 }
 
-var _ = errors.New // keep import
+func (s *categoriaService) Restaurar(id int) (*domain.Categoria, error) {
+	categoria, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if categoria.Activo {
+		return nil, &domain.ErrValidation{Field: "id", Message: "la categoría no está dada de baja"}
+	}
+	if categoria.IDPadre != nil {
+		padre, err := s.repo.GetByID(*categoria.IDPadre)
+		if err != nil {
+			return nil, err
+		}
+		if !padre.Activo {
+			return nil, &domain.ErrValidation{Field: "id", Message: "restaure primero la categoría padre"}
+		}
+	}
+	if err := s.repo.Restaurar(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// validarPadre exige que el padre exista, esté activo y no sea la propia categoría ni
+// una de sus subcategorías, lo que formaría un ciclo. 'id' es 0 al crear.
+func (s *categoriaService) validarPadre(id int, idPadre *int) error {
+	if idPadre == nil {
+		return nil
+	}
+	if *idPadre == id {
+		return &domain.ErrValidation{Field: "id_categoria_padre", Message: "una categoría no puede ser su propio padre"}
+	}
+	padre, err := s.repo.GetByID(*idPadre)
+	var notFound *domain.ErrNotFound
+	if errors.As(err, &notFound) {
+		return &domain.ErrValidation{Field: "id_categoria_padre", Message: "la categoría padre no existe"}
+	}
+	if err != nil {
+		return err
+	}
+	if !padre.Activo {
+		return &domain.ErrValidation{Field: "id_categoria_padre", Message: "la categoría padre está dada de baja"}
+	}
+	if id == 0 {
+		return nil
+	}
+	todas, err := s.repo.GetAll(true)
+	if err != nil {
+		return err
+	}
+	if domain.NuevoArbolCategorias(todas).EsDescendiente(*idPadre, id) {
+		return &domain.ErrValidation{Field: "id_categoria_padre", Message: "no puede ser una subcategoría de la propia categoría"}
+	}
+	return nil
+}
+
+func mismoPadre(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type ClienteService interface {
+	GetAll(busqueda string) ([]domain.ClienteConSaldo, error)
+	GetByID(id int) (*domain.ClienteConSaldo, error)
+	Create(cliente *domain.Cliente) (*domain.Cliente, error)
+	Update(id int, cliente *domain.Cliente) (*domain.Cliente, error)
+	GetCuentas(clienteID int, soloPendientes bool) ([]domain.CuentaPorCobrar, error)
+	RegistrarPago(pago *domain.PagoCliente, idCuenta *int) (*domain.PagoCliente, error)
+	GetEstadoCuenta(clienteID int, rango domain.RangoFechas) (*domain.EstadoCuenta, error)
+	GetAntiguedad(fecha time.Time) ([]domain.ReporteAntiguedad, error)
+}
+
+type clienteService struct {
+	repo domain.ClienteRepository
+}
+
+func NewClienteService(repo domain.ClienteRepository) ClienteService {
+	return &clienteService{repo: repo}
+}
+
+// Formato del número según el tipo de documento
+var formatosDocumento = map[string]*regexp.Regexp{
+	domain.DocumentoDNI: regexp.MustCompile(`^\d{8}$`),
+	domain.DocumentoRUC: regexp.MustCompile(`^\d{11}$`),
+	domain.DocumentoCE:  regexp.MustCompile(`^[A-Z0-9]{6,12}$`),
+}
+
+func (s *clienteService) GetAll(busqueda string) ([]domain.ClienteConSaldo, error) {
+	return s.repo.GetAll(strings.TrimSpace(busqueda))
+}
+
+func (s *clienteService) GetByID(id int) (*domain.ClienteConSaldo, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *clienteService) Create(cliente *domain.Cliente) (*domain.Cliente, error) {
+	if err := s.validar(cliente); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(cliente); err != nil {
+		return nil, err
+	}
+	return cliente, nil
+}
+
+func (s *clienteService) Update(id int, cliente *domain.Cliente) (*domain.Cliente, error) {
+	existente, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	cliente.ID = existente.ID
+	cliente.FechaCreacion = existente.FechaCreacion
+	if err := s.validar(cliente); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(cliente); err != nil {
+		return nil, err
+	}
+	return cliente, nil
+}
+
+func (s *clienteService) validar(c *domain.Cliente) error {
+	c.Nombre = strings.TrimSpace(c.Nombre)
+	c.TipoDocumento = strings.ToUpper(strings.TrimSpace(c.TipoDocumento))
+	c.NumeroDocumento = strings.ToUpper(strings.TrimSpace(c.NumeroDocumento))
+	c.Telefono = strings.TrimSpace(c.Telefono)
+	c.Direccion = strings.TrimSpace(c.Direccion)
+	c.Email = strings.TrimSpace(c.Email)
+	if c.Nombre == "" {
+		return &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
+	}
+	if c.NumeroDocumento != "" || c.TipoDocumento != "" {
+		formato, ok := formatosDocumento[c.TipoDocumento]
+		if !ok {
+			return &domain.ErrValidation{Field: "tipo_documento", Message: "debe ser DNI, RUC o CE"}
+		}
+		if !formato.MatchString(c.NumeroDocumento) {
+			return &domain.ErrValidation{Field: "numero_documento", Message: "no tiene el formato de un " + c.TipoDocumento}
+		}
+		existente, err := s.repo.GetByDocumento(c.TipoDocumento, c.NumeroDocumento)
+		var notFound *domain.ErrNotFound
+		if err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+		if existente != nil && existente.ID != c.ID {
+			return &domain.ErrDuplicate{Entity: "cliente", Field: "numero_documento", Value: c.NumeroDocumento}
+		}
+	}
+	if c.LimiteCredito != nil && *c.LimiteCredito < 0 {
+		return &domain.ErrValidation{Field: "limite_credito", Message: "no puede ser negativo"}
+	}
+	if c.DiasCredito < 0 {
+		return &domain.ErrValidation{Field: "dias_credito", Message: "no puede ser negativo"}
+	}
+	return nil
+}
+
+func (s *clienteService) GetCuentas(clienteID int, soloPendientes bool) ([]domain.CuentaPorCobrar, error) {
+	if _, err := s.GetByID(clienteID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCuentas(clienteID, soloPendientes)
+}
+
+// RegistrarPago aplica el pago a la cuenta indicada o, sin cuenta, a las pendientes
+// desde la más antigua. El pago no puede superar la deuda que cubre.
+func (s *clienteService) RegistrarPago(pago *domain.PagoCliente, idCuenta *int) (*domain.PagoCliente, error) {
+	pago.Monto = math.Round(pago.Monto*100) / 100
+	if pago.Monto <= 0 {
+		return nil, &domain.ErrValidation{Field: "monto", Message: "debe ser mayor a 0"}
+	}
+	pago.UsuarioRegistro = strings.TrimSpace(pago.UsuarioRegistro)
+	if pago.UsuarioRegistro == "" {
+		return nil, &domain.ErrValidation{Field: "usuario_registro", Message: "es requerido"}
+	}
+	cliente, err := s.GetByID(pago.IDCliente)
+	if err != nil {
+		return nil, err
+	}
+	cuentas, err := s.repo.GetCuentas(cliente.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if idCuenta != nil {
+		i := -1
+		for j := range cuentas {
+			if cuentas[j].ID == *idCuenta {
+				i = j
+			}
+		}
+		if i < 0 {
+			return nil, &domain.ErrNotFound{Entity: "cuenta pendiente del cliente", ID: *idCuenta}
+		}
+		cuentas = cuentas[i : i+1]
+	}
+	aplicaciones, restante := domain.AplicarPago(pago.Monto, cuentas)
+	if restante > 0 {
+		return nil, &domain.ErrValidation{Field: "monto", Message: fmt.Sprintf("supera la deuda pendiente por %.2f", restante)}
+	}
+	pago.Aplicaciones = aplicaciones
+	pago.TipoPago = strings.TrimSpace(pago.TipoPago)
+	pago.Observaciones = strings.TrimSpace(pago.Observaciones)
+
+	comprobantes := make([]string, 0, len(aplicaciones))
+	for _, a := range aplicaciones {
+		for _, c := range cuentas {
+			if c.ID == a.IDCuenta {
+				comprobantes = append(comprobantes, c.Comprobante)
+			}
+		}
+	}
+	// La cobranza entra a caja como un ingreso más del día
+	ingreso := &domain.ControlDiario{
+		Fecha:           pago.Fecha,
+		Descripcion:     "COBRANZA - " + cliente.Nombre,
+		MontoEntrada:    pago.Monto,
+		Observaciones:   "Pago de " + strings.Join(comprobantes, ", "),
+		UsuarioRegistro: pago.UsuarioRegistro,
+	}
+	if err := s.repo.RegistrarPago(pago, ingreso); err != nil {
+		return nil, err
+	}
+	return pago, nil
+}
+
+func (s *clienteService) GetEstadoCuenta(clienteID int, rango domain.RangoFechas) (*domain.EstadoCuenta, error) {
+	if err := validarRangoFechas(rango); err != nil {
+		return nil, err
+	}
+	cliente, err := s.GetByID(clienteID)
+	if err != nil {
+		return nil, err
+	}
+	saldoInicial, movimientos, err := s.repo.GetMovimientos(clienteID, rango)
+	if err != nil {
+		return nil, err
+	}
+	estado := &domain.EstadoCuenta{Cliente: cliente.Cliente, RangoFechas: rango, SaldoInicial: saldoInicial, Movimientos: movimientos}
+	estado.Acumular()
+	return estado, nil
+}
+
+// GetAntiguedad agrupa los saldos pendientes por cliente y tramos de atraso contados
+// a 'fecha', los clientes con más deuda primero
+func (s *clienteService) GetAntiguedad(fecha time.Time) ([]domain.ReporteAntiguedad, error) {
+	cuentas, err := s.repo.GetCuentasPendientes()
+	if err != nil {
+		return nil, err
+	}
+	clientes, err := s.repo.GetAll("")
+	if err != nil {
+		return nil, err
+	}
+	nombres := make(map[int]string, len(clientes))
+	for _, c := range clientes {
+		nombres[c.ID] = c.Nombre
+	}
+	porCliente := make(map[int]*domain.ReporteAntiguedad)
+	var reporte []*domain.ReporteAntiguedad
+	for _, c := range cuentas {
+		r, ok := porCliente[c.IDCliente]
+		if !ok {
+			r = &domain.ReporteAntiguedad{IDCliente: c.IDCliente, Cliente: nombres[c.IDCliente]}
+			porCliente[c.IDCliente] = r
+			reporte = append(reporte, r)
+		}
+		r.Sumar(c.Saldo, c.DiasVencida(fecha))
+	}
+	sort.SliceStable(reporte, func(i, j int) bool { return reporte[i].Total > reporte[j].Total })
+	items := make([]domain.ReporteAntiguedad, len(reporte))
+	for i, r := range reporte {
+		for _, v := range []*float64{&r.PorVencer, &r.Dias1a30, &r.Dias31a60, &r.Dias61a90, &r.Mas90, &r.Total} {
+			*v = math.Round(*v*100) / 100
+		}
+		items[i] = *r
+	}
+	return items, nil
+}
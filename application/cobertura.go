@@ -0,0 +1,104 @@
+package application
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+const (
+	maxDiasVentana      = 365
+	diasObjetivoDefault = 30
+	ventanaBaseDefault  = 30
+	diasQuiebreDefault  = 7
+)
+
+var ventanasDefault = []int{7, 30, 90}
+
+// normalizarParametrosCobertura aplica valores por defecto y valida las ventanas
+func normalizarParametrosCobertura(p domain.ParametrosCobertura) (domain.ParametrosCobertura, error) {
+	if len(p.Ventanas) == 0 {
+		p.Ventanas = append([]int(nil), ventanasDefault...)
+	}
+	if p.VentanaBase <= 0 {
+		p.VentanaBase = ventanaBaseDefault
+	}
+	if p.DiasObjetivo <= 0 {
+		p.DiasObjetivo = diasObjetivoDefault
+	}
+	if p.VentanaBase > maxDiasVentana {
+		return p, &domain.ErrValidation{Field: "ventana_base", Message: "debe estar entre 1 y 365 días"}
+	}
+	for _, v := range p.Ventanas {
+		if v <= 0 || v > maxDiasVentana {
+			return p, &domain.ErrValidation{Field: "ventanas", Message: "cada ventana debe estar entre 1 y 365 días"}
+		}
+	}
+	incluida := false
+	for _, v := range p.Ventanas {
+		if v == p.VentanaBase {
+			incluida = true
+			break
+		}
+	}
+	if !incluida {
+		p.Ventanas = append(p.Ventanas, p.VentanaBase)
+	}
+	sort.Ints(p.Ventanas)
+	return p, nil
+}
+
+// calcularCobertura obtiene la velocidad de venta, los días de stock restantes y
+// la cantidad a reponer para cubrir DiasObjetivo días. El resultado se ordena por
+// días de cobertura ascendente; los productos sin ventas quedan al final.
+func calcularCobertura(ventas []domain.VentasProductoVentana, p domain.ParametrosCobertura, hoy time.Time) []domain.ReporteCobertura {
+	items := make([]domain.ReporteCobertura, 0, len(ventas))
+	for _, v := range ventas {
+		item := domain.ReporteCobertura{
+			IDProducto:     v.IDProducto,
+			Codigo:         v.Codigo,
+			Nombre:         v.Nombre,
+			IDCategoria:    v.IDCategoria,
+			Categoria:      v.Categoria,
+			StockActual:    v.StockActual,
+			PrecioUnitario: v.PrecioUnitario,
+		}
+		for _, dias := range p.Ventanas {
+			vel := domain.VelocidadVenta{
+				DiasVentana:    dias,
+				TotalVendido:   v.Vendido[dias],
+				PromedioDiario: float64(v.Vendido[dias]) / float64(dias),
+			}
+			item.Velocidades = append(item.Velocidades, vel)
+			if dias == p.VentanaBase {
+				item.PromedioDiario = vel.PromedioDiario
+			}
+		}
+		if item.PromedioDiario > 0 {
+			stock := math.Max(float64(item.StockActual), 0)
+			dias := stock / item.PromedioDiario
+			quiebre := hoy.AddDate(0, 0, int(math.Floor(dias)))
+			item.DiasCobertura = &dias
+			item.FechaQuiebre = &quiebre
+			requerido := int(math.Ceil(item.PromedioDiario * float64(p.DiasObjetivo)))
+			if requerido > item.StockActual {
+				item.CantidadSugerida = requerido - item.StockActual
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].DiasCobertura, items[j].DiasCobertura
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		default:
+			return *a < *b
+		}
+	})
+	return items
+}
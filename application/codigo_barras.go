@@ -0,0 +1,67 @@
+package application
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+// buscarPorCodigoBarras busca primero en los códigos de barras y luego en el código
+// interno del producto, para que el escáner funcione con etiquetas propias
+func buscarPorCodigoBarras(repo domain.ProductoRepository, codigo string) (*domain.Producto, error) {
+	codigo = strings.TrimSpace(codigo)
+	if codigo == "" {
+		return nil, &domain.ErrValidation{Field: "codigo_barras", Message: "es requerido"}
+	}
+	producto, err := repo.GetByCodigoBarras(codigo)
+	var notFound *domain.ErrNotFound
+	if errors.As(err, &notFound) {
+		porCodigo, errCodigo := repo.GetByCodigo(codigo)
+		if errCodigo == nil {
+			return porCodigo, nil
+		}
+		if !errors.As(errCodigo, &notFound) {
+			return nil, errCodigo
+		}
+	}
+	return producto, err
+}
+
+// resolverProducto identifica el producto de un movimiento por ID o, si no se envía,
+// por código de barras. Un padre con variantes no recibe movimientos: el stock se
+// lleva en cada variante. Tampoco los recibe un producto dado de baja.
+func resolverProducto(repo domain.ProductoRepository, id int, codigoBarras string) (*domain.Producto, error) {
+	var producto *domain.Producto
+	var err error
+	switch {
+	case id > 0:
+		producto, err = repo.GetByID(id)
+	case strings.TrimSpace(codigoBarras) != "":
+		producto, err = buscarPorCodigoBarras(repo, codigoBarras)
+	default:
+		return nil, &domain.ErrValidation{Field: "id_producto", Message: "envíe id_producto o codigo_barras"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarActivo(producto); err != nil {
+		return nil, err
+	}
+	variantes, err := repo.GetVariantes(producto.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variantes) > 0 {
+		return nil, &domain.ErrValidation{Field: "id_producto", Message: "el producto tiene variantes; registre el movimiento en la variante"}
+	}
+	return producto, nil
+}
+
+// verificarActivo rechaza los productos descontinuados en las operaciones del día a día
+func verificarActivo(producto *domain.Producto) error {
+	if !producto.Activo {
+		return &domain.ErrValidation{Field: "id_producto", Message: "el producto " + producto.Codigo + " está dado de baja"}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type ComprobanteService interface {
+	GetDetalle(id int) (*domain.ComprobanteDetalle, error)
+	GetSeries() ([]domain.SerieComprobante, error)
+	CreateSerie(serie *domain.SerieComprobante) (*domain.SerieComprobante, error)
+	UpdateSerie(id int, serie *domain.SerieComprobante) (*domain.SerieComprobante, error)
+}
+
+type comprobanteService struct {
+	repo        domain.ComprobanteRepository
+	salidaRepo  domain.SalidaProductoRepository
+	clienteRepo domain.ClienteRepository
+}
+
+func NewComprobanteService(repo domain.ComprobanteRepository, salidaRepo domain.SalidaProductoRepository, clienteRepo domain.ClienteRepository) ComprobanteService {
+	return &comprobanteService{repo: repo, salidaRepo: salidaRepo, clienteRepo: clienteRepo}
+}
+
+// Una letra seguida de tres caracteres alfanuméricos, p. ej. B001 o T001
+var formatoSerie = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+
+func (s *comprobanteService) GetDetalle(id int) (*domain.ComprobanteDetalle, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	comprobante, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	lineas, err := s.salidaRepo.GetByComprobante(id)
+	if err != nil {
+		return nil, err
+	}
+	detalle := &domain.ComprobanteDetalle{Comprobante: *comprobante, Lineas: lineas}
+	if comprobante.IDCliente != nil {
+		cliente, err := s.clienteRepo.GetByID(*comprobante.IDCliente)
+		if err != nil {
+			return nil, err
+		}
+		detalle.Cliente = &cliente.Cliente
+	}
+	if comprobante.TipoPago == domain.TipoPagoCredito {
+		if detalle.Cuenta, err = s.clienteRepo.GetCuentaByComprobante(id); err != nil {
+			return nil, err
+		}
+	}
+	return detalle, nil
+}
+
+func (s *comprobanteService) GetSeries() ([]domain.SerieComprobante, error) {
+	return s.repo.GetSeries()
+}
+
+func (s *comprobanteService) CreateSerie(serie *domain.SerieComprobante) (*domain.SerieComprobante, error) {
+	serie.Serie = strings.ToUpper(strings.TrimSpace(serie.Serie))
+	if !formatoSerie.MatchString(serie.Serie) {
+		return nil, &domain.ErrValidation{Field: "serie", Message: "debe tener 4 caracteres y empezar con una letra, p. ej. B001"}
+	}
+	if serie.Correlativo < 0 {
+		return nil, &domain.ErrValidation{Field: "correlativo", Message: "no puede ser negativo"}
+	}
+	if err := s.validarSerie(serie); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSerie(serie); err != nil {
+		return nil, err
+	}
+	return serie, nil
+}
+
+// UpdateSerie cambia el tipo, el lugar o el estado; el código y el correlativo no se
+// editan para no romper la numeración ya emitida
+func (s *comprobanteService) UpdateSerie(id int, serie *domain.SerieComprobante) (*domain.SerieComprobante, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	existente, err := s.repo.GetSerieByID(id)
+	if err != nil {
+		return nil, err
+	}
+	existente.Tipo = serie.Tipo
+	existente.LugarVenta = serie.LugarVenta
+	existente.Activa = serie.Activa
+	if err := s.validarSerie(existente); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSerie(existente); err != nil {
+		return nil, err
+	}
+	return existente, nil
+}
+
+// validarSerie normaliza el tipo y el lugar y evita códigos repetidos o dos series
+// activas para el mismo lugar, que harían ambigua la numeración
+func (s *comprobanteService) validarSerie(serie *domain.SerieComprobante) error {
+	serie.Tipo = strings.ToUpper(strings.TrimSpace(serie.Tipo))
+	if serie.Tipo == "" {
+		serie.Tipo = domain.TipoComprobanteTicket
+	}
+	if serie.Tipo != domain.TipoComprobanteBoleta && serie.Tipo != domain.TipoComprobanteTicket {
+		return &domain.ErrValidation{Field: "tipo", Message: "debe ser BOLETA o TICKET"}
+	}
+	serie.LugarVenta = strings.TrimSpace(serie.LugarVenta)
+	series, err := s.repo.GetSeries()
+	if err != nil {
+		return err
+	}
+	for _, otra := range series {
+		if otra.ID == serie.ID {
+			continue
+		}
+		if otra.Serie == serie.Serie {
+			return &domain.ErrDuplicate{Entity: "serie de comprobante", Field: "serie", Value: serie.Serie}
+		}
+		if serie.Activa && otra.Activa && strings.EqualFold(otra.LugarVenta, serie.LugarVenta) {
+			return &domain.ErrDuplicate{Entity: "serie activa", Field: "lugar_venta", Value: serie.LugarVenta}
+		}
+	}
+	return nil
+}
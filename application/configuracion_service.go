@@ -0,0 +1,90 @@
+package application
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type ConfiguracionService interface {
+	GetAll() (map[string]string, error)
+	Update(valores map[string]string) (map[string]string, error)
+	GetNegocio() (*domain.DatosNegocio, error)
+}
+
+type configuracionService struct {
+	repo domain.ConfiguracionRepository
+	// predeterminado son los datos de las variables de entorno, usados mientras no
+	// se guarde un valor propio
+	predeterminado domain.DatosNegocio
+}
+
+func NewConfiguracionService(repo domain.ConfiguracionRepository, predeterminado domain.DatosNegocio) ConfiguracionService {
+	return &configuracionService{repo: repo, predeterminado: predeterminado}
+}
+
+// GetAll retorna todas las claves conocidas con su valor vigente
+func (s *configuracionService) GetAll() (map[string]string, error) {
+	guardados, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	valores := map[string]string{
+		domain.ConfigNegocioNombre:    s.predeterminado.Nombre,
+		domain.ConfigNegocioRUC:       s.predeterminado.RUC,
+		domain.ConfigNegocioDireccion: s.predeterminado.Direccion,
+		domain.ConfigNegocioTelefono:  s.predeterminado.Telefono,
+		domain.ConfigComprobantePie:   s.predeterminado.PieComprobante,
+	}
+	for clave, valor := range guardados {
+		valores[clave] = valor
+	}
+	return valores, nil
+}
+
+func (s *configuracionService) Update(valores map[string]string) (map[string]string, error) {
+	if len(valores) == 0 {
+		return nil, &domain.ErrValidation{Field: "configuracion", Message: "no hay valores para guardar"}
+	}
+	limpios := make(map[string]string, len(valores))
+	for clave, valor := range valores {
+		if !slices.Contains(domain.ClavesConfiguracion, clave) {
+			return nil, &domain.ErrValidation{Field: clave, Message: "clave de configuración desconocida"}
+		}
+		limpios[clave] = strings.TrimSpace(valor)
+	}
+	if ruc := limpios[domain.ConfigNegocioRUC]; ruc != "" && !esRUC(ruc) {
+		return nil, &domain.ErrValidation{Field: domain.ConfigNegocioRUC, Message: "debe tener 11 dígitos"}
+	}
+	if err := s.repo.Set(limpios); err != nil {
+		return nil, err
+	}
+	return s.GetAll()
+}
+
+func (s *configuracionService) GetNegocio() (*domain.DatosNegocio, error) {
+	valores, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DatosNegocio{
+		Nombre:         valores[domain.ConfigNegocioNombre],
+		RUC:            valores[domain.ConfigNegocioRUC],
+		Direccion:      valores[domain.ConfigNegocioDireccion],
+		Telefono:       valores[domain.ConfigNegocioTelefono],
+		PieComprobante: valores[domain.ConfigComprobantePie],
+	}, nil
+}
+
+func esRUC(ruc string) bool {
+	if len(ruc) != 11 {
+		return false
+	}
+	for _, r := range ruc {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
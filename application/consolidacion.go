@@ -0,0 +1,101 @@
+package application
+
+import (
+	"sort"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+// arbolCategorias carga todas las categorías, también las dadas de baja, porque sus
+// productos siguen apareciendo en los reportes
+func arbolCategorias(repo domain.CategoriaRepository) (*domain.ArbolCategorias, error) {
+	categorias, err := repo.GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NuevoArbolCategorias(categorias), nil
+}
+
+// validarNivel acepta 0 (cada categoría por separado) o un nivel del árbol desde 1 (raíces)
+func validarNivel(nivel int) error {
+	if nivel < 0 {
+		return &domain.ErrValidation{Field: "nivel", Message: "debe ser 0 (sin consolidar) o mayor"}
+	}
+	return nil
+}
+
+// claveCategoria identifica la fila consolidada; los productos sin categoría van en la 0
+func claveCategoria(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// consolidarValoracion suma la valoración de cada categoría en su ancestro del nivel pedido
+func consolidarValoracion(items []domain.ReporteValoracion, arbol *domain.ArbolCategorias, nivel int) []domain.ReporteValoracion {
+	var result []domain.ReporteValoracion
+	indice := make(map[int]int)
+	for _, item := range items {
+		id := item.IDCategoria
+		destino, nombre := arbol.Agrupar(&id, nivel)
+		i, ok := indice[*destino]
+		if !ok {
+			i = len(result)
+			indice[*destino] = i
+			result = append(result, domain.ReporteValoracion{IDCategoria: *destino, NombreCategoria: nombre})
+		}
+		result[i].TotalProductos += item.TotalProductos
+		result[i].TotalUnidades += item.TotalUnidades
+		result[i].ValorTotal += item.ValorTotal
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ValorTotal > result[j].ValorTotal })
+	return result
+}
+
+// consolidarVentasCategoria suma las ventas de cada categoría en su ancestro del nivel pedido
+func consolidarVentasCategoria(items []domain.ReporteVentasCategoria, arbol *domain.ArbolCategorias, nivel int) []domain.ReporteVentasCategoria {
+	var result []domain.ReporteVentasCategoria
+	indice := make(map[int]int)
+	for _, item := range items {
+		destino, nombre := arbol.Agrupar(item.IDCategoria, nivel)
+		i, ok := indice[claveCategoria(destino)]
+		if !ok {
+			i = len(result)
+			indice[claveCategoria(destino)] = i
+			result = append(result, domain.ReporteVentasCategoria{IDCategoria: destino, Categoria: nombre})
+		}
+		result[i].Ventas += item.Ventas
+		result[i].Unidades += item.Unidades
+		result[i].Ingresos += item.Ingresos
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Ingresos > result[j].Ingresos })
+	return result
+}
+
+// contarAlertasPorCategoria cuenta las alertas de cada categoría del nivel pedido,
+// las que tienen más alertas primero
+func contarAlertasPorCategoria(alertas *domain.AlertasActivas, arbol *domain.ArbolCategorias, nivel int) []domain.AlertasCategoria {
+	var result []domain.AlertasCategoria
+	indice := make(map[int]int)
+	fila := func(id *int) *domain.AlertasCategoria {
+		destino, nombre := arbol.Agrupar(id, nivel)
+		i, ok := indice[claveCategoria(destino)]
+		if !ok {
+			i = len(result)
+			indice[claveCategoria(destino)] = i
+			result = append(result, domain.AlertasCategoria{IDCategoria: destino, Categoria: nombre})
+		}
+		return &result[i]
+	}
+	for _, a := range alertas.StockBajo {
+		fila(a.IDCategoria).StockBajo++
+	}
+	for _, a := range alertas.QuiebrePrevisto {
+		fila(a.IDCategoria).QuiebrePrevisto++
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StockBajo+result[i].QuiebrePrevisto > result[j].StockBajo+result[j].QuiebrePrevisto
+	})
+	return result
+}
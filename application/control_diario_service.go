@@ -8,7 +8,7 @@ import (
 )
 
 type ControlDiarioService interface {
-	GetAll() ([]domain.ControlDiario, error)
+	GetAll(filtro domain.FiltroControlDiario) ([]domain.ControlDiario, *domain.TotalesControlDiario, error)
 	GetByFecha(fecha string) ([]domain.ControlDiario, error)
 	GetHoy() ([]domain.ControlDiario, error)
 	GetVerbena() ([]domain.ControlDiario, error)
@@ -24,8 +24,18 @@ func NewControlDiarioService(controlRepo domain.ControlDiarioRepository) Control
 	return &controlDiarioService{controlRepo: controlRepo}
 }
 
-func (s *controlDiarioService) GetAll() ([]domain.ControlDiario, error) {
-	return s.controlRepo.GetAll()
+func (s *controlDiarioService) GetAll(filtro domain.FiltroControlDiario) ([]domain.ControlDiario, *domain.TotalesControlDiario, error) {
+	if err := validarRangoFechas(filtro.RangoFechas); err != nil {
+		return nil, nil, err
+	}
+	if err := validarOrden(&filtro.Orden, domain.CamposOrdenControlDiario); err != nil {
+		return nil, nil, err
+	}
+	if err := filtro.Paginacion.Normalizar(); err != nil {
+		return nil, nil, err
+	}
+	filtro.Usuario = strings.TrimSpace(filtro.Usuario)
+	return s.controlRepo.GetAll(filtro)
 }
 
 func (s *controlDiarioService) GetByFecha(fecha string) ([]domain.ControlDiario, error) {
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type DevolucionProveedorService interface {
+	GetAll(filtro domain.FiltroDevolucionesProveedor) ([]domain.DevolucionProveedorConProducto, error)
+	GetByID(id int) (*domain.DevolucionProveedorConProducto, error)
+	GetByEntrada(entradaID int) ([]domain.DevolucionProveedorConProducto, error)
+	Create(devolucion *domain.DevolucionProveedor, monto *float64) (*domain.DevolucionProveedor, error)
+	Liquidar(id int, liquidacion *domain.LiquidacionProveedor) (*domain.DevolucionProveedorConProducto, error)
+}
+
+type devolucionProveedorService struct {
+	repo        domain.DevolucionProveedorRepository
+	entradaRepo domain.EntradaProductoRepository
+}
+
+func NewDevolucionProveedorService(repo domain.DevolucionProveedorRepository, entradaRepo domain.EntradaProductoRepository) DevolucionProveedorService {
+	return &devolucionProveedorService{repo: repo, entradaRepo: entradaRepo}
+}
+
+func (s *devolucionProveedorService) GetAll(filtro domain.FiltroDevolucionesProveedor) ([]domain.DevolucionProveedorConProducto, error) {
+	if err := validarRangoFechas(filtro.RangoFechas); err != nil {
+		return nil, err
+	}
+	filtro.Proveedor = strings.TrimSpace(filtro.Proveedor)
+	filtro.Estado = strings.ToUpper(strings.TrimSpace(filtro.Estado))
+	if filtro.Estado != "" && filtro.Estado != domain.EstadoDevolucionPendiente && filtro.Estado != domain.EstadoDevolucionLiquidada {
+		return nil, &domain.ErrValidation{Field: "estado", Message: "debe ser PENDIENTE o LIQUIDADA"}
+	}
+	return s.repo.GetAll(filtro)
+}
+
+func (s *devolucionProveedorService) GetByID(id int) (*domain.DevolucionProveedorConProducto, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *devolucionProveedorService) GetByEntrada(entradaID int) ([]domain.DevolucionProveedorConProducto, error) {
+	if _, err := s.entrada(entradaID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByEntrada(entradaID)
+}
+
+// Create devuelve al proveedor parte de una entrada. Sin monto se espera recuperar lo
+// pagado por esas unidades; si la entrada no tiene precio el monto es obligatorio.
+func (s *devolucionProveedorService) Create(devolucion *domain.DevolucionProveedor, monto *float64) (*domain.DevolucionProveedor, error) {
+	if devolucion.Cantidad <= 0 {
+		return nil, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
+	}
+	devolucion.UsuarioRegistro = strings.TrimSpace(devolucion.UsuarioRegistro)
+	if devolucion.UsuarioRegistro == "" {
+		return nil, &domain.ErrValidation{Field: "usuario_registro", Message: "es requerido"}
+	}
+	devolucion.TipoCredito = strings.ToUpper(strings.TrimSpace(devolucion.TipoCredito))
+	if devolucion.TipoCredito == "" {
+		devolucion.TipoCredito = domain.TipoCreditoNotaCredito
+	}
+	if devolucion.TipoCredito != domain.TipoCreditoNotaCredito && devolucion.TipoCredito != domain.TipoCreditoReembolso {
+		return nil, &domain.ErrValidation{Field: "tipo_credito", Message: "debe ser NOTA_CREDITO o REEMBOLSO"}
+	}
+	devolucion.Motivo = strings.TrimSpace(devolucion.Motivo)
+
+	entrada, err := s.entrada(devolucion.IDEntrada)
+	if err != nil {
+		return nil, err
+	}
+	if devolucion.Fecha.Before(entrada.FechaEntrada) {
+		return nil, &domain.ErrValidation{Field: "fecha", Message: "no puede ser anterior a la entrada"}
+	}
+	devuelto, err := s.repo.GetDevuelto(entrada.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pendiente := entrada.Cantidad - devuelto; devolucion.Cantidad > pendiente {
+		return nil, &domain.ErrValidation{Field: "cantidad", Message: fmt.Sprintf("supera lo recibido pendiente de devolver (%d de %d)", pendiente, entrada.Cantidad)}
+	}
+	switch {
+	case monto != nil:
+		devolucion.MontoEsperado = math.Round(*monto*100) / 100
+	case entrada.PrecioUnitario != nil:
+		// Se prorratea lo pagado por la línea: el precio unitario guardado está redondeado
+		devolucion.MontoEsperado = math.Round(entrada.Importe()*float64(devolucion.Cantidad)/float64(entrada.Cantidad)*100) / 100
+	default:
+		return nil, &domain.ErrValidation{Field: "monto_esperado", Message: "es requerido: la entrada no tiene precio unitario"}
+	}
+	if devolucion.MontoEsperado < 0 {
+		return nil, &domain.ErrValidation{Field: "monto_esperado", Message: "no puede ser negativo"}
+	}
+	// El crédito fiscal de lo devuelto se revierte en proporción a lo recibido
+	proporcion := float64(devolucion.Cantidad) / float64(entrada.Cantidad)
+	devolucion.BaseImponible = math.Round(entrada.BaseImponible*proporcion*100) / 100
+	devolucion.IGV = math.Round(entrada.IGV*proporcion*100) / 100
+	devolucion.IDProducto = entrada.IDProducto
+	devolucion.Proveedor = entrada.Proveedor
+	if err := s.repo.Create(devolucion); err != nil {
+		return nil, err
+	}
+	return devolucion, nil
+}
+
+// Liquidar registra la nota de crédito o el reembolso del proveedor. Los reembolsos
+// entran a caja como un ingreso del día en que se reciben.
+func (s *devolucionProveedorService) Liquidar(id int, liquidacion *domain.LiquidacionProveedor) (*domain.DevolucionProveedorConProducto, error) {
+	devolucion, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if devolucion.Estado != domain.EstadoDevolucionPendiente {
+		return nil, &domain.ErrValidation{Field: "estado", Message: "la devolución ya fue liquidada"}
+	}
+	liquidacion.Monto = math.Round(liquidacion.Monto*100) / 100
+	if liquidacion.Monto < 0 {
+		return nil, &domain.ErrValidation{Field: "monto", Message: "no puede ser negativo"}
+	}
+	liquidacion.UsuarioRegistro = strings.TrimSpace(liquidacion.UsuarioRegistro)
+	if liquidacion.UsuarioRegistro == "" {
+		return nil, &domain.ErrValidation{Field: "usuario_registro", Message: "es requerido"}
+	}
+	liquidacion.NumeroNota = strings.ToUpper(strings.TrimSpace(liquidacion.NumeroNota))
+	if devolucion.TipoCredito == domain.TipoCreditoNotaCredito && liquidacion.NumeroNota == "" {
+		return nil, &domain.ErrValidation{Field: "numero_nota", Message: "es requerido para liquidar una nota de crédito"}
+	}
+	if liquidacion.Fecha.Before(devolucion.Fecha) {
+		return nil, &domain.ErrValidation{Field: "fecha", Message: "no puede ser anterior a la devolución"}
+	}
+	var ingreso *domain.ControlDiario
+	if devolucion.TipoCredito == domain.TipoCreditoReembolso && liquidacion.Monto > 0 {
+		proveedor := devolucion.Proveedor
+		if proveedor == "" {
+			proveedor = "SIN PROVEEDOR"
+		}
+		ingreso = &domain.ControlDiario{
+			Fecha:           liquidacion.Fecha,
+			Descripcion:     "REEMBOLSO PROVEEDOR - " + proveedor,
+			MontoEntrada:    liquidacion.Monto,
+			Observaciones:   fmt.Sprintf("Devolución a proveedor #%d: %d x %s", devolucion.ID, devolucion.Cantidad, devolucion.NombreProducto),
+			UsuarioRegistro: liquidacion.UsuarioRegistro,
+		}
+	}
+	if err := s.repo.Liquidar(id, liquidacion, ingreso); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *devolucionProveedorService) entrada(id int) (*domain.EntradaConProducto, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id_entrada", Message: "debe ser mayor a 0"}
+	}
+	return s.entradaRepo.GetByID(id)
+}
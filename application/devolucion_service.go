@@ -0,0 +1,166 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type DevolucionService interface {
+	GetAll(rango domain.RangoFechas) ([]domain.DevolucionConProducto, error)
+	GetByID(id int) (*domain.DevolucionConProducto, error)
+	GetBySalida(salidaID int) ([]domain.DevolucionConProducto, error)
+	Create(devolucion *domain.DevolucionCliente, monto *float64) (*domain.DevolucionCliente, error)
+}
+
+type devolucionService struct {
+	repo         domain.DevolucionRepository
+	salidaRepo   domain.SalidaProductoRepository
+	productoRepo domain.ProductoRepository
+	clienteRepo  domain.ClienteRepository
+}
+
+func NewDevolucionService(repo domain.DevolucionRepository, salidaRepo domain.SalidaProductoRepository, productoRepo domain.ProductoRepository, clienteRepo domain.ClienteRepository) DevolucionService {
+	return &devolucionService{repo: repo, salidaRepo: salidaRepo, productoRepo: productoRepo, clienteRepo: clienteRepo}
+}
+
+func (s *devolucionService) GetAll(rango domain.RangoFechas) ([]domain.DevolucionConProducto, error) {
+	if err := validarRangoFechas(rango); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(rango)
+}
+
+func (s *devolucionService) GetByID(id int) (*domain.DevolucionConProducto, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *devolucionService) GetBySalida(salidaID int) ([]domain.DevolucionConProducto, error) {
+	if _, err := s.salida(salidaID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBySalida(salidaID)
+}
+
+// Create registra la devolución de parte de una salida. Sin monto se reembolsa la parte
+// proporcional de lo cobrado; el total reembolsado nunca supera el total de la salida.
+func (s *devolucionService) Create(devolucion *domain.DevolucionCliente, monto *float64) (*domain.DevolucionCliente, error) {
+	if devolucion.Cantidad <= 0 {
+		return nil, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
+	}
+	devolucion.UsuarioRegistro = strings.TrimSpace(devolucion.UsuarioRegistro)
+	if devolucion.UsuarioRegistro == "" {
+		return nil, &domain.ErrValidation{Field: "usuario_registro", Message: "es requerido"}
+	}
+	devolucion.Destino = strings.ToUpper(strings.TrimSpace(devolucion.Destino))
+	if devolucion.Destino == "" {
+		devolucion.Destino = domain.DestinoDevolucionStock
+	}
+	if devolucion.Destino != domain.DestinoDevolucionStock && devolucion.Destino != domain.DestinoDevolucionDanado {
+		return nil, &domain.ErrValidation{Field: "destino", Message: "debe ser STOCK o DANADO"}
+	}
+	devolucion.MetodoReembolso = strings.TrimSpace(devolucion.MetodoReembolso)
+	devolucion.Motivo = strings.TrimSpace(devolucion.Motivo)
+
+	salida, err := s.salida(devolucion.IDSalida)
+	if err != nil {
+		return nil, err
+	}
+	if devolucion.Fecha.Before(salida.FechaSalida) {
+		return nil, &domain.ErrValidation{Field: "fecha", Message: "no puede ser anterior a la venta"}
+	}
+	devolucion.IDProducto = salida.IDProducto
+	devuelto, err := s.repo.GetDevuelto(salida.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pendiente := salida.Cantidad - devuelto.Cantidad; devolucion.Cantidad > pendiente {
+		return nil, &domain.ErrValidation{Field: "cantidad", Message: fmt.Sprintf("supera lo vendido pendiente de devolver (%d de %d)", pendiente, salida.Cantidad)}
+	}
+	maximo, sugerido := domain.Reembolsable(&salida.SalidaProducto, devuelto, devolucion.Cantidad)
+	devolucion.MontoReembolso = sugerido
+	if monto != nil {
+		devolucion.MontoReembolso = math.Round(*monto*100) / 100
+	}
+	if devolucion.MontoReembolso < 0 {
+		return nil, &domain.ErrValidation{Field: "monto_reembolso", Message: "no puede ser negativo"}
+	}
+	if devolucion.MontoReembolso > maximo {
+		return nil, &domain.ErrValidation{Field: "monto_reembolso", Message: fmt.Sprintf("supera lo que queda por reembolsar de la venta (%.2f)", maximo)}
+	}
+	impuesto := domain.DesglosarIGV(salida.Afectacion, salida.Tasa, devolucion.MontoReembolso)
+	devolucion.BaseImponible, devolucion.IGV = impuesto.BaseImponible, impuesto.IGV
+
+	reembolso, err := s.prepararReembolso(devolucion, salida)
+	if err != nil {
+		return nil, err
+	}
+	var componentes []domain.ComponenteKit
+	if devolucion.Destino == domain.DestinoDevolucionStock {
+		producto, err := s.productoRepo.GetByID(salida.IDProducto)
+		if err != nil {
+			return nil, err
+		}
+		// El kit vuelve al stock de sus componentes según su lista de materiales actual
+		if producto.EsKit() {
+			if componentes, err = s.productoRepo.GetComponentes(producto.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.repo.Create(devolucion, componentes, reembolso); err != nil {
+		return nil, err
+	}
+	return devolucion, nil
+}
+
+func (s *devolucionService) salida(id int) (*domain.SalidaConProducto, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id_salida", Message: "debe ser mayor a 0"}
+	}
+	return s.salidaRepo.GetByID(id)
+}
+
+// prepararReembolso decide de dónde sale el dinero. Con método CREDITO se descuenta de
+// la cuenta por cobrar de la venta; si no, se retorna el egreso de caja, registrado
+// como ingreso negativo para que el control diario muestre las ventas netas.
+func (s *devolucionService) prepararReembolso(devolucion *domain.DevolucionCliente, salida *domain.SalidaConProducto) (*domain.ControlDiario, error) {
+	referencia := salida.Comprobante
+	if referencia == "" {
+		referencia = fmt.Sprintf("salida #%d", salida.ID)
+	}
+	if strings.EqualFold(devolucion.MetodoReembolso, domain.TipoPagoCredito) {
+		devolucion.MetodoReembolso = domain.TipoPagoCredito
+		if !strings.EqualFold(salida.TipoPago, domain.TipoPagoCredito) || salida.IDComprobante == nil {
+			return nil, &domain.ErrValidation{Field: "metodo_reembolso", Message: "CREDITO solo aplica a ventas al crédito"}
+		}
+		cuenta, err := s.clienteRepo.GetCuentaByComprobante(*salida.IDComprobante)
+		if err != nil {
+			return nil, err
+		}
+		if devolucion.MontoReembolso > cuenta.Saldo {
+			return nil, &domain.ErrValidation{Field: "monto_reembolso", Message: fmt.Sprintf("supera el saldo pendiente de la cuenta (%.2f)", cuenta.Saldo)}
+		}
+		devolucion.IDCuenta = &cuenta.ID
+		return nil, nil
+	}
+	if devolucion.MontoReembolso == 0 {
+		return nil, nil
+	}
+	observaciones := fmt.Sprintf("%d x %s", devolucion.Cantidad, salida.NombreProducto)
+	if devolucion.MetodoReembolso != "" {
+		observaciones += " - " + devolucion.MetodoReembolso
+	}
+	return &domain.ControlDiario{
+		Fecha:           devolucion.Fecha,
+		Descripcion:     "DEVOLUCION - " + referencia,
+		MontoEntrada:    -devolucion.MontoReembolso,
+		Observaciones:   observaciones,
+		UsuarioRegistro: devolucion.UsuarioRegistro,
+	}, nil
+}
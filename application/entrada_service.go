@@ -1,6 +1,7 @@
 package application
 
 import (
+	"math"
 	"strings"
 	"time"
 
@@ -8,7 +9,7 @@ import (
 )
 
 type EntradaProductoService interface {
-	GetAll() ([]domain.EntradaConProducto, error)
+	GetAll(filtro domain.FiltroEntradas) ([]domain.EntradaConProducto, int, error)
 	GetByID(id int) (*domain.EntradaConProducto, error)
 	GetByProductoID(productoID int) ([]domain.EntradaConProducto, error)
 	GetByFecha(fecha string) ([]domain.EntradaConProducto, error)
@@ -18,14 +19,26 @@ type EntradaProductoService interface {
 type entradaProductoService struct {
 	entradaRepo  domain.EntradaProductoRepository
 	productoRepo domain.ProductoRepository
+	unidadRepo   domain.UnidadMedidaRepository
 }
 
-func NewEntradaProductoService(entradaRepo domain.EntradaProductoRepository, productoRepo domain.ProductoRepository) EntradaProductoService {
-	return &entradaProductoService{entradaRepo: entradaRepo, productoRepo: productoRepo}
+func NewEntradaProductoService(entradaRepo domain.EntradaProductoRepository, productoRepo domain.ProductoRepository, unidadRepo domain.UnidadMedidaRepository) EntradaProductoService {
+	return &entradaProductoService{entradaRepo: entradaRepo, productoRepo: productoRepo, unidadRepo: unidadRepo}
 }
 
-func (s *entradaProductoService) GetAll() ([]domain.EntradaConProducto, error) {
-	return s.entradaRepo.GetAll()
+func (s *entradaProductoService) GetAll(filtro domain.FiltroEntradas) ([]domain.EntradaConProducto, int, error) {
+	if err := validarRangoFechas(filtro.RangoFechas); err != nil {
+		return nil, 0, err
+	}
+	if err := validarOrden(&filtro.Orden, domain.CamposOrdenEntradas); err != nil {
+		return nil, 0, err
+	}
+	if err := filtro.Paginacion.Normalizar(); err != nil {
+		return nil, 0, err
+	}
+	filtro.Usuario = strings.TrimSpace(filtro.Usuario)
+	filtro.Proveedor = strings.TrimSpace(filtro.Proveedor)
+	return s.entradaRepo.GetAll(filtro)
 }
 
 func (s *entradaProductoService) GetByID(id int) (*domain.EntradaConProducto, error) {
@@ -53,9 +66,6 @@ func (s *entradaProductoService) GetByFecha(fecha string) ([]domain.EntradaConPr
 }
 
 func (s *entradaProductoService) Create(entrada *domain.EntradaProducto) (*domain.EntradaProducto, error) {
-	if entrada.IDProducto <= 0 {
-		return nil, &domain.ErrValidation{Field: "id_producto", Message: "debe ser mayor a 0"}
-	}
 	if entrada.Cantidad <= 0 {
 		return nil, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
 	}
@@ -65,10 +75,30 @@ func (s *entradaProductoService) Create(entrada *domain.EntradaProducto) (*domai
 	if entrada.PrecioUnitario != nil && *entrada.PrecioUnitario < 0 {
 		return nil, &domain.ErrValidation{Field: "precio_unitario", Message: "no puede ser negativo"}
 	}
-	producto, err := s.productoRepo.GetByID(entrada.IDProducto)
+	producto, err := resolverProducto(s.productoRepo, entrada.IDProducto, entrada.CodigoBarras)
 	if err != nil {
 		return nil, err
 	}
+	if producto.EsKit() {
+		return nil, &domain.ErrValidation{Field: "id_producto", Message: "los kits no reciben entradas; registre la entrada de sus componentes"}
+	}
+	entrada.IDProducto = producto.ID
+	// Se compra por caja o paquete: el stock y el costo se llevan por unidad base
+	cantidad, factor, err := convertirCantidad(s.unidadRepo, producto, entrada.Unidad, entrada.Cantidad)
+	if err != nil {
+		return nil, err
+	}
+	// Sin precio no hay importe que desglosar: solo queda la afectación. El importe se
+	// calcula con el precio y la cantidad enviados, antes de pasarlos a la unidad base;
+	// es lo que suman los reportes de compras.
+	entrada.Impuesto = domain.Impuesto{Afectacion: producto.AfectacionIGV}
+	if entrada.PrecioUnitario != nil {
+		entrada.Impuesto = domain.DesglosarIGV(producto.AfectacionIGV, producto.TasaIGV, *entrada.PrecioUnitario*float64(entrada.Cantidad))
+		precio := math.Round(*entrada.PrecioUnitario/float64(factor)*100) / 100
+		entrada.PrecioUnitario = &precio
+	}
+	entrada.Cantidad = cantidad
+	entrada.Proveedor = strings.TrimSpace(entrada.Proveedor)
 	entrada.Observaciones = strings.TrimSpace(entrada.Observaciones)
 	entrada.UsuarioRegistro = strings.TrimSpace(entrada.UsuarioRegistro)
 	if err := s.entradaRepo.Create(entrada); err != nil {
@@ -0,0 +1,63 @@
+package application
+
+import "github.com/Mishka-GDI-Back/domain"
+
+const maxEtiquetasHoja = 1000
+
+type EtiquetasService interface {
+	GetEtiqueta(productoID int, simbologia string) (*domain.Etiqueta, error)
+	GetEtiquetas(solicitudes []domain.SolicitudEtiqueta, simbologia string) ([]domain.Etiqueta, error)
+}
+
+type etiquetasService struct {
+	productoRepo     domain.ProductoRepository
+	codigoBarrasRepo domain.CodigoBarrasRepository
+}
+
+func NewEtiquetasService(productoRepo domain.ProductoRepository, codigoBarrasRepo domain.CodigoBarrasRepository) EtiquetasService {
+	return &etiquetasService{productoRepo: productoRepo, codigoBarrasRepo: codigoBarrasRepo}
+}
+
+func (s *etiquetasService) GetEtiqueta(productoID int, simbologia string) (*domain.Etiqueta, error) {
+	if productoID <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	producto, err := s.productoRepo.GetByID(productoID)
+	if err != nil {
+		return nil, err
+	}
+	codigos, err := s.codigoBarrasRepo.GetByProductoID(productoID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NuevaEtiqueta(producto, codigos, simbologia)
+}
+
+// GetEtiquetas arma la lista de etiquetas repitiendo cada producto según sus copias
+func (s *etiquetasService) GetEtiquetas(solicitudes []domain.SolicitudEtiqueta, simbologia string) ([]domain.Etiqueta, error) {
+	if len(solicitudes) == 0 {
+		return nil, &domain.ErrValidation{Field: "productos", Message: "debe indicar al menos un producto"}
+	}
+	total := 0
+	for _, sol := range solicitudes {
+		if sol.Copias < 0 {
+			return nil, &domain.ErrValidation{Field: "copias", Message: "no puede ser negativo"}
+		}
+		// Se compara cada solicitud antes de sumar para que el total no desborde
+		if sol.Copias > maxEtiquetasHoja || total+max(sol.Copias, 1) > maxEtiquetasHoja {
+			return nil, &domain.ErrValidation{Field: "copias", Message: "se permiten hasta 1000 etiquetas por solicitud"}
+		}
+		total += max(sol.Copias, 1)
+	}
+	etiquetas := make([]domain.Etiqueta, 0, total)
+	for _, sol := range solicitudes {
+		e, err := s.GetEtiqueta(sol.IDProducto, simbologia)
+		if err != nil {
+			return nil, err
+		}
+		for range max(sol.Copias, 1) {
+			etiquetas = append(etiquetas, *e)
+		}
+	}
+	return etiquetas, nil
+}
@@ -0,0 +1,36 @@
+package application
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+func validarOrden(o *domain.Orden, permitidos []string) error {
+	o.Campo = strings.ToLower(strings.TrimSpace(o.Campo))
+	if o.Campo == "" || slices.Contains(permitidos, o.Campo) {
+		return nil
+	}
+	return &domain.ErrValidation{Field: "orden", Message: "use uno de: " + strings.Join(permitidos, ", ")}
+}
+
+func validarRangoFechas(r domain.RangoFechas) error {
+	var desde, hasta time.Time
+	var err error
+	if r.Desde != "" {
+		if desde, err = time.Parse("2006-01-02", r.Desde); err != nil {
+			return &domain.ErrValidation{Field: "desde", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+	}
+	if r.Hasta != "" {
+		if hasta, err = time.Parse("2006-01-02", r.Hasta); err != nil {
+			return &domain.ErrValidation{Field: "hasta", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+	}
+	if r.Desde != "" && r.Hasta != "" && hasta.Before(desde) {
+		return &domain.ErrValidation{Field: "hasta", Message: "no puede ser anterior a desde"}
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+// Importar crea o actualiza productos por código desde las filas de un archivo. Cada
+// fila pasa por las mismas validaciones que el alta y la edición; las categorías que no
+// existen se crean. Si alguna fila tiene errores, o si es una simulación, no se aplica
+// nada y el resultado indica qué pasaría con cada fila.
+func (s *productoService) Importar(filas []domain.FilaImportacion, simular bool, usuario string) (*domain.ResultadoImportacion, error) {
+	if len(filas) == 0 {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "no tiene filas de productos"}
+	}
+	if len(filas) > domain.MaximoFilasImportacion {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: fmt.Sprintf("no puede tener más de %d filas", domain.MaximoFilasImportacion)}
+	}
+	arbol, err := arbolCategorias(s.categoriaRepo)
+	if err != nil {
+		return nil, err
+	}
+	precarga, err := s.precargarImportacion(filas)
+	if err != nil {
+		return nil, err
+	}
+	categorias := &planCategorias{arbol: arbol, planeadas: make(map[string]string)}
+	resultado := &domain.ResultadoImportacion{Simulacion: simular}
+	importacion := &domain.ImportacionProductos{Usuario: strings.TrimSpace(usuario)}
+	filaPorCodigo := make(map[string]int)
+	// filaDe ubica el resultado de cada producto a importar para completar su ID al final
+	var filaDe []int
+	for _, fila := range filas {
+		r := domain.ResultadoFilaImportacion{Numero: fila.Numero, Codigo: strings.TrimSpace(fila.Valores[domain.ColumnaCodigo])}
+		item, errores, err := s.prepararFila(fila, precarga, categorias)
+		if err != nil {
+			return nil, err
+		}
+		if anterior, repetido := filaPorCodigo[r.Codigo]; repetido && r.Codigo != "" {
+			errores = append(errores, fmt.Sprintf("codigo: repetido, ya aparece en la fila %d", anterior))
+		} else {
+			filaPorCodigo[r.Codigo] = fila.Numero
+		}
+		r.Errores = errores
+		switch {
+		case len(errores) > 0:
+			resultado.ConErrores++
+		case item.Producto.ID > 0:
+			r.Accion, r.IDProducto = domain.AccionActualizar, &item.Producto.ID
+			resultado.Actualizados++
+		default:
+			r.Accion = domain.AccionCrear
+			resultado.Creados++
+		}
+		if len(errores) == 0 {
+			importacion.Productos = append(importacion.Productos, *item)
+			filaDe = append(filaDe, len(resultado.Filas))
+		}
+		resultado.Filas = append(resultado.Filas, r)
+	}
+	for _, c := range categorias.nuevas {
+		resultado.CategoriasNuevas = append(resultado.CategoriasNuevas, c.Ruta)
+	}
+	if simular || resultado.ConErrores > 0 {
+		return resultado, nil
+	}
+
+	importacion.Categorias = categorias.nuevas
+	if err := s.repo.Importar(importacion); err != nil {
+		if strings.Contains(err.Error(), "unique") || strings.Contains(err.Error(), "duplicate") {
+			return nil, &domain.ErrValidation{Field: "archivo", Message: "otro usuario registró un código o una categoría del archivo durante la importación; vuelva a intentarlo"}
+		}
+		return nil, err
+	}
+	// Los productos creados recién tienen ID después de aplicar
+	for i := range importacion.Productos {
+		resultado.Filas[filaDe[i]].IDProducto = &importacion.Productos[i].Producto.ID
+	}
+	resultado.Aplicada = true
+	return resultado, nil
+}
+
+// precargaImportacion reúne en pocas consultas lo que cada fila necesita consultar
+type precargaImportacion struct {
+	existentes   map[string]domain.Producto // por código
+	conVariantes map[int]bool
+	unidades     map[string]bool
+}
+
+func (s *productoService) precargarImportacion(filas []domain.FilaImportacion) (*precargaImportacion, error) {
+	codigos := make([]string, 0, len(filas))
+	for _, fila := range filas {
+		if codigo := strings.TrimSpace(fila.Valores[domain.ColumnaCodigo]); codigo != "" {
+			codigos = append(codigos, codigo)
+		}
+	}
+	existentes, err := s.repo.GetByCodigos(codigos)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(existentes))
+	for _, p := range existentes {
+		ids = append(ids, p.ID)
+	}
+	conVariantes, err := s.repo.GetPadresConVariantes(ids)
+	if err != nil {
+		return nil, err
+	}
+	catalogo, err := s.unidadRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	unidades := make(map[string]bool, len(catalogo))
+	for _, u := range catalogo {
+		unidades[domain.NormalizarUnidad(u.Codigo)] = true
+	}
+	return &precargaImportacion{existentes: existentes, conVariantes: conVariantes, unidades: unidades}, nil
+}
+
+// prepararFila arma el producto a crear o actualizar y retorna los errores de la fila.
+// El error solo se retorna si falla la consulta, no por datos inválidos.
+func (s *productoService) prepararFila(fila domain.FilaImportacion, precarga *precargaImportacion, categorias *planCategorias) (*domain.ProductoImportado, []string, error) {
+	valor := func(columna string) string { return strings.TrimSpace(fila.Valores[columna]) }
+	var errores []string
+	agregar := func(err error) { errores = append(errores, describirError(err)) }
+
+	codigo := valor(domain.ColumnaCodigo)
+	if codigo == "" {
+		return nil, []string{"codigo: es requerido"}, nil
+	}
+	var existente *domain.Producto
+	if encontrado, ok := precarga.existentes[codigo]; ok {
+		existente = &encontrado
+	}
+	item := &domain.ProductoImportado{}
+	p := &item.Producto
+	var err error
+	if existente != nil {
+		if !existente.Activo {
+			return nil, []string{fmt.Sprintf("codigo: pertenece al producto dado de baja #%d; restáurelo antes de importarlo", existente.ID)}, nil
+		}
+		*p = *existente
+	} else {
+		p.Codigo, p.Tipo = codigo, domain.TipoProductoSimple
+	}
+
+	if v := valor(domain.ColumnaNombre); v != "" {
+		p.Nombre = v
+	}
+	if v := valor(domain.ColumnaUnidadMedida); v != "" {
+		p.UnidadMedida = v
+	}
+	if v := valor(domain.ColumnaAfectacionIGV); v != "" {
+		// Sin tasa se usa la de la afectación indicada
+		p.AfectacionIGV, p.TasaIGV = v, 0
+	}
+	if v := valor(domain.ColumnaTasaIGV); v != "" {
+		if p.TasaIGV, err = leerDecimal(domain.ColumnaTasaIGV, v); err != nil {
+			agregar(err)
+		}
+	}
+	if v := valor(domain.ColumnaPrecioUnitario); v != "" {
+		if p.PrecioUnitario, err = leerDecimal(domain.ColumnaPrecioUnitario, v); err != nil {
+			agregar(err)
+		}
+		item.ActualizarPrecio = true
+	}
+	if v := valor(domain.ColumnaStockActual); v != "" {
+		if p.StockActual, err = leerEntero(domain.ColumnaStockActual, v); err != nil {
+			agregar(err)
+		}
+		item.ActualizarStock = true
+	}
+	if v := valor(domain.ColumnaStockInicial); v != "" {
+		if p.StockInicial, err = leerEntero(domain.ColumnaStockInicial, v); err != nil {
+			agregar(err)
+		}
+	} else if existente == nil {
+		p.StockInicial = p.StockActual
+	}
+	if existente != nil && (item.ActualizarStock || valor(domain.ColumnaStockInicial) != "") {
+		if existente.EsKit() || precarga.conVariantes[existente.ID] {
+			agregar(&domain.ErrValidation{Field: domain.ColumnaStockActual, Message: "el producto es un kit o tiene variantes; su stock no se importa"})
+		}
+	}
+	if existente != nil && existente.EsKit() {
+		// El stock leído de un kit es calculado; su fila no lleva stock
+		p.StockActual, p.StockInicial = 0, 0
+	}
+	if v := valor(domain.ColumnaCategoria); v != "" {
+		if existente != nil && existente.EsVariante() {
+			agregar(&domain.ErrValidation{Field: domain.ColumnaCategoria, Message: "una variante usa la categoría de su producto padre"})
+		} else if id, ruta, err := categorias.resolver(v, p.IDCategoria); err != nil {
+			agregar(err)
+		} else {
+			p.IDCategoria, item.RutaCategoria = id, ruta
+		}
+	}
+	if err := normalizarDatos(p); err != nil {
+		agregar(err)
+	} else if !precarga.unidades[p.UnidadMedida] {
+		agregar(unidadInexistente("unidad_medida", p.UnidadMedida))
+	} else if existente != nil {
+		if err := s.validarCambioUnidad(existente.ID, existente.UnidadMedida, p.UnidadMedida); err != nil {
+			var validation *domain.ErrValidation
+			if !errors.As(err, &validation) {
+				return nil, nil, err
+			}
+			agregar(err)
+		}
+	}
+	return item, errores, nil
+}
+
+// describirError deja el mensaje de la fila como "campo: mensaje"
+func describirError(err error) string {
+	var validation *domain.ErrValidation
+	if errors.As(err, &validation) {
+		return validation.Field + ": " + validation.Message
+	}
+	return err.Error()
+}
+
+// leerDecimal acepta coma o punto decimal (12,50 o 12.50), no separadores de miles
+func leerDecimal(columna, valor string) (float64, error) {
+	if !strings.Contains(valor, ".") {
+		valor = strings.Replace(valor, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(valor, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, &domain.ErrValidation{Field: columna, Message: fmt.Sprintf("%q no es un número", valor)}
+	}
+	if n < 0 {
+		return 0, &domain.ErrValidation{Field: columna, Message: "no puede ser negativo"}
+	}
+	return n, nil
+}
+
+// leerEntero acepta también "12.0", que es como las hojas de cálculo suelen guardar los enteros
+func leerEntero(columna, valor string) (int, error) {
+	n, err := leerDecimal(columna, valor)
+	if err != nil {
+		return 0, err
+	}
+	if n != float64(int(n)) {
+		return 0, &domain.ErrValidation{Field: columna, Message: "debe ser un número entero"}
+	}
+	return int(n), nil
+}
+
+// planCategorias resuelve las categorías del archivo contra el árbol y acumula las que
+// hay que crear, sin repetirlas aunque varias filas las nombren
+type planCategorias struct {
+	arbol     *domain.ArbolCategorias
+	nuevas    []domain.CategoriaImportada
+	planeadas map[string]string // ruta en mayúsculas -> ruta como se creará
+}
+
+// resolver acepta el nombre de una categoría o su ruta ("Bebidas > Gaseosas"). Retorna
+// el ID si existe o, si hay que crearla, la ruta con que se identifica. 'actual' es la
+// categoría que ya tiene el producto, que puede conservar aunque esté dada de baja.
+func (c *planCategorias) resolver(texto string, actual *int) (*int, string, error) {
+	partes := strings.Split(texto, ">")
+	for i := range partes {
+		partes[i] = strings.TrimSpace(partes[i])
+		if partes[i] == "" {
+			return nil, "", &domain.ErrValidation{Field: domain.ColumnaCategoria, Message: fmt.Sprintf("ruta de categoría inválida: %q", texto)}
+		}
+	}
+	var nodo *domain.NodoCategoria
+	if len(partes) == 1 {
+		// Un nombre suelto se busca en todo el árbol para no obligar a escribir la ruta
+		coincidencias := c.buscarEnArbol(partes[0], c.arbol.Raices())
+		if len(coincidencias) > 1 {
+			return nil, "", &domain.ErrValidation{Field: domain.ColumnaCategoria, Message: fmt.Sprintf("hay varias categorías %q; indique la ruta, p. ej. %q", partes[0], c.arbol.Ruta(coincidencias[0].ID))}
+		}
+		if len(coincidencias) == 0 {
+			// Puede ser la última de una ruta que otra fila ya manda crear
+			for _, nueva := range c.nuevas {
+				if strings.EqualFold(nueva.Nombre, partes[0]) {
+					return nil, nueva.Ruta, nil
+				}
+			}
+			return nil, c.planear(nil, partes), nil
+		}
+		nodo = coincidencias[0]
+	} else {
+		hijos := c.arbol.Raices()
+		for i, nombre := range partes {
+			siguiente := buscarNodo(hijos, nombre)
+			if siguiente == nil {
+				return nil, c.planear(nodo, partes[i:]), nil
+			}
+			nodo, hijos = siguiente, siguiente.Subcategorias
+		}
+	}
+	if !nodo.Activo && (actual == nil || *actual != nodo.ID) {
+		return nil, "", &domain.ErrValidation{Field: domain.ColumnaCategoria, Message: fmt.Sprintf("la categoría %s está dada de baja", c.arbol.Ruta(nodo.ID))}
+	}
+	return &nodo.ID, "", nil
+}
+
+// planear agrega las categorías de 'nombres' (cada una hija de la anterior) debajo de
+// 'padre' y retorna la ruta de la última
+func (c *planCategorias) planear(padre *domain.NodoCategoria, nombres []string) string {
+	var idPadre *int
+	prefijo, rutaPadre := "", ""
+	if padre != nil {
+		idPadre, prefijo = &padre.ID, c.arbol.Ruta(padre.ID)+" > "
+	}
+	for _, nombre := range nombres {
+		ruta := prefijo + nombre
+		if rutaPadre != "" {
+			ruta = rutaPadre + " > " + nombre
+		}
+		clave := strings.ToUpper(ruta)
+		if planeada, ok := c.planeadas[clave]; ok {
+			ruta = planeada
+		} else {
+			c.planeadas[clave] = ruta
+			nueva := domain.CategoriaImportada{Ruta: ruta, Nombre: nombre, RutaPadre: rutaPadre}
+			if rutaPadre == "" {
+				nueva.IDPadre = idPadre
+			}
+			c.nuevas = append(c.nuevas, nueva)
+		}
+		rutaPadre = ruta
+	}
+	return rutaPadre
+}
+
+func (c *planCategorias) buscarEnArbol(nombre string, nodos []*domain.NodoCategoria) []*domain.NodoCategoria {
+	var encontrados []*domain.NodoCategoria
+	for _, n := range nodos {
+		if strings.EqualFold(n.Nombre, nombre) {
+			encontrados = append(encontrados, n)
+		}
+		encontrados = append(encontrados, c.buscarEnArbol(nombre, n.Subcategorias)...)
+	}
+	return encontrados
+}
+
+// buscarNodo prefiere la categoría activa si hay una activa y otra dada de baja con el
+// mismo nombre
+func buscarNodo(nodos []*domain.NodoCategoria, nombre string) *domain.NodoCategoria {
+	var encontrado *domain.NodoCategoria
+	for _, n := range nodos {
+		if strings.EqualFold(n.Nombre, nombre) && (encontrado == nil || !encontrado.Activo) {
+			encontrado = n
+		}
+	}
+	return encontrado
+}
@@ -0,0 +1,230 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type PrecioService interface {
+	GetHistorial(productoID int) (*domain.Producto, []domain.CambioPrecio, error)
+	GetProgramados(productoID int) (*domain.Producto, []domain.PrecioProgramado, error)
+	Programar(productoID int, precio float64, fechaVigencia time.Time, usuario string) (*domain.PrecioProgramado, error)
+	Cancelar(productoID, id int) error
+	GetPrecioEnFecha(productoID int, fecha time.Time) (*domain.Producto, float64, error)
+	AplicarProgramados(hasta time.Time) ([]domain.CambioPrecio, error)
+	// ActualizarMasivo cambia el precio de varios productos a la vez; con simular solo
+	// retorna los precios anteriores y nuevos
+	ActualizarMasivo(actualizacion *domain.ActualizacionMasivaPrecios, simular bool) ([]domain.CambioMasivoPrecio, error)
+}
+
+type precioService struct {
+	repo         domain.PrecioRepository
+	productoRepo domain.ProductoRepository
+}
+
+func NewPrecioService(repo domain.PrecioRepository, productoRepo domain.ProductoRepository) PrecioService {
+	return &precioService{repo: repo, productoRepo: productoRepo}
+}
+
+func (s *precioService) producto(productoID int) (*domain.Producto, error) {
+	if productoID <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.productoRepo.GetByID(productoID)
+}
+
+func (s *precioService) GetHistorial(productoID int) (*domain.Producto, []domain.CambioPrecio, error) {
+	producto, err := s.producto(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	historial, err := s.repo.GetHistorial(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return producto, historial, nil
+}
+
+func (s *precioService) GetProgramados(productoID int) (*domain.Producto, []domain.PrecioProgramado, error) {
+	producto, err := s.producto(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	programados, err := s.repo.GetProgramados(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return producto, programados, nil
+}
+
+func (s *precioService) Programar(productoID int, precio float64, fechaVigencia time.Time, usuario string) (*domain.PrecioProgramado, error) {
+	if _, err := s.producto(productoID); err != nil {
+		return nil, err
+	}
+	if precio < 0 {
+		return nil, &domain.ErrValidation{Field: "precio_nuevo", Message: "no puede ser negativo"}
+	}
+	// Los cambios de hoy se hacen actualizando el producto
+	if !fechaVigencia.After(time.Now()) {
+		return nil, &domain.ErrValidation{Field: "fecha_vigencia", Message: "debe ser una fecha futura"}
+	}
+	programados, err := s.repo.GetProgramados(productoID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range programados {
+		if p.Estado == domain.EstadoProgramadoPendiente && p.FechaVigencia.Format("2006-01-02") == fechaVigencia.Format("2006-01-02") {
+			return nil, &domain.ErrDuplicate{Entity: "cambio de precio programado", Field: "fecha_vigencia", Value: fechaVigencia.Format("2006-01-02")}
+		}
+	}
+	programado := &domain.PrecioProgramado{
+		IDProducto:    productoID,
+		PrecioNuevo:   math.Round(precio*100) / 100,
+		FechaVigencia: fechaVigencia,
+		Usuario:       strings.TrimSpace(usuario),
+	}
+	if err := s.repo.CreateProgramado(programado); err != nil {
+		return nil, err
+	}
+	return programado, nil
+}
+
+func (s *precioService) Cancelar(productoID, id int) error {
+	programado, err := s.repo.GetProgramadoByID(id)
+	if err != nil {
+		return err
+	}
+	if programado.IDProducto != productoID {
+		return &domain.ErrNotFound{Entity: "cambio de precio programado", ID: id}
+	}
+	if programado.Estado != domain.EstadoProgramadoPendiente {
+		return &domain.ErrValidation{Field: "estado", Message: "solo se pueden cancelar cambios pendientes"}
+	}
+	return s.repo.CancelarProgramado(id)
+}
+
+func (s *precioService) GetPrecioEnFecha(productoID int, fecha time.Time) (*domain.Producto, float64, error) {
+	producto, err := s.producto(productoID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fecha.Format("2006-01-02") < producto.FechaCreacion.Format("2006-01-02") {
+		return nil, 0, &domain.ErrValidation{Field: "fecha", Message: "es anterior al registro del producto"}
+	}
+	historial, err := s.repo.GetHistorial(productoID)
+	if err != nil {
+		return nil, 0, err
+	}
+	programados, err := s.repo.GetProgramados(productoID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return producto, domain.PrecioEnFecha(producto, historial, programados, fecha), nil
+}
+
+// AplicarProgramados aplica los cambios pendientes con vigencia hasta 'hasta'. Un
+// cambio que falla no detiene a los demás; los errores se retornan juntos.
+func (s *precioService) AplicarProgramados(hasta time.Time) ([]domain.CambioPrecio, error) {
+	pendientes, err := s.repo.GetPendientesHasta(hasta)
+	if err != nil {
+		return nil, err
+	}
+	var aplicados []domain.CambioPrecio
+	var errs []error
+	for i := range pendientes {
+		cambio, err := s.repo.AplicarProgramado(&pendientes[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if cambio != nil {
+			aplicados = append(aplicados, *cambio)
+		}
+	}
+	return aplicados, errors.Join(errs...)
+}
+
+const motivoActualizacionMasiva = "Actualización masiva de precios"
+
+func (s *precioService) validarActualizacionMasiva(a *domain.ActualizacionMasivaPrecios) error {
+	a.Proveedor = strings.TrimSpace(a.Proveedor)
+	a.Usuario = strings.TrimSpace(a.Usuario)
+	a.Motivo = strings.TrimSpace(a.Motivo)
+	if a.Motivo == "" {
+		a.Motivo = motivoActualizacionMasiva
+	}
+	switch a.Tipo {
+	case domain.AjustePorcentaje:
+		if a.Valor <= -100 {
+			return &domain.ErrValidation{Field: "valor", Message: "el porcentaje debe ser mayor a -100"}
+		}
+	case domain.AjusteMonto:
+	default:
+		return &domain.ErrValidation{Field: "tipo", Message: "debe ser PORCENTAJE o MONTO"}
+	}
+	if a.Valor == 0 {
+		return &domain.ErrValidation{Field: "valor", Message: "no puede ser 0"}
+	}
+	if a.Redondeo < 0 {
+		return &domain.ErrValidation{Field: "redondeo", Message: "no puede ser negativo"}
+	}
+	// Sin criterios se actualizaría todo el catálogo; se exige al menos uno
+	if a.IDCategoria == nil && a.Proveedor == "" && len(a.IDsProductos) == 0 {
+		return &domain.ErrValidation{Field: "productos", Message: "indique una categoría, un proveedor o la lista de productos"}
+	}
+	if a.IDCategoria != nil && *a.IDCategoria <= 0 {
+		return &domain.ErrValidation{Field: "id_categoria", Message: "debe ser mayor a 0"}
+	}
+	for _, id := range a.IDsProductos {
+		if id <= 0 {
+			return &domain.ErrValidation{Field: "ids_productos", Message: "los IDs deben ser mayores a 0"}
+		}
+	}
+	return nil
+}
+
+// ActualizarMasivo valida la actualización y calcula los precios nuevos. Si la lista
+// de productos es el único criterio, cada producto debe existir y estar activo; con
+// otros criterios la lista solo los acota. Ningún precio puede quedar negativo. Al
+// aplicar, el repositorio recalcula los precios con los productos bloqueados, por si
+// cambiaron desde la vista previa.
+func (s *precioService) ActualizarMasivo(a *domain.ActualizacionMasivaPrecios, simular bool) ([]domain.CambioMasivoPrecio, error) {
+	if err := s.validarActualizacionMasiva(a); err != nil {
+		return nil, err
+	}
+	productos, err := s.repo.GetParaActualizacionMasiva(a)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.IDsProductos) > 0 && a.IDCategoria == nil && a.Proveedor == "" {
+		encontrados := make(map[int]bool, len(productos))
+		for _, p := range productos {
+			encontrados[p.IDProducto] = true
+		}
+		for _, id := range a.IDsProductos {
+			if !encontrados[id] {
+				return nil, &domain.ErrNotFound{Entity: "producto activo", ID: id}
+			}
+		}
+	}
+	cambios := make([]domain.CambioMasivoPrecio, 0, len(productos))
+	for _, p := range productos {
+		p.PrecioNuevo = a.PrecioNuevo(p.PrecioAnterior)
+		if p.PrecioNuevo == p.PrecioAnterior {
+			continue
+		}
+		if p.PrecioNuevo < 0 {
+			return nil, &domain.ErrValidation{Field: "valor", Message: fmt.Sprintf("el precio de %s quedaría negativo", p.Codigo)}
+		}
+		cambios = append(cambios, p)
+	}
+	if simular || len(cambios) == 0 {
+		return cambios, nil
+	}
+	return s.repo.AplicarActualizacionMasiva(a)
+}
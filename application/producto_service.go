@@ -1,39 +1,101 @@
 package application
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/Mishka-GDI-Back/domain"
 )
 
 type ProductoService interface {
-	GetAll() ([]domain.Producto, error)
+	GetAll(filtro domain.FiltroProductos) ([]domain.Producto, int, error)
 	GetByID(id int) (*domain.Producto, error)
 	Create(producto *domain.Producto) (*domain.Producto, error)
-	Update(id int, producto *domain.Producto) (*domain.Producto, error)
+	Update(id int, producto *domain.Producto, usuario string) (*domain.Producto, error)
 	Delete(id int) error
+	Restaurar(id int) (*domain.Producto, error)
 	GetStockBajo(limite int) ([]domain.Producto, error)
-	Search(termino string) ([]domain.Producto, error)
+	Search(termino string, limite int, incluirInactivos bool) ([]domain.Producto, error)
+	GetByCodigoBarras(codigo string) (*domain.Producto, error)
+	GetCodigosBarras(productoID int) ([]domain.CodigoBarras, error)
+	AgregarCodigoBarras(productoID int, codigo, tipo string) (*domain.CodigoBarras, error)
+	EliminarCodigoBarras(productoID, id int) error
+	GetVariantes(padreID int) (*domain.Producto, []domain.Producto, error)
+	CreateVariante(padreID int, variante *domain.Producto) (*domain.Producto, error)
+	GetComponentes(kitID int) (*domain.Producto, []domain.ComponenteKit, error)
+	SetComponentes(kitID int, componentes []domain.ComponenteKit) (*domain.Producto, []domain.ComponenteKit, error)
+	Importar(filas []domain.FilaImportacion, simular bool, usuario string) (*domain.ResultadoImportacion, error)
 }
 
+const (
+	limiteBusquedaDefault = 20
+	limiteBusquedaMaximo  = 100
+)
+
 type productoService struct {
-	repo          domain.ProductoRepository
-	categoriaRepo domain.CategoriaRepository
+	repo             domain.ProductoRepository
+	categoriaRepo    domain.CategoriaRepository
+	codigoBarrasRepo domain.CodigoBarrasRepository
+	unidadRepo       domain.UnidadMedidaRepository
+	archivoRepo      domain.ArchivoRepository
 }
 
-func NewProductoService(repo domain.ProductoRepository, categoriaRepo domain.CategoriaRepository) ProductoService {
-	return &productoService{repo: repo, categoriaRepo: categoriaRepo}
+func NewProductoService(repo domain.ProductoRepository, categoriaRepo domain.CategoriaRepository, codigoBarrasRepo domain.CodigoBarrasRepository, unidadRepo domain.UnidadMedidaRepository, archivoRepo domain.ArchivoRepository) ProductoService {
+	return &productoService{repo: repo, categoriaRepo: categoriaRepo, codigoBarrasRepo: codigoBarrasRepo, unidadRepo: unidadRepo, archivoRepo: archivoRepo}
 }
 
-func (s *productoService) GetAll() ([]domain.Producto, error) {
-	return s.repo.GetAll()
+func (s *productoService) GetAll(filtro domain.FiltroProductos) ([]domain.Producto, int, error) {
+	if filtro.ClaseABC != "" {
+		filtro.ClaseABC = strings.ToUpper(strings.TrimSpace(filtro.ClaseABC))
+		if filtro.ClaseABC != domain.ClaseA && filtro.ClaseABC != domain.ClaseB && filtro.ClaseABC != domain.ClaseC {
+			return nil, 0, &domain.ErrValidation{Field: "clase_abc", Message: "debe ser A, B o C"}
+		}
+	}
+	if err := validarOrden(&filtro.Orden, domain.CamposOrdenProductos); err != nil {
+		return nil, 0, err
+	}
+	if err := filtro.Paginacion.Normalizar(); err != nil {
+		return nil, 0, err
+	}
+	productos, total, err := s.repo.GetAll(filtro)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.cargarImagenes(productos); err != nil {
+		return nil, 0, err
+	}
+	return productos, total, nil
 }
 
 func (s *productoService) GetByID(id int) (*domain.Producto, error) {
 	if id <= 0 {
 		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
 	}
-	return s.repo.GetByID(id)
+	producto, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if producto.Imagenes, err = s.archivoRepo.GetByEntidad(domain.EntidadProducto, id); err != nil {
+		return nil, err
+	}
+	return producto, nil
+}
+
+// cargarImagenes completa las imágenes de un listado con una sola consulta
+func (s *productoService) cargarImagenes(productos []domain.Producto) error {
+	ids := make([]int, len(productos))
+	for i, p := range productos {
+		ids[i] = p.ID
+	}
+	imagenes, err := s.archivoRepo.GetByEntidades(domain.EntidadProducto, ids)
+	if err != nil {
+		return err
+	}
+	for i := range productos {
+		productos[i].Imagenes = imagenes[productos[i].ID]
+	}
+	return nil
 }
 
 func (s *productoService) Create(producto *domain.Producto) (*domain.Producto, error) {
@@ -44,18 +106,30 @@ func (s *productoService) Create(producto *domain.Producto) (*domain.Producto, e
 		return nil, &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
 	}
 	if producto.IDCategoria != nil && *producto.IDCategoria > 0 {
-		if _, err := s.categoriaRepo.GetByID(*producto.IDCategoria); err != nil {
-			return nil, &domain.ErrValidation{Field: "id_categoria", Message: "la categoría especificada no existe"}
+		if err := s.validarCategoria(*producto.IDCategoria); err != nil {
+			return nil, err
 		}
 	}
 	existing, _ := s.repo.GetByCodigo(strings.TrimSpace(producto.Codigo))
+	if existing != nil && !existing.Activo {
+		return nil, &domain.ErrValidation{Field: "codigo", Message: fmt.Sprintf("pertenece al producto dado de baja #%d; restáurelo en lugar de crearlo", existing.ID)}
+	}
 	if existing != nil {
 		return nil, &domain.ErrDuplicate{Entity: "producto", Field: "codigo", Value: producto.Codigo}
 	}
-	producto.Codigo = strings.TrimSpace(producto.Codigo)
-	producto.Nombre = strings.TrimSpace(producto.Nombre)
-	if producto.UnidadMedida == "" {
-		producto.UnidadMedida = "UNIDAD"
+	if err := s.validarDatos(producto); err != nil {
+		return nil, err
+	}
+	producto.Tipo = strings.ToUpper(strings.TrimSpace(producto.Tipo))
+	switch producto.Tipo {
+	case "":
+		producto.Tipo = domain.TipoProductoSimple
+	case domain.TipoProductoSimple:
+	case domain.TipoProductoKit:
+		// El stock del kit se calcula de sus componentes
+		producto.StockActual, producto.StockInicial = 0, 0
+	default:
+		return nil, &domain.ErrValidation{Field: "tipo", Message: "debe ser SIMPLE o KIT"}
 	}
 	if err := s.repo.Create(producto); err != nil {
 		return nil, err
@@ -63,7 +137,7 @@ func (s *productoService) Create(producto *domain.Producto) (*domain.Producto, e
 	return producto, nil
 }
 
-func (s *productoService) Update(id int, producto *domain.Producto) (*domain.Producto, error) {
+func (s *productoService) Update(id int, producto *domain.Producto, usuario string) (*domain.Producto, error) {
 	if id <= 0 {
 		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
 	}
@@ -73,45 +147,166 @@ func (s *productoService) Update(id int, producto *domain.Producto) (*domain.Pro
 	if strings.TrimSpace(producto.Nombre) == "" {
 		return nil, &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
 	}
-	existing, err := s.repo.GetByID(id)
+	existing, err := s.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
-	if producto.IDCategoria != nil && *producto.IDCategoria > 0 {
-		if _, err := s.categoriaRepo.GetByID(*producto.IDCategoria); err != nil {
-			return nil, &domain.ErrValidation{Field: "id_categoria", Message: "la categoría especificada no existe"}
+	// Un producto puede seguir en una categoría dada de baja, pero no pasar a una
+	if producto.IDCategoria != nil && *producto.IDCategoria > 0 && (existing.IDCategoria == nil || *existing.IDCategoria != *producto.IDCategoria) {
+		if err := s.validarCategoria(*producto.IDCategoria); err != nil {
+			return nil, err
 		}
 	}
 	byCode, _ := s.repo.GetByCodigo(strings.TrimSpace(producto.Codigo))
 	if byCode != nil && byCode.ID != id {
 		return nil, &domain.ErrDuplicate{Entity: "producto", Field: "codigo", Value: producto.Codigo}
 	}
-	existing.Codigo = strings.TrimSpace(producto.Codigo)
-	existing.Nombre = strings.TrimSpace(producto.Nombre)
+	unidadAnterior := existing.UnidadMedida
+	existing.Codigo = producto.Codigo
+	existing.Nombre = producto.Nombre
 	existing.IDCategoria = producto.IDCategoria
 	existing.UnidadMedida = producto.UnidadMedida
-	if existing.UnidadMedida == "" {
-		existing.UnidadMedida = "UNIDAD"
+	// Sin afectación se conserva la configuración de IGV vigente
+	if producto.AfectacionIGV != "" {
+		existing.AfectacionIGV, existing.TasaIGV = producto.AfectacionIGV, producto.TasaIGV
+	}
+	if err := s.validarDatos(existing); err != nil {
+		return nil, err
+	}
+	if err := s.validarCambioUnidad(existing.ID, unidadAnterior, existing.UnidadMedida); err != nil {
+		return nil, err
 	}
 	existing.PrecioUnitario = producto.PrecioUnitario
+	if existing.EsVariante() {
+		// La variante conserva la categoría del padre; solo cambian sus atributos
+		padre, err := s.repo.GetByID(*existing.IDProductoPadre)
+		if err != nil {
+			return nil, err
+		}
+		existing.IDCategoria = padre.IDCategoria
+		if producto.Atributos != nil {
+			atributos, err := s.validarAtributos(*existing.IDProductoPadre, existing.ID, producto.Atributos)
+			if err != nil {
+				return nil, err
+			}
+			existing.Atributos = atributos
+		}
+	}
+	if existing.EsKit() {
+		// El stock leído de un kit es calculado; en su fila siempre queda en cero
+		disponible := existing.StockActual
+		existing.StockActual, existing.StockInicial = 0, 0
+		if err := s.repo.Editar(existing, strings.TrimSpace(usuario)); err != nil {
+			return nil, err
+		}
+		existing.StockActual = disponible
+		return existing, nil
+	}
 	existing.StockActual = producto.StockActual
 	existing.StockInicial = producto.StockInicial
-	if err := s.repo.Update(existing); err != nil {
+	// El cambio de stock queda en los ajustes y el de precio en el historial, en la
+	// misma transacción que la edición
+	if err := s.repo.Editar(existing, strings.TrimSpace(usuario)); err != nil {
 		return nil, err
 	}
 	return existing, nil
 }
 
-func (s *productoService) Delete(id int) error {
-	if id <= 0 {
-		return &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+// validarDatos normaliza y valida los datos comunes al crear y actualizar: código y
+// nombre, unidad de medida (UNIDAD por defecto) e IGV (gravado por defecto)
+func (s *productoService) validarDatos(producto *domain.Producto) error {
+	if err := normalizarDatos(producto); err != nil {
+		return err
+	}
+	return validarUnidad(s.unidadRepo, "unidad_medida", producto.UnidadMedida)
+}
+
+// normalizarDatos hace las validaciones de validarDatos que no consultan la base; la
+// importación revisa la unidad contra el catálogo precargado
+func normalizarDatos(producto *domain.Producto) error {
+	producto.Codigo = strings.TrimSpace(producto.Codigo)
+	producto.Nombre = strings.TrimSpace(producto.Nombre)
+	if producto.Codigo == "" {
+		return &domain.ErrValidation{Field: "codigo", Message: "es requerido"}
+	}
+	if producto.Nombre == "" {
+		return &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
+	}
+	producto.UnidadMedida = domain.NormalizarUnidad(producto.UnidadMedida)
+	if producto.UnidadMedida == "" {
+		producto.UnidadMedida = "UNIDAD"
 	}
-	if _, err := s.repo.GetByID(id); err != nil {
+	if producto.AfectacionIGV == "" {
+		producto.AfectacionIGV = domain.AfectacionGravado
+	}
+	return validarIGV(producto)
+}
+
+// validarCambioUnidad impide cambiar la unidad base de un producto que tiene
+// conversiones: sus factores se definieron contra la unidad anterior
+func (s *productoService) validarCambioUnidad(productoID int, anterior, nueva string) error {
+	if anterior == nueva {
+		return nil
+	}
+	conversiones, err := s.unidadRepo.GetConversiones(productoID)
+	if err != nil {
+		return err
+	}
+	if len(conversiones) > 0 {
+		return &domain.ErrValidation{Field: "unidad_medida", Message: fmt.Sprintf("el producto tiene conversiones definidas sobre %s; elimínelas antes de cambiar la unidad base", anterior)}
+	}
+	return nil
+}
+
+// validarCategoria exige que la categoría exista y esté activa
+func (s *productoService) validarCategoria(id int) error {
+	categoria, err := s.categoriaRepo.GetByID(id)
+	if err != nil {
+		return &domain.ErrValidation{Field: "id_categoria", Message: "la categoría especificada no existe"}
+	}
+	if !categoria.Activo {
+		return &domain.ErrValidation{Field: "id_categoria", Message: "la categoría está dada de baja"}
+	}
+	return nil
+}
+
+// Delete da de baja el producto (y sus variantes) sin borrar su historial
+func (s *productoService) Delete(id int) error {
+	producto, err := s.GetByID(id)
+	if err != nil {
 		return err
 	}
+	if !producto.Activo {
+		return &domain.ErrValidation{Field: "id", Message: "el producto ya está dado de baja"}
+	}
 	return s.repo.Delete(id)
 }
 
+// Restaurar reactiva un producto dado de baja; una variante solo puede restaurarse si su
+// padre está activo
+func (s *productoService) Restaurar(id int) (*domain.Producto, error) {
+	producto, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if producto.Activo {
+		return nil, &domain.ErrValidation{Field: "id", Message: "el producto no está dado de baja"}
+	}
+	if producto.EsVariante() {
+		padre, err := s.repo.GetByID(*producto.IDProductoPadre)
+		if err != nil {
+			return nil, err
+		}
+		if !padre.Activo {
+			return nil, &domain.ErrValidation{Field: "id", Message: "restaure primero el producto padre"}
+		}
+	}
+	if err := s.repo.Restaurar(id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
 func (s *productoService) GetStockBajo(limite int) ([]domain.Producto, error) {
 	if limite < 0 {
 		limite = 5
@@ -119,6 +314,249 @@ func (s *productoService) GetStockBajo(limite int) ([]domain.Producto, error) {
 	return s.repo.GetStockBajo(limite)
 }
 
-func (s *productoService) Search(termino string) ([]domain.Producto, error) {
-	return s.repo.Search(termino)
+func (s *productoService) Search(termino string, limite int, incluirInactivos bool) ([]domain.Producto, error) {
+	if limite <= 0 {
+		limite = limiteBusquedaDefault
+	}
+	if limite > limiteBusquedaMaximo {
+		limite = limiteBusquedaMaximo
+	}
+	productos, err := s.repo.Search(termino, limite, incluirInactivos)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cargarImagenes(productos); err != nil {
+		return nil, err
+	}
+	return productos, nil
+}
+
+// GetByCodigoBarras es la búsqueda del punto de venta: no devuelve productos dados de baja
+func (s *productoService) GetByCodigoBarras(codigo string) (*domain.Producto, error) {
+	producto, err := buscarPorCodigoBarras(s.repo, codigo)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarActivo(producto); err != nil {
+		return nil, err
+	}
+	if producto.Imagenes, err = s.archivoRepo.GetByEntidad(domain.EntidadProducto, producto.ID); err != nil {
+		return nil, err
+	}
+	return producto, nil
+}
+
+func (s *productoService) GetCodigosBarras(productoID int) ([]domain.CodigoBarras, error) {
+	if _, err := s.GetByID(productoID); err != nil {
+		return nil, err
+	}
+	return s.codigoBarrasRepo.GetByProductoID(productoID)
+}
+
+func (s *productoService) AgregarCodigoBarras(productoID int, codigo, tipo string) (*domain.CodigoBarras, error) {
+	if _, err := s.GetByID(productoID); err != nil {
+		return nil, err
+	}
+	codigo, tipo, err := domain.NormalizarCodigoBarras(codigo, tipo)
+	if err != nil {
+		return nil, err
+	}
+	existente, err := s.codigoBarrasRepo.GetByCodigo(codigo)
+	var notFound *domain.ErrNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, err
+	}
+	if existente != nil {
+		return nil, &domain.ErrDuplicate{Entity: "código de barras", Field: "codigo", Value: existente.Codigo}
+	}
+	cb := &domain.CodigoBarras{IDProducto: productoID, Codigo: codigo, Tipo: tipo}
+	if err := s.codigoBarrasRepo.Create(cb); err != nil {
+		return nil, err
+	}
+	return cb, nil
+}
+
+func (s *productoService) EliminarCodigoBarras(productoID, id int) error {
+	if productoID <= 0 || id <= 0 {
+		return &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.codigoBarrasRepo.Delete(productoID, id)
+}
+
+func (s *productoService) GetVariantes(padreID int) (*domain.Producto, []domain.Producto, error) {
+	padre, err := s.GetByID(padreID)
+	if err != nil {
+		return nil, nil, err
+	}
+	variantes, err := s.repo.GetVariantes(padreID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.cargarImagenes(variantes); err != nil {
+		return nil, nil, err
+	}
+	return padre, variantes, nil
+}
+
+// CreateVariante crea una variante con la categoría y unidad del padre. Sin nombre se
+// usa el del padre con los atributos; un precio en cero hereda el precio del padre.
+func (s *productoService) CreateVariante(padreID int, variante *domain.Producto) (*domain.Producto, error) {
+	padre, err := s.GetByID(padreID)
+	if err != nil {
+		return nil, err
+	}
+	if padre.EsVariante() {
+		return nil, &domain.ErrValidation{Field: "id_producto_padre", Message: "una variante no puede tener variantes"}
+	}
+	if padre.EsKit() {
+		return nil, &domain.ErrValidation{Field: "id_producto_padre", Message: "un kit no puede tener variantes"}
+	}
+	if !padre.Activo {
+		return nil, &domain.ErrValidation{Field: "id_producto_padre", Message: "el producto padre está dado de baja"}
+	}
+	if err := s.validarPrimeraVariante(padre); err != nil {
+		return nil, err
+	}
+	atributos, err := s.validarAtributos(padreID, 0, variante.Atributos)
+	if err != nil {
+		return nil, err
+	}
+	variante.Tipo = domain.TipoProductoSimple
+	variante.IDProductoPadre = &padre.ID
+	variante.Atributos = atributos
+	variante.IDCategoria = padre.IDCategoria
+	variante.UnidadMedida = padre.UnidadMedida
+	if variante.AfectacionIGV == "" {
+		variante.AfectacionIGV, variante.TasaIGV = padre.AfectacionIGV, padre.TasaIGV
+	}
+	if strings.TrimSpace(variante.Nombre) == "" {
+		variante.Nombre = padre.Nombre + " - " + domain.DescribirAtributos(atributos)
+	}
+	if variante.PrecioUnitario == 0 {
+		variante.PrecioUnitario = padre.PrecioUnitario
+	}
+	return s.Create(variante)
+}
+
+// validarPrimeraVariante impide convertir en padre un producto con stock propio o con
+// movimientos: los reportes dejan de contar el stock de los padres con variantes, y
+// ese stock e historial quedarían fuera de todos ellos
+func (s *productoService) validarPrimeraVariante(padre *domain.Producto) error {
+	variantes, err := s.repo.GetVariantes(padre.ID)
+	if err != nil || len(variantes) > 0 {
+		return err
+	}
+	tiene := padre.StockActual != 0
+	if !tiene {
+		if tiene, err = s.repo.TieneMovimientos(padre.ID); err != nil {
+			return err
+		}
+	}
+	if tiene {
+		return &domain.ErrValidation{Field: "id_producto_padre", Message: "el producto tiene stock o movimientos propios; cree un producto padre nuevo para las variantes"}
+	}
+	return nil
+}
+
+// validarIGV normaliza la afectación; un gravado sin tasa usa la general y los
+// exonerados e inafectos no llevan tasa
+func validarIGV(p *domain.Producto) error {
+	p.AfectacionIGV = strings.ToUpper(strings.TrimSpace(p.AfectacionIGV))
+	switch p.AfectacionIGV {
+	case domain.AfectacionGravado:
+		if p.TasaIGV == 0 {
+			p.TasaIGV = domain.TasaIGVPorDefecto
+		}
+		if p.TasaIGV < 0 || p.TasaIGV >= 100 {
+			return &domain.ErrValidation{Field: "tasa_igv", Message: "debe estar entre 0 y 100"}
+		}
+	case domain.AfectacionExonerado, domain.AfectacionInafecto:
+		p.TasaIGV = 0
+	default:
+		return &domain.ErrValidation{Field: "afectacion_igv", Message: "debe ser GRAVADO, EXONERADO o INAFECTO"}
+	}
+	return nil
+}
+
+// validarAtributos exige al menos un atributo y que la combinación no se repita entre
+// las variantes del padre (excluyendo a la propia variante al actualizar)
+func (s *productoService) validarAtributos(padreID, varianteID int, atributos map[string]string) (map[string]string, error) {
+	atributos = domain.NormalizarAtributos(atributos)
+	if len(atributos) == 0 {
+		return nil, &domain.ErrValidation{Field: "atributos", Message: "indique al menos un atributo, p. ej. talla, color o sabor"}
+	}
+	hermanas, err := s.repo.GetVariantes(padreID)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hermanas {
+		if h.ID != varianteID && domain.MismosAtributos(h.Atributos, atributos) {
+			return nil, &domain.ErrDuplicate{Entity: "variante", Field: "atributos", Value: domain.DescribirAtributos(atributos)}
+		}
+	}
+	return atributos, nil
+}
+
+func (s *productoService) GetComponentes(kitID int) (*domain.Producto, []domain.ComponenteKit, error) {
+	kit, err := s.GetByID(kitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !kit.EsKit() {
+		return nil, nil, &domain.ErrValidation{Field: "id", Message: "el producto no es un kit"}
+	}
+	componentes, err := s.repo.GetComponentes(kitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kit, componentes, nil
+}
+
+// SetComponentes reemplaza la lista de materiales. Los componentes deben llevar stock
+// propio: no se admiten kits anidados ni padres con variantes.
+func (s *productoService) SetComponentes(kitID int, componentes []domain.ComponenteKit) (*domain.Producto, []domain.ComponenteKit, error) {
+	kit, err := s.GetByID(kitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !kit.EsKit() {
+		return nil, nil, &domain.ErrValidation{Field: "id", Message: "el producto no es un kit"}
+	}
+	if len(componentes) == 0 {
+		return nil, nil, &domain.ErrValidation{Field: "componentes", Message: "indique al menos un componente"}
+	}
+	vistos := make(map[int]bool, len(componentes))
+	for _, c := range componentes {
+		if c.Cantidad <= 0 {
+			return nil, nil, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
+		}
+		if c.IDComponente == kitID {
+			return nil, nil, &domain.ErrValidation{Field: "id_producto", Message: "el kit no puede contenerse a sí mismo"}
+		}
+		if vistos[c.IDComponente] {
+			return nil, nil, &domain.ErrValidation{Field: "id_producto", Message: "componente repetido"}
+		}
+		vistos[c.IDComponente] = true
+		componente, err := s.repo.GetByID(c.IDComponente)
+		if err != nil {
+			return nil, nil, err
+		}
+		if componente.EsKit() {
+			return nil, nil, &domain.ErrValidation{Field: "id_producto", Message: "un kit no puede ser componente de otro kit"}
+		}
+		if err := verificarActivo(componente); err != nil {
+			return nil, nil, err
+		}
+		variantes, err := s.repo.GetVariantes(componente.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(variantes) > 0 {
+			return nil, nil, &domain.ErrValidation{Field: "id_producto", Message: "use una variante como componente, no el producto padre"}
+		}
+	}
+	if err := s.repo.SetComponentes(kitID, componentes); err != nil {
+		return nil, nil, err
+	}
+	return s.GetComponentes(kitID)
 }
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type PromocionService interface {
+	GetAll(soloActivas bool) ([]domain.Promocion, error)
+	GetByID(id int) (*domain.Promocion, error)
+	Create(promocion *domain.Promocion) (*domain.Promocion, error)
+	Update(id int, promocion *domain.Promocion) (*domain.Promocion, error)
+	Desactivar(id int) error
+}
+
+type promocionService struct {
+	repo          domain.PromocionRepository
+	productoRepo  domain.ProductoRepository
+	categoriaRepo domain.CategoriaRepository
+}
+
+func NewPromocionService(repo domain.PromocionRepository, productoRepo domain.ProductoRepository, categoriaRepo domain.CategoriaRepository) PromocionService {
+	return &promocionService{repo: repo, productoRepo: productoRepo, categoriaRepo: categoriaRepo}
+}
+
+func (s *promocionService) GetAll(soloActivas bool) ([]domain.Promocion, error) {
+	return s.repo.GetAll(soloActivas)
+}
+
+func (s *promocionService) GetByID(id int) (*domain.Promocion, error) {
+	if id <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *promocionService) Create(promocion *domain.Promocion) (*domain.Promocion, error) {
+	if err := s.validar(promocion); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(promocion); err != nil {
+		return nil, err
+	}
+	return promocion, nil
+}
+
+func (s *promocionService) Update(id int, promocion *domain.Promocion) (*domain.Promocion, error) {
+	existing, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	promocion.ID = existing.ID
+	promocion.FechaCreacion = existing.FechaCreacion
+	if err := s.validar(promocion); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(promocion); err != nil {
+		return nil, err
+	}
+	return promocion, nil
+}
+
+// Desactivar deja de aplicar la promoción sin borrarla, para conservar su reporte
+func (s *promocionService) Desactivar(id int) error {
+	promocion, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	promocion.Activa = false
+	return s.repo.Update(promocion)
+}
+
+func (s *promocionService) validar(p *domain.Promocion) error {
+	p.Nombre = strings.TrimSpace(p.Nombre)
+	if p.Nombre == "" {
+		return &domain.ErrValidation{Field: "nombre", Message: "es requerido"}
+	}
+	p.Tipo = strings.ToUpper(strings.TrimSpace(p.Tipo))
+	switch p.Tipo {
+	case domain.TipoPromocionPorcentaje:
+		if p.Valor <= 0 || p.Valor > 100 {
+			return &domain.ErrValidation{Field: "valor", Message: "el porcentaje debe estar entre 0 y 100"}
+		}
+		p.CantidadLleva, p.CantidadPaga = 0, 0
+	case domain.TipoPromocionMontoFijo:
+		if p.Valor <= 0 {
+			return &domain.ErrValidation{Field: "valor", Message: "debe ser mayor a 0"}
+		}
+		p.CantidadLleva, p.CantidadPaga = 0, 0
+	case domain.TipoPromocionNxM:
+		if p.CantidadLleva < 2 || p.CantidadPaga < 0 || p.CantidadPaga >= p.CantidadLleva {
+			return &domain.ErrValidation{Field: "cantidad_paga", Message: "debe cumplir 0 <= cantidad_paga < cantidad_lleva, con cantidad_lleva >= 2"}
+		}
+		p.Valor = 0
+	case domain.TipoPromocionPrecioPaquete:
+		if p.CantidadLleva < 2 {
+			return &domain.ErrValidation{Field: "cantidad_lleva", Message: "el paquete debe tener al menos 2 unidades"}
+		}
+		if p.Valor <= 0 {
+			return &domain.ErrValidation{Field: "valor", Message: "el precio del paquete debe ser mayor a 0"}
+		}
+		p.CantidadPaga = 0
+	default:
+		return &domain.ErrValidation{Field: "tipo", Message: "debe ser PORCENTAJE, MONTO_FIJO, NXM o PRECIO_PAQUETE"}
+	}
+	if p.IDProducto != nil && p.IDCategoria != nil {
+		return &domain.ErrValidation{Field: "id_categoria", Message: "indique un producto o una categoría, no ambos"}
+	}
+	if p.IDProducto != nil {
+		if _, err := s.productoRepo.GetByID(*p.IDProducto); err != nil {
+			return &domain.ErrValidation{Field: "id_producto", Message: "el producto especificado no existe"}
+		}
+	}
+	if p.IDCategoria != nil {
+		if _, err := s.categoriaRepo.GetByID(*p.IDCategoria); err != nil {
+			return &domain.ErrValidation{Field: "id_categoria", Message: "la categoría especificada no existe"}
+		}
+	}
+	if p.FechaInicio != nil && p.FechaFin != nil && p.FechaFin.Before(*p.FechaInicio) {
+		return &domain.ErrValidation{Field: "fecha_fin", Message: "no puede ser anterior a fecha_inicio"}
+	}
+	for campo, h := range map[string]*string{"hora_inicio": &p.HoraInicio, "hora_fin": &p.HoraFin} {
+		*h = strings.TrimSpace(*h)
+		if *h == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", *h)
+		if err != nil {
+			return &domain.ErrValidation{Field: campo, Message: "formato inválido, use HH:MM"}
+		}
+		*h = t.Format("15:04")
+	}
+	for _, d := range p.DiasSemana {
+		if d < 0 || d > 6 {
+			return &domain.ErrValidation{Field: "dias_semana", Message: "use 0 (domingo) a 6 (sábado)"}
+		}
+	}
+	slices.Sort(p.DiasSemana)
+	p.DiasSemana = slices.Compact(p.DiasSemana)
+	p.LugarVenta = strings.TrimSpace(p.LugarVenta)
+	return nil
+}
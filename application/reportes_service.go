@@ -1,47 +1,300 @@
 package application
 
 import (
+	"math"
+	"time"
+
 	"github.com/Mishka-GDI-Back/domain"
 )
 
 type ReportesService interface {
-	GetInventarioActual() ([]domain.ReporteInventarioItem, error)
+	GetInventarioActual(unidad string) ([]domain.ReporteInventarioItem, error)
 	GetMovimientos(inicio, fin string) ([]domain.ReporteMovimiento, error)
-	GetProductosMasVendidos(limite int) ([]domain.ReporteProductoVendido, error)
-	GetProductosMasIngresados(limite int) ([]domain.ReporteProductoVendido, error)
-	GetValoracionInventario() ([]domain.ReporteValoracion, error)
+	GetProductosMasVendidos(limite int, unidad string) ([]domain.ReporteProductoVendido, error)
+	GetProductosMasIngresados(limite int, unidad string) ([]domain.ReporteProductoVendido, error)
+	GetValoracionInventario(nivel int) ([]domain.ReporteValoracion, error)
+	GetCobertura(params domain.ParametrosCobertura) ([]domain.ReporteCobertura, error)
+	GetClasificacionABC(inicio, fin string, umbralA, umbralB float64) ([]domain.ReporteABC, error)
+	AplicarClasificacionABC(inicio, fin string, umbralA, umbralB float64) ([]domain.ReporteABC, error)
+	GetStockInmovilizado(dias int) ([]domain.ReporteStockInmovilizado, error)
+	GetDescuentosPorPromocion(rango domain.RangoFechas) ([]domain.ReportePromocion, error)
+	GetComprasPorProveedor(rango domain.RangoFechas) ([]domain.ReporteCompraProveedor, error)
+	GetVentasPorCategoria(rango domain.RangoFechas, nivel int) ([]domain.ReporteVentasCategoria, error)
+	GetReporteIGV(mes, anio int) (*domain.ReporteIGV, error)
 }
 
 type reportesService struct {
-	repo domain.ReportesRepository
+	repo          domain.ReportesRepository
+	productoRepo  domain.ProductoRepository
+	unidadRepo    domain.UnidadMedidaRepository
+	categoriaRepo domain.CategoriaRepository
 }
 
-func NewReportesService(repo domain.ReportesRepository) ReportesService {
-	return &reportesService{repo: repo}
+func NewReportesService(repo domain.ReportesRepository, productoRepo domain.ProductoRepository, unidadRepo domain.UnidadMedidaRepository, categoriaRepo domain.CategoriaRepository) ReportesService {
+	return &reportesService{repo: repo, productoRepo: productoRepo, unidadRepo: unidadRepo, categoriaRepo: categoriaRepo}
 }
 
-func (s *reportesService) GetInventarioActual() ([]domain.ReporteInventarioItem, error) {
-	return s.repo.GetInventarioActual()
+// factoresUnidad retorna los factores de conversión a 'unidad' por producto; nil si no se pidió unidad
+func (s *reportesService) factoresUnidad(unidad string) (string, map[int]int, error) {
+	unidad = domain.NormalizarUnidad(unidad)
+	if unidad == "" {
+		return "", nil, nil
+	}
+	if err := validarUnidad(s.unidadRepo, "unidad", unidad); err != nil {
+		return "", nil, err
+	}
+	factores, err := s.unidadRepo.GetFactores(unidad)
+	return unidad, factores, err
+}
+
+// enUnidad convierte una cantidad base a la unidad del reporte; los productos sin
+// esa conversión configurada quedan solo en su unidad base
+func enUnidad(cantidad int, unidad string, factor int) (string, float64) {
+	if factor <= 0 {
+		return "", 0
+	}
+	return unidad, math.Round(float64(cantidad)/float64(factor)*100) / 100
+}
+
+func (s *reportesService) GetInventarioActual(unidad string) ([]domain.ReporteInventarioItem, error) {
+	unidad, factores, err := s.factoresUnidad(unidad)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetInventarioActual()
+	if err != nil || factores == nil {
+		return items, err
+	}
+	for i := range items {
+		items[i].UnidadReporte, items[i].CantidadReporte = enUnidad(items[i].StockActual, unidad, factores[items[i].IDProducto])
+	}
+	return items, nil
+}
+
+func (s *reportesService) convertirVendidos(items []domain.ReporteProductoVendido, unidad string, factores map[int]int) {
+	for i := range items {
+		items[i].UnidadReporte, items[i].CantidadReporte = enUnidad(items[i].TotalVendido, unidad, factores[items[i].IDProducto])
+	}
 }
 
 func (s *reportesService) GetMovimientos(inicio, fin string) ([]domain.ReporteMovimiento, error) {
 	return s.repo.GetMovimientos(inicio, fin)
 }
 
-func (s *reportesService) GetProductosMasVendidos(limite int) ([]domain.ReporteProductoVendido, error) {
+func (s *reportesService) GetProductosMasVendidos(limite int, unidad string) ([]domain.ReporteProductoVendido, error) {
 	if limite <= 0 {
 		limite = 10
 	}
-	return s.repo.GetProductosMasVendidos(limite)
+	unidad, factores, err := s.factoresUnidad(unidad)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetProductosMasVendidos(limite)
+	if err != nil || factores == nil {
+		return items, err
+	}
+	s.convertirVendidos(items, unidad, factores)
+	return items, nil
 }
 
-func (s *reportesService) GetProductosMasIngresados(limite int) ([]domain.ReporteProductoVendido, error) {
+func (s *reportesService) GetProductosMasIngresados(limite int, unidad string) ([]domain.ReporteProductoVendido, error) {
 	if limite <= 0 {
 		limite = 10
 	}
-	return s.repo.GetProductosMasIngresados(limite)
+	unidad, factores, err := s.factoresUnidad(unidad)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetProductosMasIngresados(limite)
+	if err != nil || factores == nil {
+		return items, err
+	}
+	s.convertirVendidos(items, unidad, factores)
+	return items, nil
+}
+
+// GetValoracionInventario valoriza el stock por categoría; con nivel > 0 consolida
+// las subcategorías en su ancestro de ese nivel (1 = categorías raíz)
+func (s *reportesService) GetValoracionInventario(nivel int) ([]domain.ReporteValoracion, error) {
+	if err := validarNivel(nivel); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetValoracionInventario()
+	if err != nil {
+		return nil, err
+	}
+	arbol, err := arbolCategorias(s.categoriaRepo)
+	if err != nil {
+		return nil, err
+	}
+	return consolidarValoracion(items, arbol, nivel), nil
+}
+
+// GetVentasPorCategoria totaliza las ventas del rango por categoría, consolidadas en el
+// nivel pedido del árbol
+func (s *reportesService) GetVentasPorCategoria(rango domain.RangoFechas, nivel int) ([]domain.ReporteVentasCategoria, error) {
+	if err := validarRangoFechas(rango); err != nil {
+		return nil, err
+	}
+	if err := validarNivel(nivel); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetVentasPorCategoria(rango)
+	if err != nil {
+		return nil, err
+	}
+	arbol, err := arbolCategorias(s.categoriaRepo)
+	if err != nil {
+		return nil, err
+	}
+	return consolidarVentasCategoria(items, arbol, nivel), nil
+}
+
+func (s *reportesService) GetCobertura(params domain.ParametrosCobertura) ([]domain.ReporteCobertura, error) {
+	params, err := normalizarParametrosCobertura(params)
+	if err != nil {
+		return nil, err
+	}
+	ventas, err := s.repo.GetVentasPorVentana(params.Ventanas)
+	if err != nil {
+		return nil, err
+	}
+	return calcularCobertura(ventas, params, time.Now()), nil
 }
 
-func (s *reportesService) GetValoracionInventario() ([]domain.ReporteValoracion, error) {
-	return s.repo.GetValoracionInventario()
+// GetClasificacionABC clasifica los productos por participación acumulada en los ingresos
+// del periodo. Por defecto se usan los últimos 365 días y los umbrales 80% / 95%.
+func (s *reportesService) GetClasificacionABC(inicio, fin string, umbralA, umbralB float64) ([]domain.ReporteABC, error) {
+	hasta := time.Now()
+	if fin != "" {
+		t, err := time.Parse("2006-01-02", fin)
+		if err != nil {
+			return nil, &domain.ErrValidation{Field: "fin", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+		hasta = t
+	}
+	desde := hasta.AddDate(0, 0, -364)
+	if inicio != "" {
+		t, err := time.Parse("2006-01-02", inicio)
+		if err != nil {
+			return nil, &domain.ErrValidation{Field: "inicio", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+		desde = t
+	}
+	if hasta.Before(desde) {
+		return nil, &domain.ErrValidation{Field: "fin", Message: "no puede ser anterior a inicio"}
+	}
+	if umbralA <= 0 {
+		umbralA = 80
+	}
+	if umbralB <= 0 {
+		umbralB = 95
+	}
+	if umbralA >= umbralB || umbralB > 100 {
+		return nil, &domain.ErrValidation{Field: "umbral_b", Message: "los umbrales deben cumplir 0 < umbral_a < umbral_b <= 100"}
+	}
+	ventas, err := s.repo.GetIngresosPorProducto(desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	return clasificarABC(ventas, umbralA, umbralB), nil
+}
+
+// clasificarABC asume las ventas ordenadas por ingresos descendente. Un producto es A
+// mientras la participación acumulada previa no alcance umbralA, y B mientras no alcance umbralB.
+func clasificarABC(ventas []domain.ReporteProductoVendido, umbralA, umbralB float64) []domain.ReporteABC {
+	var total float64
+	for _, v := range ventas {
+		total += v.TotalIngresos
+	}
+	items := make([]domain.ReporteABC, len(ventas))
+	var acumulada float64
+	for i, v := range ventas {
+		item := domain.ReporteABC{
+			IDProducto:    v.IDProducto,
+			Codigo:        v.Codigo,
+			Nombre:        v.Nombre,
+			Categoria:     v.Categoria,
+			TotalVendido:  v.TotalVendido,
+			TotalIngresos: v.TotalIngresos,
+			Clase:         domain.ClaseC,
+		}
+		if total > 0 && v.TotalIngresos > 0 {
+			item.Participacion = v.TotalIngresos / total * 100
+			switch {
+			case acumulada < umbralA:
+				item.Clase = domain.ClaseA
+			case acumulada < umbralB:
+				item.Clase = domain.ClaseB
+			}
+			acumulada += item.Participacion
+		}
+		item.ParticipacionAcumulada = acumulada
+		item.FrecuenciaConteoDias = domain.FrecuenciaConteoDias(item.Clase)
+		items[i] = item
+	}
+	return items
+}
+
+// AplicarClasificacionABC calcula la clasificación y la guarda en cada producto
+func (s *reportesService) AplicarClasificacionABC(inicio, fin string, umbralA, umbralB float64) ([]domain.ReporteABC, error) {
+	items, err := s.GetClasificacionABC(inicio, fin, umbralA, umbralB)
+	if err != nil {
+		return nil, err
+	}
+	clases := make(map[int]string, len(items))
+	for _, item := range items {
+		clases[item.IDProducto] = item.Clase
+	}
+	if err := s.productoRepo.ActualizarClasesABC(clases); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *reportesService) GetStockInmovilizado(dias int) ([]domain.ReporteStockInmovilizado, error) {
+	if dias <= 0 {
+		dias = 90
+	}
+	return s.repo.GetStockInmovilizado(dias)
+}
+
+func (s *reportesService) GetDescuentosPorPromocion(rango domain.RangoFechas) ([]domain.ReportePromocion, error) {
+	if err := validarRangoFechas(rango); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDescuentosPorPromocion(rango)
+}
+
+func (s *reportesService) GetComprasPorProveedor(rango domain.RangoFechas) ([]domain.ReporteCompraProveedor, error) {
+	if err := validarRangoFechas(rango); err != nil {
+		return nil, err
+	}
+	return s.repo.GetComprasPorProveedor(rango)
+}
+
+// GetReporteIGV compara el IGV de las ventas del mes con el de sus entradas
+func (s *reportesService) GetReporteIGV(mes, anio int) (*domain.ReporteIGV, error) {
+	if mes < 1 || mes > 12 {
+		return nil, &domain.ErrValidation{Field: "mes", Message: "debe estar entre 1 y 12"}
+	}
+	if err := validarAnio(anio); err != nil {
+		return nil, err
+	}
+	inicio := time.Date(anio, time.Month(mes), 1, 0, 0, 0, 0, time.UTC)
+	ventas, compras, err := s.repo.GetResumenIGV(inicio, inicio.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+	reporte := &domain.ReporteIGV{Mes: mes, Anio: anio, Ventas: ventas, Compras: compras}
+	for _, v := range ventas {
+		reporte.IGVVentas += v.IGV
+	}
+	for _, c := range compras {
+		reporte.IGVCompras += c.IGV
+	}
+	reporte.IGVVentas = math.Round(reporte.IGVVentas*100) / 100
+	reporte.IGVCompras = math.Round(reporte.IGVCompras*100) / 100
+	reporte.IGVPorPagar = math.Round((reporte.IGVVentas-reporte.IGVCompras)*100) / 100
+	return reporte, nil
 }
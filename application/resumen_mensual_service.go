@@ -1,15 +1,25 @@
 package application
 
 import (
+	"errors"
+	"time"
+
 	"github.com/Mishka-GDI-Back/domain"
 )
 
+// maxAniosComparativo limita la cantidad de años en una comparación mensual
+const maxAniosComparativo = 10
+
 type ResumenMensualService interface {
 	GetByMesAnio(mes, anio int) (*domain.ResumenMensual, error)
 	GetActual() (*domain.ResumenMensual, error)
 	GetByProductoID(productoID, mes, anio int) (*domain.ResumenProducto, error)
 	Generar(mes, anio int) (*domain.ResumenMensual, error)
 	GuardarManual(resumen *domain.ResumenMensual) (*domain.ResumenMensual, error)
+	GetResumenAnual(anio int) (*domain.ResumenAnual, error)
+	CompararMes(mes, desde, hasta int) (*domain.ComparativoMensual, error)
+	CompararPeriodos(inicio, fin, inicioAnterior, finAnterior string) (*domain.ComparativoPeriodo, error)
+	GetMatrizMovimientos(mes, anio int, idCategoria *int) ([]domain.MovimientoMensualProducto, error)
 }
 
 type resumenMensualService struct {
@@ -35,6 +45,13 @@ func (s *resumenMensualService) GetByProductoID(productoID, mes, anio int) (*dom
 	if productoID <= 0 {
 		return nil, &domain.ErrValidation{Field: "id_producto", Message: "debe ser mayor a 0"}
 	}
+	mes, anio = mesAnioPorDefecto(mes, anio)
+	if mes < 1 || mes > 12 {
+		return nil, &domain.ErrValidation{Field: "mes", Message: "debe estar entre 1 y 12"}
+	}
+	if err := validarAnio(anio); err != nil {
+		return nil, err
+	}
 	return s.resumenRepo.GetByProductoID(productoID, mes, anio)
 }
 
@@ -52,3 +69,208 @@ func (s *resumenMensualService) GuardarManual(resumen *domain.ResumenMensual) (*
 	}
 	return resumen, nil
 }
+
+func validarAnio(anio int) error {
+	if anio < 2000 || anio > 2100 {
+		return &domain.ErrValidation{Field: "anio", Message: "debe estar entre 2000 y 2100"}
+	}
+	return nil
+}
+
+func (s *resumenMensualService) GetResumenAnual(anio int) (*domain.ResumenAnual, error) {
+	if err := validarAnio(anio); err != nil {
+		return nil, err
+	}
+	meses, err := s.resumenRepo.CalcularAnio(anio)
+	if err != nil {
+		return nil, err
+	}
+	persistidos, err := s.resumenRepo.GetByAnio(anio)
+	if err != nil {
+		return nil, err
+	}
+	// Los resúmenes guardados tienen prioridad sobre los calculados
+	for _, p := range persistidos {
+		if p.Mes >= 1 && p.Mes <= 12 {
+			meses[p.Mes-1] = p
+		}
+	}
+
+	resumen := &domain.ResumenAnual{Anio: anio, Meses: meses}
+	for _, m := range meses {
+		resumen.TotalIngresos += m.TotalIngresos
+		resumen.TotalGastosFijos += m.TotalGastosFijos
+		resumen.TotalGastosVariables += m.TotalGastosVariables
+		resumen.Balance += m.Balance
+	}
+
+	// Solo cuentan para promedios y extremos los meses ya transcurridos
+	resumen.MesesConsiderados = mesesTranscurridos(anio, time.Now())
+	if resumen.MesesConsiderados == 0 {
+		return resumen, nil
+	}
+	var ingresos, gastos, balance float64
+	for i := 0; i < resumen.MesesConsiderados; i++ {
+		m := &resumen.Meses[i]
+		ingresos += m.TotalIngresos
+		gastos += m.TotalGastos()
+		balance += m.Balance
+		if resumen.MejorMes == nil || m.Balance > resumen.MejorMes.Balance {
+			resumen.MejorMes = m
+		}
+		if resumen.PeorMes == nil || m.Balance < resumen.PeorMes.Balance {
+			resumen.PeorMes = m
+		}
+	}
+	n := float64(resumen.MesesConsiderados)
+	resumen.PromedioIngresos = ingresos / n
+	resumen.PromedioGastos = gastos / n
+	resumen.PromedioBalance = balance / n
+	return resumen, nil
+}
+
+func mesesTranscurridos(anio int, ahora time.Time) int {
+	switch {
+	case anio < ahora.Year():
+		return 12
+	case anio == ahora.Year():
+		return int(ahora.Month())
+	default:
+		return 0
+	}
+}
+
+func (s *resumenMensualService) CompararMes(mes, desde, hasta int) (*domain.ComparativoMensual, error) {
+	if mes < 1 || mes > 12 {
+		return nil, &domain.ErrValidation{Field: "mes", Message: "debe estar entre 1 y 12"}
+	}
+	if err := validarAnio(desde); err != nil {
+		return nil, &domain.ErrValidation{Field: "desde", Message: "debe estar entre 2000 y 2100"}
+	}
+	if err := validarAnio(hasta); err != nil {
+		return nil, &domain.ErrValidation{Field: "hasta", Message: "debe estar entre 2000 y 2100"}
+	}
+	if desde > hasta {
+		return nil, &domain.ErrValidation{Field: "desde", Message: "no puede ser mayor que hasta"}
+	}
+	if hasta-desde+1 > maxAniosComparativo {
+		return nil, &domain.ErrValidation{Field: "hasta", Message: "el rango no puede superar 10 años"}
+	}
+
+	comparativo := &domain.ComparativoMensual{Mes: mes}
+	var anterior *domain.ResumenMensual
+	for anio := desde; anio <= hasta; anio++ {
+		actual, err := s.resumenDelMes(mes, anio)
+		if err != nil {
+			return nil, err
+		}
+		item := domain.ComparativoAnio{Resumen: *actual}
+		if anterior != nil {
+			v := domain.NuevaVariacion(actual.TotalIngresos, actual.TotalGastos(), actual.Balance,
+				anterior.TotalIngresos, anterior.TotalGastos(), anterior.Balance)
+			item.Variacion = &v
+		}
+		comparativo.Anios = append(comparativo.Anios, item)
+		anterior = actual
+	}
+	return comparativo, nil
+}
+
+// resumenDelMes retorna el resumen guardado o, si no existe, lo calcula sin persistirlo
+func (s *resumenMensualService) resumenDelMes(mes, anio int) (*domain.ResumenMensual, error) {
+	resumen, err := s.resumenRepo.GetByMesAnio(mes, anio)
+	if err == nil {
+		return resumen, nil
+	}
+	var notFound *domain.ErrNotFound
+	if !errors.As(err, &notFound) {
+		return nil, err
+	}
+	inicio := time.Date(anio, time.Month(mes), 1, 0, 0, 0, 0, time.UTC)
+	periodo, err := s.resumenRepo.CalcularPeriodo(inicio, inicio.AddDate(0, 1, -1))
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ResumenMensual{
+		Mes:                  mes,
+		Anio:                 anio,
+		TotalIngresos:        periodo.TotalIngresos,
+		TotalGastosVariables: periodo.TotalGastos,
+		Balance:              periodo.Balance,
+		Observaciones:        "Calculado al vuelo",
+		Calculado:            true,
+	}, nil
+}
+
+// CompararPeriodos compara [inicio, fin] contra [inicioAnterior, finAnterior].
+// Si no se indica el periodo anterior se usa el inmediatamente previo de igual duración.
+func (s *resumenMensualService) CompararPeriodos(inicio, fin, inicioAnterior, finAnterior string) (*domain.ComparativoPeriodo, error) {
+	desde, err := time.Parse("2006-01-02", inicio)
+	if err != nil {
+		return nil, &domain.ErrValidation{Field: "inicio", Message: "formato inválido, use YYYY-MM-DD"}
+	}
+	hasta, err := time.Parse("2006-01-02", fin)
+	if err != nil {
+		return nil, &domain.ErrValidation{Field: "fin", Message: "formato inválido, use YYYY-MM-DD"}
+	}
+	if hasta.Before(desde) {
+		return nil, &domain.ErrValidation{Field: "fin", Message: "no puede ser anterior a inicio"}
+	}
+
+	var desdeAnt, hastaAnt time.Time
+	if inicioAnterior == "" && finAnterior == "" {
+		dias := int(hasta.Sub(desde).Hours()/24) + 1
+		hastaAnt = desde.AddDate(0, 0, -1)
+		desdeAnt = hastaAnt.AddDate(0, 0, -(dias - 1))
+	} else {
+		if desdeAnt, err = time.Parse("2006-01-02", inicioAnterior); err != nil {
+			return nil, &domain.ErrValidation{Field: "inicio_anterior", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+		if hastaAnt, err = time.Parse("2006-01-02", finAnterior); err != nil {
+			return nil, &domain.ErrValidation{Field: "fin_anterior", Message: "formato inválido, use YYYY-MM-DD"}
+		}
+		if hastaAnt.Before(desdeAnt) {
+			return nil, &domain.ErrValidation{Field: "fin_anterior", Message: "no puede ser anterior a inicio_anterior"}
+		}
+	}
+
+	actual, err := s.resumenRepo.CalcularPeriodo(desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	previo, err := s.resumenRepo.CalcularPeriodo(desdeAnt, hastaAnt)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ComparativoPeriodo{
+		Actual:   *actual,
+		Anterior: *previo,
+		Variacion: domain.NuevaVariacion(actual.TotalIngresos, actual.TotalGastos, actual.Balance,
+			previo.TotalIngresos, previo.TotalGastos, previo.Balance),
+	}, nil
+}
+
+// mesAnioPorDefecto completa con el mes y año actuales los valores no indicados (0)
+func mesAnioPorDefecto(mes, anio int) (int, int) {
+	now := time.Now()
+	if mes == 0 {
+		mes = int(now.Month())
+	}
+	if anio == 0 {
+		anio = now.Year()
+	}
+	return mes, anio
+}
+
+func (s *resumenMensualService) GetMatrizMovimientos(mes, anio int, idCategoria *int) ([]domain.MovimientoMensualProducto, error) {
+	if mes < 1 || mes > 12 {
+		return nil, &domain.ErrValidation{Field: "mes", Message: "debe estar entre 1 y 12"}
+	}
+	if err := validarAnio(anio); err != nil {
+		return nil, err
+	}
+	if idCategoria != nil && *idCategoria <= 0 {
+		return nil, &domain.ErrValidation{Field: "id_categoria", Message: "debe ser mayor a 0"}
+	}
+	return s.resumenRepo.GetMatrizMovimientos(mes, anio, idCategoria)
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"math"
 	"strings"
 	"time"
 
@@ -8,25 +9,42 @@ import (
 )
 
 type SalidaProductoService interface {
-	GetAll() ([]domain.SalidaConProducto, error)
+	GetAll(filtro domain.FiltroSalidas) ([]domain.SalidaConProducto, int, error)
 	GetByID(id int) (*domain.SalidaConProducto, error)
 	GetByProductoID(productoID int) ([]domain.SalidaConProducto, error)
 	GetByFecha(fecha string) ([]domain.SalidaConProducto, error)
 	GetByLugar(lugar string) ([]domain.SalidaConProducto, error)
 	Create(salida *domain.SalidaProducto) (*domain.SalidaProducto, error)
+	CreateVenta(comprobante *domain.Comprobante, salidas []*domain.SalidaProducto) (*domain.ComprobanteDetalle, error)
 }
 
 type salidaProductoService struct {
-	salidaRepo   domain.SalidaProductoRepository
-	productoRepo domain.ProductoRepository
+	salidaRepo    domain.SalidaProductoRepository
+	productoRepo  domain.ProductoRepository
+	unidadRepo    domain.UnidadMedidaRepository
+	promocionRepo domain.PromocionRepository
+	clienteRepo   domain.ClienteRepository
+	categoriaRepo domain.CategoriaRepository
 }
 
-func NewSalidaProductoService(salidaRepo domain.SalidaProductoRepository, productoRepo domain.ProductoRepository) SalidaProductoService {
-	return &salidaProductoService{salidaRepo: salidaRepo, productoRepo: productoRepo}
+func NewSalidaProductoService(salidaRepo domain.SalidaProductoRepository, productoRepo domain.ProductoRepository, unidadRepo domain.UnidadMedidaRepository, promocionRepo domain.PromocionRepository, clienteRepo domain.ClienteRepository, categoriaRepo domain.CategoriaRepository) SalidaProductoService {
+	return &salidaProductoService{salidaRepo: salidaRepo, productoRepo: productoRepo, unidadRepo: unidadRepo, promocionRepo: promocionRepo, clienteRepo: clienteRepo, categoriaRepo: categoriaRepo}
 }
 
-func (s *salidaProductoService) GetAll() ([]domain.SalidaConProducto, error) {
-	return s.salidaRepo.GetAll()
+func (s *salidaProductoService) GetAll(filtro domain.FiltroSalidas) ([]domain.SalidaConProducto, int, error) {
+	if err := validarRangoFechas(filtro.RangoFechas); err != nil {
+		return nil, 0, err
+	}
+	if err := validarOrden(&filtro.Orden, domain.CamposOrdenSalidas); err != nil {
+		return nil, 0, err
+	}
+	if err := filtro.Paginacion.Normalizar(); err != nil {
+		return nil, 0, err
+	}
+	filtro.Lugar = strings.TrimSpace(filtro.Lugar)
+	filtro.Usuario = strings.TrimSpace(filtro.Usuario)
+	filtro.TipoPago = strings.TrimSpace(filtro.TipoPago)
+	return s.salidaRepo.GetAll(filtro)
 }
 
 func (s *salidaProductoService) GetByID(id int) (*domain.SalidaConProducto, error) {
@@ -61,42 +79,151 @@ func (s *salidaProductoService) GetByLugar(lugar string) ([]domain.SalidaConProd
 }
 
 func (s *salidaProductoService) Create(salida *domain.SalidaProducto) (*domain.SalidaProducto, error) {
-	if salida.IDProducto <= 0 {
-		return nil, &domain.ErrValidation{Field: "id_producto", Message: "debe ser mayor a 0"}
+	comprobante := &domain.Comprobante{
+		IDCliente:       salida.IDCliente,
+		LugarVenta:      salida.LugarVenta,
+		TipoPago:        salida.TipoPago,
+		UsuarioRegistro: salida.UsuarioRegistro,
 	}
-	if salida.Cantidad <= 0 {
-		return nil, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
+	if _, err := s.CreateVenta(comprobante, []*domain.SalidaProducto{salida}); err != nil {
+		return nil, err
 	}
-	if strings.TrimSpace(salida.UsuarioRegistro) == "" {
+	return salida, nil
+}
+
+// CreateVenta registra las líneas de una venta bajo un solo comprobante. La fecha,
+// el lugar, el tipo de pago, el cliente y el usuario del comprobante se copian a cada
+// línea. Las ventas al crédito generan una cuenta por cobrar del cliente.
+func (s *salidaProductoService) CreateVenta(comprobante *domain.Comprobante, salidas []*domain.SalidaProducto) (*domain.ComprobanteDetalle, error) {
+	if len(salidas) == 0 {
+		return nil, &domain.ErrValidation{Field: "items", Message: "la venta debe tener al menos una línea"}
+	}
+	comprobante.LugarVenta = strings.TrimSpace(comprobante.LugarVenta)
+	comprobante.TipoPago = strings.TrimSpace(comprobante.TipoPago)
+	comprobante.UsuarioRegistro = strings.TrimSpace(comprobante.UsuarioRegistro)
+	if comprobante.UsuarioRegistro == "" {
 		return nil, &domain.ErrValidation{Field: "usuario_registro", Message: "es requerido"}
 	}
-	producto, err := s.productoRepo.GetByID(salida.IDProducto)
+	cuenta, err := s.prepararCredito(comprobante, salidas[0].FechaSalida)
 	if err != nil {
 		return nil, err
 	}
-	if producto.StockActual < salida.Cantidad {
-		return nil, &domain.ErrInsufficientStock{
-			ProductoID:  salida.IDProducto,
-			StockActual: producto.StockActual,
-			CantidadReq: salida.Cantidad,
+	// Todas las líneas comparten la fecha: basta consultar las promociones una vez
+	promociones, err := s.promocionRepo.GetVigentes(salidas[0].FechaSalida)
+	if err != nil {
+		return nil, err
+	}
+	// Las promociones de categoría alcanzan a las subcategorías
+	var arbol *domain.ArbolCategorias
+	if len(promociones) > 0 {
+		if arbol, err = arbolCategorias(s.categoriaRepo); err != nil {
+			return nil, err
 		}
 	}
-	// Calcular total
-	salida.Total = salida.PrecioVenta*float64(salida.Cantidad) - salida.Descuento
-	if salida.Total < 0 {
-		salida.Total = 0
+	lineas := make([]domain.LineaVenta, 0, len(salidas))
+	registradas := make([]domain.SalidaProducto, 0, len(salidas))
+	for _, salida := range salidas {
+		salida.FechaSalida = salidas[0].FechaSalida
+		salida.LugarVenta = comprobante.LugarVenta
+		salida.TipoPago = comprobante.TipoPago
+		salida.IDCliente = comprobante.IDCliente
+		salida.UsuarioRegistro = comprobante.UsuarioRegistro
+		linea, err := s.prepararLinea(salida, promociones, arbol)
+		if err != nil {
+			return nil, err
+		}
+		lineas = append(lineas, linea)
+		registradas = append(registradas, *salida)
 	}
-	salida.LugarVenta = strings.TrimSpace(salida.LugarVenta)
-	salida.TipoPago = strings.TrimSpace(salida.TipoPago)
-	salida.Observaciones = strings.TrimSpace(salida.Observaciones)
-	salida.UsuarioRegistro = strings.TrimSpace(salida.UsuarioRegistro)
-	if err := s.salidaRepo.Create(salida); err != nil {
+	comprobante.Totalizar(registradas)
+	if err := s.salidaRepo.CreateVenta(comprobante, lineas, cuenta); err != nil {
 		return nil, err
 	}
-	// Actualizar stock
-	producto.StockActual -= salida.Cantidad
-	if err := s.productoRepo.Update(producto); err != nil {
+	guardadas, err := s.salidaRepo.GetByComprobante(comprobante.ID)
+	if err != nil {
 		return nil, err
 	}
-	return salida, nil
+	return &domain.ComprobanteDetalle{Comprobante: *comprobante, Lineas: guardadas, Cuenta: cuenta}, nil
+}
+
+// prepararCredito valida el cliente de la venta. Las ventas al crédito lo exigen y
+// retornan la cuenta por cobrar con su vencimiento según los días de crédito del cliente.
+func (s *salidaProductoService) prepararCredito(comprobante *domain.Comprobante, fecha time.Time) (*domain.CuentaPorCobrar, error) {
+	credito := strings.EqualFold(comprobante.TipoPago, domain.TipoPagoCredito)
+	if credito {
+		comprobante.TipoPago = domain.TipoPagoCredito
+	}
+	if comprobante.IDCliente == nil {
+		if credito {
+			return nil, &domain.ErrValidation{Field: "id_cliente", Message: "es requerido en las ventas al crédito"}
+		}
+		return nil, nil
+	}
+	cliente, err := s.clienteRepo.GetByID(*comprobante.IDCliente)
+	if err != nil {
+		return nil, err
+	}
+	if !cliente.Activo {
+		return nil, &domain.ErrValidation{Field: "id_cliente", Message: "el cliente está inactivo"}
+	}
+	if !credito {
+		return nil, nil
+	}
+	return &domain.CuentaPorCobrar{
+		IDCliente:        cliente.ID,
+		FechaEmision:     fecha,
+		FechaVencimiento: fecha.AddDate(0, 0, cliente.DiasCredito),
+	}, nil
+}
+
+// prepararLinea resuelve el producto, convierte la unidad, aplica la mejor promoción
+// y calcula el total y el IGV de la línea
+func (s *salidaProductoService) prepararLinea(salida *domain.SalidaProducto, promociones []domain.Promocion, arbol *domain.ArbolCategorias) (domain.LineaVenta, error) {
+	if salida.Cantidad <= 0 {
+		return domain.LineaVenta{}, &domain.ErrValidation{Field: "cantidad", Message: "debe ser mayor a 0"}
+	}
+	producto, err := resolverProducto(s.productoRepo, salida.IDProducto, salida.CodigoBarras)
+	if err != nil {
+		return domain.LineaVenta{}, err
+	}
+	salida.IDProducto = producto.ID
+	cantidad, factor, err := convertirCantidad(s.unidadRepo, producto, salida.Unidad, salida.Cantidad)
+	if err != nil {
+		return domain.LineaVenta{}, err
+	}
+	salida.Cantidad = cantidad
+	salida.PrecioVenta /= float64(factor)
+	linea := domain.LineaVenta{Salida: salida}
+	if producto.EsKit() {
+		// El kit registra los ingresos a su nombre y descuenta sus componentes
+		linea.Componentes, err = s.productoRepo.GetComponentes(producto.ID)
+		if err != nil {
+			return domain.LineaVenta{}, err
+		}
+		if len(linea.Componentes) == 0 {
+			return domain.LineaVenta{}, &domain.ErrValidation{Field: "id_producto", Message: "el kit no tiene componentes definidos"}
+		}
+	} else if producto.StockActual < salida.Cantidad {
+		return domain.LineaVenta{}, &domain.ErrInsufficientStock{
+			ProductoID:  salida.IDProducto,
+			StockActual: producto.StockActual,
+			CantidadReq: salida.Cantidad,
+		}
+	}
+	// La mejor promoción vigente se aplica antes del descuento manual
+	salida.IDPromocion, salida.DescuentoPromocion = nil, 0
+	if promocion, descuento := domain.MejorPromocion(promociones, arbol, producto, salida, time.Now()); promocion != nil {
+		salida.IDPromocion = &promocion.ID
+		salida.DescuentoPromocion = descuento
+	}
+	// El total se calcula con el precio sin redondear: al vender por caja es el precio
+	// de la caja. El descuento manual no puede dejar el total bajo cero, para que total
+	// más descuentos siempre sea el importe de la línea.
+	importe := math.Round(salida.PrecioVenta*float64(salida.Cantidad)*100) / 100
+	salida.Descuento = math.Min(salida.Descuento, importe-salida.DescuentoPromocion)
+	salida.Total = math.Round((importe-salida.DescuentoPromocion-salida.Descuento)*100) / 100
+	salida.PrecioVenta = math.Round(salida.PrecioVenta*100) / 100
+	salida.Impuesto = domain.DesglosarIGV(producto.AfectacionIGV, producto.TasaIGV, salida.Total)
+	salida.Observaciones = strings.TrimSpace(salida.Observaciones)
+	return linea, nil
 }
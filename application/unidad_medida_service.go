@@ -0,0 +1,127 @@
+package application
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+type UnidadMedidaService interface {
+	GetAll() ([]domain.UnidadMedida, error)
+	Create(unidad *domain.UnidadMedida) (*domain.UnidadMedida, error)
+	GetConversiones(productoID int) (*domain.Producto, []domain.ConversionUnidad, error)
+	SetConversion(productoID int, unidad string, factor int) (*domain.ConversionUnidad, error)
+	DeleteConversion(productoID int, unidad string) error
+}
+
+type unidadMedidaService struct {
+	repo         domain.UnidadMedidaRepository
+	productoRepo domain.ProductoRepository
+}
+
+func NewUnidadMedidaService(repo domain.UnidadMedidaRepository, productoRepo domain.ProductoRepository) UnidadMedidaService {
+	return &unidadMedidaService{repo: repo, productoRepo: productoRepo}
+}
+
+func (s *unidadMedidaService) GetAll() ([]domain.UnidadMedida, error) {
+	return s.repo.GetAll()
+}
+
+func (s *unidadMedidaService) Create(unidad *domain.UnidadMedida) (*domain.UnidadMedida, error) {
+	unidad.Codigo = domain.NormalizarUnidad(unidad.Codigo)
+	unidad.Nombre = strings.TrimSpace(unidad.Nombre)
+	if unidad.Codigo == "" {
+		return nil, &domain.ErrValidation{Field: "codigo", Message: "es requerido"}
+	}
+	if unidad.Nombre == "" {
+		unidad.Nombre = unidad.Codigo
+	}
+	if existente, _ := s.repo.GetByCodigo(unidad.Codigo); existente != nil {
+		return nil, &domain.ErrDuplicate{Entity: "unidad de medida", Field: "codigo", Value: unidad.Codigo}
+	}
+	if err := s.repo.Create(unidad); err != nil {
+		return nil, err
+	}
+	return unidad, nil
+}
+
+func (s *unidadMedidaService) GetConversiones(productoID int) (*domain.Producto, []domain.ConversionUnidad, error) {
+	if productoID <= 0 {
+		return nil, nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	producto, err := s.productoRepo.GetByID(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	conversiones, err := s.repo.GetConversiones(productoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return producto, conversiones, nil
+}
+
+// SetConversion crea o actualiza cuántas unidades base del producto contiene 'unidad'
+func (s *unidadMedidaService) SetConversion(productoID int, unidad string, factor int) (*domain.ConversionUnidad, error) {
+	if productoID <= 0 {
+		return nil, &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	if factor <= 0 {
+		return nil, &domain.ErrValidation{Field: "factor", Message: "debe ser mayor a 0"}
+	}
+	producto, err := s.productoRepo.GetByID(productoID)
+	if err != nil {
+		return nil, err
+	}
+	unidad = domain.NormalizarUnidad(unidad)
+	if unidad == domain.NormalizarUnidad(producto.UnidadMedida) {
+		return nil, &domain.ErrValidation{Field: "unidad", Message: "es la unidad base del producto"}
+	}
+	if err := validarUnidad(s.repo, "unidad", unidad); err != nil {
+		return nil, err
+	}
+	conversion := &domain.ConversionUnidad{IDProducto: productoID, Unidad: unidad, Factor: factor}
+	if err := s.repo.SetConversion(conversion); err != nil {
+		return nil, err
+	}
+	return conversion, nil
+}
+
+func (s *unidadMedidaService) DeleteConversion(productoID int, unidad string) error {
+	if productoID <= 0 {
+		return &domain.ErrValidation{Field: "id", Message: "debe ser mayor a 0"}
+	}
+	return s.repo.DeleteConversion(productoID, domain.NormalizarUnidad(unidad))
+}
+
+// validarUnidad comprueba que la unidad exista en el catálogo
+func validarUnidad(repo domain.UnidadMedidaRepository, campo, unidad string) error {
+	_, err := repo.GetByCodigo(unidad)
+	var notFound *domain.ErrNotFound
+	if errors.As(err, &notFound) {
+		return unidadInexistente(campo, unidad)
+	}
+	return err
+}
+
+func unidadInexistente(campo, unidad string) error {
+	return &domain.ErrValidation{Field: campo, Message: "la unidad " + unidad + " no existe en el catálogo"}
+}
+
+// convertirCantidad lleva una cantidad expresada en 'unidad' a la unidad base del
+// producto y retorna también el factor aplicado
+func convertirCantidad(repo domain.UnidadMedidaRepository, producto *domain.Producto, unidad string, cantidad int) (int, int, error) {
+	unidad = domain.NormalizarUnidad(unidad)
+	if unidad == "" || unidad == domain.NormalizarUnidad(producto.UnidadMedida) {
+		return cantidad, 1, nil
+	}
+	conversiones, err := repo.GetConversiones(producto.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	factor, err := domain.FactorConversion(producto, conversiones, unidad)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cantidad * factor, factor, nil
+}
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/almacenamiento"
 	"github.com/Mishka-GDI-Back/infrastructure/config"
 	"github.com/Mishka-GDI-Back/infrastructure/database"
 	"github.com/Mishka-GDI-Back/infrastructure/http/handler"
 	"github.com/Mishka-GDI-Back/infrastructure/http/router"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
 	"github.com/Mishka-GDI-Back/infrastructure/persistence"
+	"github.com/Mishka-GDI-Back/infrastructure/scheduler"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,45 +34,106 @@ func main() {
 	defer db.Close()
 
 	// ── Repositorios (capa de infraestructura / persistencia) ──────────────
-	categoriaRepo := persistence.NewCategoriaRepository(db)
-	productoRepo  := persistence.NewProductoRepository(db)
-	entradaRepo   := persistence.NewEntradaProductoRepository(db)
-	salidaRepo    := persistence.NewSalidaProductoRepository(db)
-	controlRepo   := persistence.NewControlDiarioRepository(db)
-	resumenRepo   := persistence.NewResumenMensualRepository(db)
-	usuarioRepo   := persistence.NewUsuarioRepository(db)
-	reportesRepo  := persistence.NewReportesRepository(db)
-	alertasRepo   := persistence.NewAlertasRepository(db)
+	categoriaRepo    := persistence.NewCategoriaRepository(db)
+	productoRepo     := persistence.NewProductoRepository(db)
+	entradaRepo      := persistence.NewEntradaProductoRepository(db)
+	salidaRepo       := persistence.NewSalidaProductoRepository(db)
+	controlRepo      := persistence.NewControlDiarioRepository(db)
+	resumenRepo      := persistence.NewResumenMensualRepository(db)
+	usuarioRepo      := persistence.NewUsuarioRepository(db)
+	reportesRepo     := persistence.NewReportesRepository(db)
+	alertasRepo      := persistence.NewAlertasRepository(db)
+	codigoBarrasRepo := persistence.NewCodigoBarrasRepository(db)
+	unidadRepo       := persistence.NewUnidadMedidaRepository(db)
+	precioRepo       := persistence.NewPrecioRepository(db)
+	promocionRepo    := persistence.NewPromocionRepository(db)
+	comprobanteRepo  := persistence.NewComprobanteRepository(db)
+	clienteRepo      := persistence.NewClienteRepository(db)
+	devolucionRepo   := persistence.NewDevolucionRepository(db)
+	devProveedorRepo := persistence.NewDevolucionProveedorRepository(db)
+	archivoRepo      := persistence.NewArchivoRepository(db)
+
+	// ── Almacenamiento de imágenes y adjuntos ──────────────────────────────
+	almacen, err := almacenamiento.NewDiscoLocal(cfg.ArchivosDir)
+	if err != nil {
+		log.Fatalf("❌ Error al preparar el almacenamiento de archivos: %v", err)
+	}
+
+	// ── Configuración del negocio (comprobantes y reportes PDF) ────────────
+	// Las variables NEGOCIO_* son los valores iniciales hasta que se guarden otros
+	configuracionRepo    := persistence.NewConfiguracionRepository(db)
+	configuracionService := application.NewConfiguracionService(configuracionRepo, domain.DatosNegocio{
+		Nombre:    cfg.Negocio.Nombre,
+		RUC:       cfg.Negocio.RUC,
+		Direccion: cfg.Negocio.Direccion,
+		Telefono:  cfg.Negocio.Telefono,
+	})
 
 	// ── Servicios (capa de aplicación) ─────────────────────────────────────
 	categoriaService := application.NewCategoriaService(categoriaRepo)
-	productoService  := application.NewProductoService(productoRepo, categoriaRepo)
-	entradaService   := application.NewEntradaProductoService(entradaRepo, productoRepo)
-	salidaService    := application.NewSalidaProductoService(salidaRepo, productoRepo)
+	productoService  := application.NewProductoService(productoRepo, categoriaRepo, codigoBarrasRepo, unidadRepo, archivoRepo)
+	entradaService   := application.NewEntradaProductoService(entradaRepo, productoRepo, unidadRepo)
+	salidaService    := application.NewSalidaProductoService(salidaRepo, productoRepo, unidadRepo, promocionRepo, clienteRepo, categoriaRepo)
 	controlService   := application.NewControlDiarioService(controlRepo)
 	resumenService   := application.NewResumenMensualService(resumenRepo)
 	authService      := application.NewAuthService(usuarioRepo)
-	reportesService  := application.NewReportesService(reportesRepo)
-	alertasService   := application.NewAlertasService(alertasRepo)
+	reportesService  := application.NewReportesService(reportesRepo, productoRepo, unidadRepo, categoriaRepo)
+	alertasService   := application.NewAlertasService(alertasRepo, reportesRepo, categoriaRepo)
+	etiquetasService := application.NewEtiquetasService(productoRepo, codigoBarrasRepo)
+	unidadService    := application.NewUnidadMedidaService(unidadRepo, productoRepo)
+	precioService    := application.NewPrecioService(precioRepo, productoRepo)
+	promocionService := application.NewPromocionService(promocionRepo, productoRepo, categoriaRepo)
+	clienteService   := application.NewClienteService(clienteRepo)
+	archivoService   := application.NewArchivoService(archivoRepo, almacen, almacenamiento.NewMiniaturas(), productoRepo, entradaRepo, controlRepo)
+
+	comprobanteService  := application.NewComprobanteService(comprobanteRepo, salidaRepo, clienteRepo)
+	devolucionService   := application.NewDevolucionService(devolucionRepo, salidaRepo, productoRepo, clienteRepo)
+	devProveedorService := application.NewDevolucionProveedorService(devProveedorRepo, entradaRepo)
+
+	// ── Reportes PDF ────────────────────────────────────────────────────────
+	generadorPDF := pdf.NewGenerador(configuracionService)
 
 	// ── Handlers (capa de infraestructura / HTTP) ───────────────────────────
 	categoriaHandler := handler.NewCategoriaHandler(categoriaService)
 	productoHandler  := handler.NewProductoHandler(productoService)
 	entradaHandler   := handler.NewEntradaHandler(entradaService)
 	salidaHandler    := handler.NewSalidaHandler(salidaService)
-	controlHandler   := handler.NewControlDiarioHandler(controlService)
-	resumenHandler   := handler.NewResumenMensualHandler(resumenService)
+	controlHandler   := handler.NewControlDiarioHandler(controlService, generadorPDF)
+	resumenHandler   := handler.NewResumenMensualHandler(resumenService, generadorPDF)
 	authHandler      := handler.NewAuthHandler(authService)
-	reportesHandler  := handler.NewReportesHandler(reportesService)
+	reportesHandler  := handler.NewReportesHandler(reportesService, generadorPDF)
 	alertasHandler   := handler.NewAlertasHandler(alertasService)
+	etiquetasHandler := handler.NewEtiquetasHandler(etiquetasService)
+	unidadHandler    := handler.NewUnidadMedidaHandler(unidadService)
+	precioHandler    := handler.NewPrecioHandler(precioService)
+	promocionHandler := handler.NewPromocionHandler(promocionService)
+	clienteHandler   := handler.NewClienteHandler(clienteService)
+	devProvHandler   := handler.NewDevolucionProveedorHandler(devProveedorService)
+	archivoHandler   := handler.NewArchivoHandler(archivoService)
+
+	comprobanteHandler := handler.NewComprobanteHandler(comprobanteService, configuracionService)
+	devolucionHandler  := handler.NewDevolucionHandler(devolucionService)
 
 	// ── Router ──────────────────────────────────────────────────────────────
 	appRouter := router.NewRouter(
 		categoriaHandler, productoHandler, entradaHandler, salidaHandler,
 		controlHandler, resumenHandler, authHandler, reportesHandler, alertasHandler,
+		etiquetasHandler, unidadHandler, precioHandler, promocionHandler, comprobanteHandler,
+		clienteHandler, devolucionHandler, devProvHandler, archivoHandler,
 	)
 	ginRouter := appRouter.SetupRoutes()
 
+	// ── Tareas programadas ──────────────────────────────────────────────────
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Ejecutar(ctx, "precios programados", cfg.IntervaloProgramador, func() error {
+		aplicados, err := precioService.AplicarProgramados(time.Now())
+		if len(aplicados) > 0 {
+			log.Printf("💲 %d cambio(s) de precio programados aplicados", len(aplicados))
+		}
+		return err
+	})
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -83,5 +150,6 @@ func main() {
 
 	<-quit
 	log.Println("🛑 Cerrando servidor...")
+	cancel()
 	log.Println("👋 Servidor cerrado exitosamente")
 }
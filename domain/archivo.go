@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Entidades a las que se adjuntan archivos
+const (
+	EntidadProducto      = "PRODUCTO"
+	EntidadEntrada       = "ENTRADA"
+	EntidadControlDiario = "CONTROL_DIARIO"
+)
+
+// Un producto tiene imágenes; las entradas y el control diario, adjuntos (facturas del
+// proveedor, comprobantes de gastos)
+const (
+	TipoArchivoImagen  = "IMAGEN"
+	TipoArchivoAdjunto = "ADJUNTO"
+)
+
+const (
+	TamanoMaximoImagen     = 5 << 20
+	TamanoMaximoAdjunto    = 10 << 20
+	MaximoImagenesProducto = 10
+)
+
+// extensionesPorTipo son los tipos MIME admitidos, con la extensión con que se guardan
+var extensionesPorTipo = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+// Archivo es una imagen o un adjunto guardado en el almacenamiento. Ruta es relativa
+// al almacenamiento; RutaMiniatura solo se genera para las imágenes.
+type Archivo struct {
+	ID              int
+	Entidad         string
+	IDEntidad       int
+	Tipo            string
+	NombreOriginal  string
+	ContentType     string
+	Tamano          int64
+	Ruta            string
+	RutaMiniatura   string
+	Orden           int
+	UsuarioRegistro string
+	FechaCreacion   time.Time
+}
+
+func (a *Archivo) EsImagen() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// TipoArchivoEntidad indica si la entidad recibe imágenes o adjuntos
+func TipoArchivoEntidad(entidad string) string {
+	if entidad == EntidadProducto {
+		return TipoArchivoImagen
+	}
+	return TipoArchivoAdjunto
+}
+
+// ValidarArchivo verifica el tamaño y el tipo según lo que admite la entidad y
+// retorna la extensión con que se guarda. El tipo debe venir detectado del contenido,
+// no de lo que declara el cliente.
+func ValidarArchivo(entidad, contentType string, tamano int64) (string, error) {
+	if tamano <= 0 {
+		return "", &ErrValidation{Field: "archivo", Message: "el archivo está vacío"}
+	}
+	extension, ok := extensionesPorTipo[contentType]
+	tipo := TipoArchivoEntidad(entidad)
+	maximo := int64(TamanoMaximoAdjunto)
+	if tipo == TipoArchivoImagen {
+		maximo = TamanoMaximoImagen
+		if !strings.HasPrefix(contentType, "image/") {
+			ok = false
+		}
+	}
+	if !ok {
+		if tipo == TipoArchivoImagen {
+			return "", &ErrValidation{Field: "archivo", Message: "solo se admiten imágenes JPEG, PNG o GIF"}
+		}
+		return "", &ErrValidation{Field: "archivo", Message: "solo se admiten imágenes JPEG, PNG, GIF o documentos PDF"}
+	}
+	if tamano > maximo {
+		return "", &ErrValidation{Field: "archivo", Message: fmt.Sprintf("no puede superar %d MB", maximo>>20)}
+	}
+	return extension, nil
+}
+
+// NombreArchivo limpia el nombre original para guardarlo y para las descargas
+func NombreArchivo(nombre string) string {
+	nombre = strings.TrimSpace(path.Base(strings.ReplaceAll(nombre, "\\", "/")))
+	if nombre == "." || nombre == "/" {
+		nombre = ""
+	}
+	if r := []rune(nombre); len(r) > 200 {
+		nombre = string(r[len(r)-200:])
+	}
+	return nombre
+}
@@ -1,10 +1,129 @@
 package domain
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
+// Categoria inactiva es una categoría dada de baja: no admite productos nuevos pero se
+// conserva en los productos que ya la tenían y en los reportes. IDPadre nulo indica una
+// categoría raíz; el árbol puede tener cualquier profundidad.
 type Categoria struct {
 	ID                 int
 	Nombre             string
+	IDPadre            *int
+	Activo             bool
+	FechaBaja          *time.Time
 	FechaCreacion      time.Time
 	FechaActualizacion time.Time
 }
+
+const SinCategoria = "SIN CATEGORIA"
+
+// NodoCategoria es una categoría dentro del árbol con su nivel (1 en las raíces)
+type NodoCategoria struct {
+	Categoria
+	Nivel         int
+	Subcategorias []*NodoCategoria
+}
+
+// ArbolCategorias indexa las categorías por ID para recorrer el árbol en memoria
+type ArbolCategorias struct {
+	nodos  map[int]*NodoCategoria
+	raices []*NodoCategoria
+}
+
+// NuevoArbolCategorias arma el árbol. Una categoría cuyo padre no está en la lista
+// (p. ej. un padre inactivo que no se cargó) queda como raíz.
+func NuevoArbolCategorias(categorias []Categoria) *ArbolCategorias {
+	a := &ArbolCategorias{nodos: make(map[int]*NodoCategoria, len(categorias))}
+	for _, c := range categorias {
+		a.nodos[c.ID] = &NodoCategoria{Categoria: c}
+	}
+	for _, c := range categorias {
+		nodo := a.nodos[c.ID]
+		if c.IDPadre != nil {
+			if padre, ok := a.nodos[*c.IDPadre]; ok {
+				padre.Subcategorias = append(padre.Subcategorias, nodo)
+				continue
+			}
+		}
+		a.raices = append(a.raices, nodo)
+	}
+	ordenarNodos(a.raices, 1)
+	return a
+}
+
+func ordenarNodos(nodos []*NodoCategoria, nivel int) {
+	sort.Slice(nodos, func(i, j int) bool { return nodos[i].Nombre < nodos[j].Nombre })
+	for _, n := range nodos {
+		n.Nivel = nivel
+		ordenarNodos(n.Subcategorias, nivel+1)
+	}
+}
+
+func (a *ArbolCategorias) Raices() []*NodoCategoria {
+	return a.raices
+}
+
+func (a *ArbolCategorias) Nodo(id int) (*NodoCategoria, bool) {
+	n, ok := a.nodos[id]
+	return n, ok
+}
+
+// Ancestros retorna la cadena desde la raíz hasta la categoría, ella incluida
+func (a *ArbolCategorias) Ancestros(id int) []*NodoCategoria {
+	var cadena []*NodoCategoria
+	// El tope evita un bucle infinito si los datos llegaran a tener un ciclo
+	n, ok := a.nodos[id]
+	for ok && len(cadena) <= len(a.nodos) {
+		cadena = append(cadena, n)
+		if n.IDPadre == nil {
+			break
+		}
+		n, ok = a.nodos[*n.IDPadre]
+	}
+	for i, j := 0, len(cadena)-1; i < j; i, j = i+1, j-1 {
+		cadena[i], cadena[j] = cadena[j], cadena[i]
+	}
+	return cadena
+}
+
+// Ruta describe la categoría con sus ancestros, p. ej. "Bebidas > Gaseosas"
+func (a *ArbolCategorias) Ruta(id int) string {
+	cadena := a.Ancestros(id)
+	nombres := make([]string, len(cadena))
+	for i, n := range cadena {
+		nombres[i] = n.Nombre
+	}
+	return strings.Join(nombres, " > ")
+}
+
+// EsDescendiente indica si 'id' está debajo de 'ancestro' en el árbol
+func (a *ArbolCategorias) EsDescendiente(id, ancestro int) bool {
+	for _, n := range a.Ancestros(id) {
+		if n.ID == ancestro && n.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// Agrupar retorna la categoría en la que se acumula 'id' al consolidar en 'nivel':
+// su ancestro de ese nivel, o ella misma si es menos profunda. Con nivel 0 no se
+// consolida. Sin categoría retorna nil y SinCategoria.
+func (a *ArbolCategorias) Agrupar(id *int, nivel int) (*int, string) {
+	if id == nil {
+		return nil, SinCategoria
+	}
+	cadena := a.Ancestros(*id)
+	if len(cadena) == 0 {
+		return id, SinCategoria
+	}
+	destino := cadena[len(cadena)-1]
+	if nivel > 0 && nivel < len(cadena) {
+		destino = cadena[nivel-1]
+	}
+	return &destino.ID, a.Ruta(destino.ID)
+}
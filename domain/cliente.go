@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// TipoPagoCredito es el tipo de pago de las ventas al fiado: no ingresa dinero a
+// caja, genera una cuenta por cobrar que se cancela con pagos del cliente
+const TipoPagoCredito = "CREDITO"
+
+const (
+	DocumentoDNI = "DNI"
+	DocumentoRUC = "RUC"
+	DocumentoCE  = "CE"
+)
+
+const (
+	EstadoCuentaPendiente = "PENDIENTE"
+	EstadoCuentaPagada    = "PAGADA"
+)
+
+// Cliente es un comprador habitual. LimiteCredito nil no limita la deuda; DiasCredito
+// es el plazo para vencer cada venta al crédito.
+type Cliente struct {
+	ID                 int
+	Nombre             string
+	TipoDocumento      string
+	NumeroDocumento    string
+	Telefono           string
+	Direccion          string
+	Email              string
+	LimiteCredito      *float64
+	DiasCredito        int
+	Activo             bool
+	FechaCreacion      time.Time
+	FechaActualizacion time.Time
+}
+
+// ClienteConSaldo es el cliente con su deuda pendiente
+type ClienteConSaldo struct {
+	Cliente
+	Saldo float64
+}
+
+// CuentaPorCobrar es la deuda de una venta al crédito; Saldo baja con cada pago
+type CuentaPorCobrar struct {
+	ID               int
+	IDCliente        int
+	IDComprobante    int
+	Comprobante      string
+	FechaEmision     time.Time
+	FechaVencimiento time.Time
+	Monto            float64
+	Saldo            float64
+	Estado           string
+	FechaCreacion    time.Time
+}
+
+// DiasVencida retorna los días de atraso a la fecha dada; 0 si aún no vence
+func (c *CuentaPorCobrar) DiasVencida(fecha time.Time) int {
+	vencimiento := time.Date(c.FechaVencimiento.Year(), c.FechaVencimiento.Month(), c.FechaVencimiento.Day(), 0, 0, 0, 0, time.UTC)
+	dia := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, time.UTC)
+	return max(int(dia.Sub(vencimiento).Hours()/24), 0)
+}
+
+// PagoCliente es un abono del cliente. Se aplica a las cuentas indicadas en
+// Aplicaciones y entra a caja como un registro de control diario (IDControl).
+type PagoCliente struct {
+	ID              int
+	IDCliente       int
+	Fecha           time.Time
+	Monto           float64
+	TipoPago        string
+	Observaciones   string
+	UsuarioRegistro string
+	IDControl       int
+	Aplicaciones    []AplicacionPago
+	FechaCreacion   time.Time
+}
+
+// AplicacionPago es la parte de un pago que cancela una cuenta por cobrar
+type AplicacionPago struct {
+	IDCuenta int
+	Monto    float64
+}
+
+// AplicarPago reparte el monto entre las cuentas pendientes en el orden dado (las
+// más antiguas primero). Retorna lo que no se pudo aplicar.
+func AplicarPago(monto float64, cuentas []CuentaPorCobrar) ([]AplicacionPago, float64) {
+	var aplicaciones []AplicacionPago
+	restante := math.Round(monto*100) / 100
+	for _, c := range cuentas {
+		if restante <= 0 {
+			break
+		}
+		if c.Saldo <= 0 {
+			continue
+		}
+		aplicado := math.Min(restante, c.Saldo)
+		aplicaciones = append(aplicaciones, AplicacionPago{IDCuenta: c.ID, Monto: aplicado})
+		restante = math.Round((restante-aplicado)*100) / 100
+	}
+	return aplicaciones, restante
+}
+
+// Tipos de movimiento del estado de cuenta
+const (
+	MovimientoCargo = "CARGO"
+	MovimientoAbono = "ABONO"
+)
+
+// MovimientoCuenta es una línea del estado de cuenta: una venta al crédito (cargo) o
+// un pago (abono), con el saldo acumulado
+type MovimientoCuenta struct {
+	Fecha      time.Time
+	Tipo       string
+	Referencia string
+	Cargo      float64
+	Abono      float64
+	Saldo      float64
+}
+
+// EstadoCuenta lista los movimientos del cliente en el rango partiendo del saldo
+// anterior a Desde
+type EstadoCuenta struct {
+	Cliente Cliente
+	RangoFechas
+	SaldoInicial float64
+	Movimientos  []MovimientoCuenta
+	TotalCargos  float64
+	TotalAbonos  float64
+	SaldoFinal   float64
+}
+
+// Acumular calcula el saldo de cada movimiento y los totales del estado de cuenta
+func (e *EstadoCuenta) Acumular() {
+	saldo := e.SaldoInicial
+	e.TotalCargos, e.TotalAbonos = 0, 0
+	for i := range e.Movimientos {
+		m := &e.Movimientos[i]
+		saldo = math.Round((saldo+m.Cargo-m.Abono)*100) / 100
+		m.Saldo = saldo
+		e.TotalCargos += m.Cargo
+		e.TotalAbonos += m.Abono
+	}
+	e.TotalCargos = math.Round(e.TotalCargos*100) / 100
+	e.TotalAbonos = math.Round(e.TotalAbonos*100) / 100
+	e.SaldoFinal = saldo
+}
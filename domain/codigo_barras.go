@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TipoEAN13   = "EAN13"
+	TipoEAN8    = "EAN8"
+	TipoUPC     = "UPC"
+	TipoInterno = "INTERNO"
+)
+
+// CodigoBarras es uno de los códigos escaneables de un producto
+type CodigoBarras struct {
+	ID            int
+	IDProducto    int
+	Codigo        string
+	Tipo          string
+	FechaCreacion time.Time
+}
+
+// NormalizarCodigoBarras limpia el código, deduce el tipo cuando no se indica y valida
+// el dígito verificador de los códigos EAN/UPC
+func NormalizarCodigoBarras(codigo, tipo string) (string, string, error) {
+	codigo = strings.ReplaceAll(strings.TrimSpace(codigo), " ", "")
+	tipo = strings.ToUpper(strings.TrimSpace(tipo))
+	if codigo == "" {
+		return "", "", &ErrValidation{Field: "codigo_barras", Message: "es requerido"}
+	}
+	if len(codigo) > 50 {
+		return "", "", &ErrValidation{Field: "codigo_barras", Message: "no puede superar 50 caracteres"}
+	}
+	if tipo == "" {
+		tipo = TipoInterno
+		if soloDigitos(codigo) {
+			switch len(codigo) {
+			case 13:
+				tipo = TipoEAN13
+			case 12:
+				tipo = TipoUPC
+			case 8:
+				tipo = TipoEAN8
+			}
+		}
+	}
+	largos := map[string]int{TipoEAN13: 13, TipoUPC: 12, TipoEAN8: 8}
+	switch tipo {
+	case TipoEAN13, TipoUPC, TipoEAN8:
+		if !soloDigitos(codigo) || len(codigo) != largos[tipo] {
+			return "", "", &ErrValidation{Field: "codigo_barras", Message: fmt.Sprintf("un código %s debe tener %d dígitos", tipo, largos[tipo])}
+		}
+		if !digitoVerificadorValido(codigo) {
+			return "", "", &ErrValidation{Field: "codigo_barras", Message: "dígito verificador inválido"}
+		}
+	case TipoInterno:
+	default:
+		return "", "", &ErrValidation{Field: "tipo", Message: "debe ser EAN13, EAN8, UPC o INTERNO"}
+	}
+	return codigo, tipo, nil
+}
+
+// VariantesCodigoBarras retorna las formas equivalentes de un código escaneado:
+// un UPC-A de 12 dígitos es el mismo EAN-13 con un 0 inicial
+func VariantesCodigoBarras(codigo string) []string {
+	codigo = strings.ReplaceAll(strings.TrimSpace(codigo), " ", "")
+	variantes := []string{codigo}
+	if soloDigitos(codigo) {
+		switch {
+		case len(codigo) == 12:
+			variantes = append(variantes, "0"+codigo)
+		case len(codigo) == 13 && codigo[0] == '0':
+			variantes = append(variantes, codigo[1:])
+		}
+	}
+	return variantes
+}
+
+// digitoVerificadorValido aplica el módulo 10 de GS1 (pesos 3 y 1 desde la derecha)
+func digitoVerificadorValido(codigo string) bool {
+	suma := 0
+	cuerpo := codigo[:len(codigo)-1]
+	for i := range len(cuerpo) {
+		d := int(cuerpo[len(cuerpo)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		suma += d
+	}
+	return int(codigo[len(codigo)-1]-'0') == (10-suma%10)%10
+}
+
+func soloDigitos(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
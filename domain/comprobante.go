@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	TipoComprobanteBoleta = "BOLETA"
+	TipoComprobanteTicket = "TICKET"
+)
+
+// SerieComprobante numera los comprobantes de un lugar de venta. La serie activa sin
+// lugar es la predeterminada para los lugares que no tienen una propia.
+type SerieComprobante struct {
+	ID            int
+	Tipo          string
+	Serie         string
+	LugarVenta    string
+	Correlativo   int
+	Activa        bool
+	FechaCreacion time.Time
+}
+
+// Comprobante agrupa las salidas de una venta bajo un número correlativo de su
+// serie. Los importes incluyen IGV; Descuento suma promociones y descuentos manuales.
+type Comprobante struct {
+	ID              int
+	IDSerie         int
+	Tipo            string
+	Serie           string
+	Numero          int
+	IDCliente       *int
+	FechaEmision    time.Time
+	LugarVenta      string
+	TipoPago        string
+	UsuarioRegistro string
+	Subtotal        float64
+	Descuento       float64
+	OpGravada       float64
+	OpExonerada     float64
+	OpInafecta      float64
+	IGV             float64
+	Total           float64
+	FechaCreacion   time.Time
+}
+
+// Numeracion retorna la serie y el número como se imprimen, p. ej. B001-00000042
+func (c *Comprobante) Numeracion() string {
+	return fmt.Sprintf("%s-%08d", c.Serie, c.Numero)
+}
+
+// Totalizar calcula los importes del comprobante a partir de sus líneas
+func (c *Comprobante) Totalizar(lineas []SalidaProducto) {
+	c.Subtotal, c.Descuento, c.OpGravada, c.OpExonerada, c.OpInafecta, c.IGV, c.Total = 0, 0, 0, 0, 0, 0, 0
+	for _, l := range lineas {
+		c.Subtotal += l.Importe()
+		c.Descuento += l.DescuentoPromocion + l.Descuento
+		switch l.Afectacion {
+		case AfectacionExonerado:
+			c.OpExonerada += l.BaseImponible
+		case AfectacionInafecto:
+			c.OpInafecta += l.BaseImponible
+		default:
+			c.OpGravada += l.BaseImponible
+		}
+		c.IGV += l.IGV
+		c.Total += l.Total
+	}
+	for _, v := range []*float64{&c.Subtotal, &c.Descuento, &c.OpGravada, &c.OpExonerada, &c.OpInafecta, &c.IGV, &c.Total} {
+		*v = math.Round(*v*100) / 100
+	}
+}
+
+// LineaVenta es una salida a registrar en un comprobante; Componentes solo se
+// indica en los kits, cuyo stock se descuenta de ellos
+type LineaVenta struct {
+	Salida      *SalidaProducto
+	Componentes []ComponenteKit
+}
+
+// ComprobanteDetalle es el comprobante con sus líneas, listo para imprimir. Cliente
+// solo se indica si la venta tiene uno y Cuenta si fue al crédito.
+type ComprobanteDetalle struct {
+	Comprobante
+	Lineas  []SalidaConProducto
+	Cliente *Cliente
+	Cuenta  *CuentaPorCobrar
+}
+
+// Claves del almacén de configuración
+const (
+	ConfigNegocioNombre    = "negocio_nombre"
+	ConfigNegocioRUC       = "negocio_ruc"
+	ConfigNegocioDireccion = "negocio_direccion"
+	ConfigNegocioTelefono  = "negocio_telefono"
+	ConfigComprobantePie   = "comprobante_pie"
+)
+
+var ClavesConfiguracion = []string{ConfigNegocioNombre, ConfigNegocioRUC, ConfigNegocioDireccion, ConfigNegocioTelefono, ConfigComprobantePie}
+
+// DatosNegocio son los datos impresos en los comprobantes
+type DatosNegocio struct {
+	Nombre         string
+	RUC            string
+	Direccion      string
+	Telefono       string
+	PieComprobante string
+}
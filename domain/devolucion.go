@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Destino de la mercadería devuelta
+const (
+	DestinoDevolucionStock  = "STOCK"
+	DestinoDevolucionDanado = "DANADO"
+)
+
+// DevolucionCliente es la devolución de parte de una salida. Lo que vuelve a STOCK se
+// reingresa (en los kits, sus componentes); lo DANADO no. El reembolso sale de caja
+// (IDControl) o, con método CREDITO, descuenta la cuenta por cobrar de la venta (IDCuenta).
+type DevolucionCliente struct {
+	ID              int
+	IDSalida        int
+	IDProducto      int
+	Fecha           time.Time
+	Cantidad        int
+	Destino         string
+	MetodoReembolso string
+	MontoReembolso  float64
+	// BaseImponible e IGV desglosan el reembolso con la afectación y la tasa de la
+	// venta; rebajan el IGV de ventas del periodo de la devolución
+	BaseImponible   float64
+	IGV             float64
+	Motivo          string
+	UsuarioRegistro string
+	IDControl       *int
+	IDCuenta        *int
+	FechaCreacion   time.Time
+}
+
+// DevolucionConProducto es el modelo de lectura enriquecido con datos del producto y
+// el comprobante de la venta original
+type DevolucionConProducto struct {
+	DevolucionCliente
+	NombreProducto string
+	CodigoProducto string
+	Comprobante    string
+}
+
+// Devuelto resume lo ya devuelto de una salida
+type Devuelto struct {
+	Cantidad int
+	Monto    float64
+}
+
+// Reembolsable retorna el monto máximo a reembolsar por 'cantidad' unidades de la salida
+// y el reembolso sugerido: la parte proporcional del total cobrado, o todo lo que
+// queda cuando se devuelven las últimas unidades para no arrastrar redondeos
+func Reembolsable(salida *SalidaProducto, devuelto Devuelto, cantidad int) (maximo, sugerido float64) {
+	maximo = math.Round((salida.Total-devuelto.Monto)*100) / 100
+	if cantidad >= salida.Cantidad-devuelto.Cantidad {
+		return maximo, maximo
+	}
+	sugerido = math.Round(salida.Total*float64(cantidad)/float64(salida.Cantidad)*100) / 100
+	return maximo, math.Min(sugerido, maximo)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Cómo compensa el proveedor la mercadería devuelta
+const (
+	TipoCreditoNotaCredito = "NOTA_CREDITO"
+	TipoCreditoReembolso   = "REEMBOLSO"
+)
+
+const (
+	EstadoDevolucionPendiente = "PENDIENTE"
+	EstadoDevolucionLiquidada = "LIQUIDADA"
+)
+
+// DevolucionProveedor es la mercadería de una entrada que se devuelve al proveedor.
+// Descuenta el stock al registrarse y queda PENDIENTE hasta que el proveedor emite la
+// nota de crédito o reembolsa MontoEsperado; entonces se liquida con lo recibido.
+// BaseImponible e IGV son la parte proporcional de los de la entrada y rebajan el IGV
+// de compras del periodo de la devolución.
+type DevolucionProveedor struct {
+	ID               int
+	IDEntrada        int
+	IDProducto       int
+	Proveedor        string
+	Fecha            time.Time
+	Cantidad         int
+	TipoCredito      string
+	MontoEsperado    float64
+	BaseImponible    float64
+	IGV              float64
+	Motivo           string
+	UsuarioRegistro  string
+	Estado           string
+	NumeroNota       string
+	MontoLiquidado   float64
+	FechaLiquidacion *time.Time
+	// IDControl es el ingreso en caja de los reembolsos liquidados
+	IDControl     *int
+	FechaCreacion time.Time
+}
+
+// DevolucionProveedorConProducto es el modelo de lectura enriquecido con datos del producto
+type DevolucionProveedorConProducto struct {
+	DevolucionProveedor
+	NombreProducto string
+	CodigoProducto string
+}
+
+// Diferencia retorna lo que el proveedor quedó debiendo (o pagó de más) al liquidar
+func (d *DevolucionProveedor) Diferencia() float64 {
+	if d.Estado != EstadoDevolucionLiquidada {
+		return 0
+	}
+	return d.MontoEsperado - d.MontoLiquidado
+}
+
+// LiquidacionProveedor es la nota de crédito o el reembolso recibido del proveedor
+type LiquidacionProveedor struct {
+	Fecha           time.Time
+	Monto           float64
+	NumeroNota      string
+	UsuarioRegistro string
+}
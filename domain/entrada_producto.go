@@ -1,19 +1,37 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type EntradaProducto struct {
-	ID                 int
-	IDProducto         int
-	FechaEntrada       time.Time
-	Cantidad           int
-	PrecioUnitario     *float64
-	Observaciones      string
-	UsuarioRegistro    string
+	ID             int
+	IDProducto     int
+	FechaEntrada   time.Time
+	Cantidad       int
+	PrecioUnitario *float64
+	// Impuesto desglosa el IGV incluido en el importe pagado por la línea
+	Impuesto
+	// Proveedor es quien entregó la mercadería; agrupa las compras y las devoluciones
+	Proveedor       string
+	Observaciones   string
+	UsuarioRegistro string
+	// CodigoBarras identifica el producto al escanear cuando no se envía IDProducto; no se persiste
+	CodigoBarras string
+	// Unidad en la que viene Cantidad (CAJA, PAQUETE...); se convierte a la unidad base y no se persiste
+	Unidad             string
 	FechaCreacion      time.Time
 	FechaActualizacion time.Time
 }
 
+// Importe es lo pagado por la línea. Sale de la base y el IGV, que se calculan con el
+// precio enviado; PrecioUnitario es el costo por unidad base redondeado al céntimo y,
+// al comprar por caja, su producto por Cantidad puede no coincidir con lo pagado.
+func (e *EntradaProducto) Importe() float64 {
+	return math.Round((e.BaseImponible+e.IGV)*100) / 100
+}
+
 // EntradaConProducto es el modelo de lectura enriquecido con datos del producto
 type EntradaConProducto struct {
 	EntradaProducto
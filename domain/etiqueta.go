@@ -0,0 +1,58 @@
+package domain
+
+import "strings"
+
+const (
+	SimbologiaCode128 = "code128"
+	SimbologiaEAN13   = "ean13"
+)
+
+// Etiqueta son los datos impresos en una etiqueta de producto o de góndola
+type Etiqueta struct {
+	IDProducto     int
+	Nombre         string
+	PrecioUnitario float64
+	Codigo         string
+	Simbologia     string
+}
+
+// SolicitudEtiqueta indica cuántas copias imprimir de un producto en la hoja
+type SolicitudEtiqueta struct {
+	IDProducto int
+	Copias     int
+}
+
+// NuevaEtiqueta elige el código a imprimir. Sin simbología explícita se prefiere el
+// primer EAN-13/UPC del producto y, si no tiene, el código interno en Code128.
+func NuevaEtiqueta(p *Producto, codigos []CodigoBarras, simbologia string) (*Etiqueta, error) {
+	simbologia = strings.ToLower(strings.TrimSpace(simbologia))
+	e := &Etiqueta{IDProducto: p.ID, Nombre: p.Nombre, PrecioUnitario: p.PrecioUnitario}
+	ean := ""
+	for _, cb := range codigos {
+		if cb.Tipo == TipoEAN13 {
+			ean = cb.Codigo
+			break
+		}
+		if cb.Tipo == TipoUPC && ean == "" {
+			ean = "0" + cb.Codigo
+		}
+	}
+	switch simbologia {
+	case "":
+		if ean != "" {
+			e.Codigo, e.Simbologia = ean, SimbologiaEAN13
+		} else {
+			e.Codigo, e.Simbologia = p.Codigo, SimbologiaCode128
+		}
+	case SimbologiaEAN13:
+		if ean == "" {
+			return nil, &ErrValidation{Field: "simbologia", Message: "el producto no tiene un código EAN-13 o UPC registrado"}
+		}
+		e.Codigo, e.Simbologia = ean, SimbologiaEAN13
+	case SimbologiaCode128:
+		e.Codigo, e.Simbologia = p.Codigo, SimbologiaCode128
+	default:
+		return nil, &ErrValidation{Field: "simbologia", Message: "debe ser code128 o ean13"}
+	}
+	return e, nil
+}
@@ -0,0 +1,124 @@
+package domain
+
+const (
+	LimitePorDefecto = 50
+	LimiteMaximo     = 500
+)
+
+// Paginacion indica la página solicitada. Todos ignora la paginación (exportaciones).
+type Paginacion struct {
+	Pagina int
+	Limite int
+	Todos  bool
+}
+
+// Normalizar aplica los valores por defecto y el límite máximo por página
+func (p *Paginacion) Normalizar() error {
+	if p.Todos {
+		return nil
+	}
+	if p.Pagina < 0 {
+		return &ErrValidation{Field: "pagina", Message: "debe ser mayor a 0"}
+	}
+	if p.Limite < 0 {
+		return &ErrValidation{Field: "limite", Message: "debe ser mayor a 0"}
+	}
+	if p.Pagina == 0 {
+		p.Pagina = 1
+	}
+	if p.Limite == 0 {
+		p.Limite = LimitePorDefecto
+	}
+	if p.Limite > LimiteMaximo {
+		p.Limite = LimiteMaximo
+	}
+	return nil
+}
+
+func (p Paginacion) Offset() int {
+	if p.Pagina <= 1 {
+		return 0
+	}
+	return (p.Pagina - 1) * p.Limite
+}
+
+// TotalPaginas calcula las páginas necesarias para el total de registros
+func (p Paginacion) TotalPaginas(total int) int {
+	if p.Todos || p.Limite <= 0 {
+		return 1
+	}
+	return (total + p.Limite - 1) / p.Limite
+}
+
+// Orden indica el campo de ordenamiento y su dirección
+type Orden struct {
+	Campo string
+	Desc  bool
+}
+
+// RangoFechas filtra por fecha inclusiva (YYYY-MM-DD); vacío no filtra
+type RangoFechas struct {
+	Desde string
+	Hasta string
+}
+
+// Campos de ordenamiento admitidos por cada listado
+var (
+	CamposOrdenProductos     = []string{"nombre", "codigo", "precio", "stock", "fecha_creacion"}
+	CamposOrdenEntradas      = []string{"fecha", "cantidad", "precio", "producto", "usuario", "proveedor"}
+	CamposOrdenSalidas       = []string{"fecha", "cantidad", "total", "producto", "lugar", "usuario"}
+	CamposOrdenControlDiario = []string{"fecha", "monto_entrada", "monto_salida", "usuario"}
+)
+
+type FiltroProductos struct {
+	IDCategoria      *int
+	ClaseABC         string
+	IDProductoPadre  *int
+	IncluirInactivos bool
+	Orden
+	Paginacion
+}
+
+type FiltroEntradas struct {
+	RangoFechas
+	IDProducto  *int
+	IDCategoria *int
+	Usuario     string
+	Proveedor   string
+	Orden
+	Paginacion
+}
+
+type FiltroDevolucionesProveedor struct {
+	RangoFechas
+	Proveedor string
+	Estado    string
+}
+
+type FiltroSalidas struct {
+	RangoFechas
+	IDProducto  *int
+	IDCategoria *int
+	Lugar       string
+	Usuario     string
+	TipoPago    string
+	IDCliente   *int
+	Orden
+	Paginacion
+}
+
+type FiltroControlDiario struct {
+	RangoFechas
+	Usuario   string
+	EsVerbena *bool
+	Orden
+	Paginacion
+}
+
+// TotalesControlDiario son los totales de todos los registros que cumplen el filtro,
+// no solo los de la página devuelta
+type TotalesControlDiario struct {
+	Registros    int
+	MontoEntrada float64
+	MontoSalida  float64
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "math"
+
+const (
+	AfectacionGravado   = "GRAVADO"
+	AfectacionExonerado = "EXONERADO"
+	AfectacionInafecto  = "INAFECTO"
+
+	TasaIGVPorDefecto = 18.0
+)
+
+// Impuesto es el desglose de un importe que incluye IGV. Solo los gravados llevan
+// tasa; en exonerados e inafectos la base es todo el importe.
+type Impuesto struct {
+	Afectacion    string
+	Tasa          float64
+	BaseImponible float64
+	IGV           float64
+}
+
+// DesglosarIGV separa la base imponible y el IGV de 'importe' según la afectación y
+// la tasa del producto, redondeando a céntimos
+func DesglosarIGV(afectacion string, tasa, importe float64) Impuesto {
+	importe = math.Round(importe*100) / 100
+	if afectacion != AfectacionGravado || tasa <= 0 {
+		return Impuesto{Afectacion: afectacion, BaseImponible: importe}
+	}
+	base := math.Round(importe/(1+tasa/100)*100) / 100
+	return Impuesto{Afectacion: afectacion, Tasa: tasa, BaseImponible: base, IGV: math.Round((importe-base)*100) / 100}
+}
+
+// ReporteIGVItem totaliza ventas o compras de una afectación en el periodo
+type ReporteIGVItem struct {
+	Afectacion    string
+	Operaciones   int
+	BaseImponible float64
+	IGV           float64
+	Total         float64
+}
+
+// ReporteIGV compara el IGV cobrado en ventas (débito) con el pagado en entradas
+// (crédito). Un IGVPorPagar negativo es saldo a favor.
+type ReporteIGV struct {
+	Mes         int
+	Anio        int
+	Ventas      []ReporteIGVItem
+	Compras     []ReporteIGVItem
+	IGVVentas   float64
+	IGVCompras  float64
+	IGVPorPagar float64
+}
@@ -0,0 +1,83 @@
+package domain
+
+// Columnas que admite la importación de productos; "codigo" es obligatoria y las demás
+// son opcionales. En un producto existente, una celda vacía conserva el valor actual.
+const (
+	ColumnaCodigo         = "codigo"
+	ColumnaNombre         = "nombre"
+	ColumnaCategoria      = "categoria"
+	ColumnaUnidadMedida   = "unidad_medida"
+	ColumnaPrecioUnitario = "precio_unitario"
+	ColumnaStockActual    = "stock_actual"
+	ColumnaStockInicial   = "stock_inicial"
+	ColumnaAfectacionIGV  = "afectacion_igv"
+	ColumnaTasaIGV        = "tasa_igv"
+)
+
+var ColumnasImportacion = []string{
+	ColumnaCodigo, ColumnaNombre, ColumnaCategoria, ColumnaUnidadMedida, ColumnaPrecioUnitario,
+	ColumnaStockActual, ColumnaStockInicial, ColumnaAfectacionIGV, ColumnaTasaIGV,
+}
+
+const MaximoFilasImportacion = 5000
+
+const (
+	AccionCrear      = "CREAR"
+	AccionActualizar = "ACTUALIZAR"
+)
+
+// FilaImportacion es una fila del archivo tal como se leyó. Numero es la fila en la
+// hoja (el encabezado es la 1) para que los errores se puedan ubicar.
+type FilaImportacion struct {
+	Numero  int
+	Valores map[string]string
+}
+
+// ResultadoFilaImportacion indica qué se hará (o se hizo) con cada fila
+type ResultadoFilaImportacion struct {
+	Numero     int
+	Codigo     string
+	Accion     string
+	IDProducto *int
+	Errores    []string
+}
+
+// ResultadoImportacion resume la importación. Si alguna fila tiene errores no se
+// aplica nada; en una simulación tampoco.
+type ResultadoImportacion struct {
+	Simulacion       bool
+	Aplicada         bool
+	Filas            []ResultadoFilaImportacion
+	Creados          int
+	Actualizados     int
+	ConErrores       int
+	CategoriasNuevas []string
+}
+
+// CategoriaImportada es una categoría que no existe y se crea al importar. Ruta la
+// identifica (p. ej. "Bebidas > Gaseosas"); su padre es IDPadre si ya existía o
+// RutaPadre si también se crea en la importación.
+type CategoriaImportada struct {
+	Ruta      string
+	Nombre    string
+	IDPadre   *int
+	RutaPadre string
+}
+
+// ProductoImportado es un producto a crear (ID 0) o actualizar. El stock y el precio
+// solo se escriben si vinieron en el archivo, para no pisar con un valor leído antes
+// de la transacción lo que se vendió mientras tanto.
+type ProductoImportado struct {
+	Producto         Producto
+	RutaCategoria    string
+	ActualizarStock  bool
+	ActualizarPrecio bool
+}
+
+// ImportacionProductos es lo que se aplica en una sola transacción; las categorías
+// van con los padres antes que sus subcategorías
+type ImportacionProductos struct {
+	Categorias []CategoriaImportada
+	Productos  []ProductoImportado
+	Usuario    string
+}
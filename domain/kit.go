@@ -0,0 +1,31 @@
+package domain
+
+const (
+	TipoProductoSimple = "SIMPLE"
+	TipoProductoKit    = "KIT"
+)
+
+// ComponenteKit es una línea de la lista de materiales de un kit
+type ComponenteKit struct {
+	IDKit        int
+	IDComponente int
+	Cantidad     int
+	Codigo       string
+	Nombre       string
+	StockActual  int
+}
+
+// DisponibilidadKit retorna cuántos kits completos alcanzan con el stock de los componentes
+func DisponibilidadKit(componentes []ComponenteKit) int {
+	if len(componentes) == 0 {
+		return 0
+	}
+	disponible := -1
+	for _, c := range componentes {
+		n := max(c.StockActual, 0) / c.Cantidad
+		if disponible < 0 || n < disponible {
+			disponible = n
+		}
+	}
+	return disponible
+}
@@ -1,51 +1,190 @@
 package domain
 
+import (
+	"io"
+	"time"
+)
+
 // CategoriaRepository define el puerto de persistencia para categorías
 type CategoriaRepository interface {
-	GetAll() ([]Categoria, error)
+	GetAll(incluirInactivas bool) ([]Categoria, error)
 	GetByID(id int) (*Categoria, error)
 	Create(categoria *Categoria) error
 	Update(categoria *Categoria) error
+	// Delete da de baja la categoría sin borrarla; Restaurar la reactiva
 	Delete(id int) error
+	Restaurar(id int) error
 }
 
 // ProductoRepository define el puerto de persistencia para productos
 type ProductoRepository interface {
-	GetAll() ([]Producto, error)
+	GetAll(filtro FiltroProductos) ([]Producto, int, error)
 	GetByID(id int) (*Producto, error)
 	GetByCodigo(codigo string) (*Producto, error)
+	// GetByCodigos retorna los productos con esos códigos indexados por código
+	GetByCodigos(codigos []string) (map[string]Producto, error)
 	Create(producto *Producto) error
 	Update(producto *Producto) error
+	// Editar guarda la edición del producto y, en la misma transacción, registra el
+	// cambio de stock en los ajustes y el de precio en el historial. Update y Editar
+	// llevan las variantes a la categoría del padre.
+	Editar(producto *Producto, usuario string) error
+	// Delete da de baja el producto y sus variantes sin borrarlos; Restaurar reactiva el
+	// producto y las variantes que se dieron de baja junto con él
 	Delete(id int) error
+	Restaurar(id int) error
 	GetStockBajo(limite int) ([]Producto, error)
-	Search(termino string) ([]Producto, error)
+	Search(termino string, limite int, incluirInactivos bool) ([]Producto, error)
+	ActualizarClasesABC(clases map[int]string) error
+	GetByCodigoBarras(codigo string) (*Producto, error)
+	GetVariantes(padreID int) ([]Producto, error)
+	// GetPadresConVariantes retorna cuáles de los productos 'ids' tienen variantes
+	GetPadresConVariantes(ids []int) (map[int]bool, error)
+	// TieneMovimientos indica si el producto tiene entradas, salidas o ajustes de stock
+	TieneMovimientos(id int) (bool, error)
+	GetComponentes(kitID int) ([]ComponenteKit, error)
+	SetComponentes(kitID int, componentes []ComponenteKit) error
+	// Importar crea las categorías nuevas y crea o actualiza los productos en una sola
+	// transacción; los cambios de stock y de precio quedan en ajustes e historial y las
+	// variantes siguen a su padre si cambia de categoría
+	Importar(importacion *ImportacionProductos) error
+}
+
+// CodigoBarrasRepository define el puerto de persistencia para códigos de barras
+type CodigoBarrasRepository interface {
+	GetByProductoID(productoID int) ([]CodigoBarras, error)
+	GetByCodigo(codigo string) (*CodigoBarras, error)
+	Create(codigo *CodigoBarras) error
+	Delete(productoID, id int) error
+}
+
+// UnidadMedidaRepository define el puerto de persistencia para el catálogo de unidades
+// y las conversiones por producto
+type UnidadMedidaRepository interface {
+	GetAll() ([]UnidadMedida, error)
+	GetByCodigo(codigo string) (*UnidadMedida, error)
+	Create(unidad *UnidadMedida) error
+	GetConversiones(productoID int) ([]ConversionUnidad, error)
+	GetFactores(unidad string) (map[int]int, error)
+	SetConversion(conversion *ConversionUnidad) error
+	DeleteConversion(productoID int, unidad string) error
+}
+
+// PrecioRepository define el puerto de persistencia para el historial de precios y
+// los cambios programados
+type PrecioRepository interface {
+	GetHistorial(productoID int) ([]CambioPrecio, error)
+	GetProgramados(productoID int) ([]PrecioProgramado, error)
+	GetProgramadoByID(id int) (*PrecioProgramado, error)
+	GetPendientesHasta(fecha time.Time) ([]PrecioProgramado, error)
+	CreateProgramado(programado *PrecioProgramado) error
+	CancelarProgramado(id int) error
+	AplicarProgramado(programado *PrecioProgramado) (*CambioPrecio, error)
+	// GetParaActualizacionMasiva retorna los productos que alcanza la actualización
+	// con su precio actual en PrecioAnterior
+	GetParaActualizacionMasiva(actualizacion *ActualizacionMasivaPrecios) ([]CambioMasivoPrecio, error)
+	AplicarActualizacionMasiva(actualizacion *ActualizacionMasivaPrecios) ([]CambioMasivoPrecio, error)
 }
 
 // EntradaProductoRepository define el puerto de persistencia para entradas
 type EntradaProductoRepository interface {
-	GetAll() ([]EntradaConProducto, error)
+	GetAll(filtro FiltroEntradas) ([]EntradaConProducto, int, error)
 	GetByID(id int) (*EntradaConProducto, error)
 	GetByProductoID(productoID int) ([]EntradaConProducto, error)
 	GetByFecha(fecha string) ([]EntradaConProducto, error)
 	Create(entrada *EntradaProducto) error
 }
 
+// DevolucionProveedorRepository define el puerto de persistencia para devoluciones a proveedores
+type DevolucionProveedorRepository interface {
+	GetAll(filtro FiltroDevolucionesProveedor) ([]DevolucionProveedorConProducto, error)
+	GetByID(id int) (*DevolucionProveedorConProducto, error)
+	GetByEntrada(entradaID int) ([]DevolucionProveedorConProducto, error)
+	GetDevuelto(entradaID int) (int, error)
+	// Create vuelve a validar lo devuelto con la entrada bloqueada y descuenta el stock
+	// en la misma transacción
+	Create(devolucion *DevolucionProveedor) error
+	// Liquidar cierra la devolución pendiente; 'ingreso' solo se indica en los reembolsos
+	Liquidar(id int, liquidacion *LiquidacionProveedor, ingreso *ControlDiario) error
+}
+
 // SalidaProductoRepository define el puerto de persistencia para salidas
 type SalidaProductoRepository interface {
-	GetAll() ([]SalidaConProducto, error)
+	GetAll(filtro FiltroSalidas) ([]SalidaConProducto, int, error)
 	GetByID(id int) (*SalidaConProducto, error)
 	GetByProductoID(productoID int) ([]SalidaConProducto, error)
 	GetByFecha(fecha string) ([]SalidaConProducto, error)
 	GetByLugar(lugar string) ([]SalidaConProducto, error)
-	Create(salida *SalidaProducto) error
+	GetByComprobante(comprobanteID int) ([]SalidaConProducto, error)
+	// CreateVenta numera el comprobante con la serie de su lugar de venta, registra sus
+	// líneas y descuenta el stock (el de los componentes en los kits) en una sola
+	// transacción; si algo falla no se consume el número. En las ventas al crédito
+	// 'cuenta' trae el vencimiento y se registra por el total, dentro del límite del cliente.
+	CreateVenta(comprobante *Comprobante, lineas []LineaVenta, cuenta *CuentaPorCobrar) error
+}
+
+// DevolucionRepository define el puerto de persistencia para devoluciones de clientes
+type DevolucionRepository interface {
+	GetAll(rango RangoFechas) ([]DevolucionConProducto, error)
+	GetByID(id int) (*DevolucionConProducto, error)
+	GetBySalida(salidaID int) ([]DevolucionConProducto, error)
+	GetDevuelto(salidaID int) (Devuelto, error)
+	// Create vuelve a validar lo devuelto con la salida bloqueada, registra el reembolso
+	// (en caja o contra la cuenta por cobrar) y reingresa el stock en una sola
+	// transacción. 'componentes' solo se indica al reingresar un kit.
+	Create(devolucion *DevolucionCliente, componentes []ComponenteKit, reembolso *ControlDiario) error
+}
+
+// ComprobanteRepository define el puerto de persistencia para comprobantes y series
+type ComprobanteRepository interface {
+	GetByID(id int) (*Comprobante, error)
+	GetSeries() ([]SerieComprobante, error)
+	GetSerieByID(id int) (*SerieComprobante, error)
+	CreateSerie(serie *SerieComprobante) error
+	UpdateSerie(serie *SerieComprobante) error
+}
+
+// ConfiguracionRepository define el puerto de persistencia para la configuración clave/valor
+type ConfiguracionRepository interface {
+	GetAll() (map[string]string, error)
+	Set(valores map[string]string) error
+}
+
+// PromocionRepository define el puerto de persistencia para promociones
+type PromocionRepository interface {
+	GetAll(soloActivas bool) ([]Promocion, error)
+	GetByID(id int) (*Promocion, error)
+	Create(promocion *Promocion) error
+	Update(promocion *Promocion) error
+	// GetVigentes retorna las promociones activas cuyo rango de fechas incluye 'fecha'
+	GetVigentes(fecha time.Time) ([]Promocion, error)
+}
+
+// ClienteRepository define el puerto de persistencia para clientes y sus cuentas por cobrar
+type ClienteRepository interface {
+	GetAll(busqueda string) ([]ClienteConSaldo, error)
+	GetByID(id int) (*ClienteConSaldo, error)
+	GetByDocumento(tipo, numero string) (*Cliente, error)
+	Create(cliente *Cliente) error
+	Update(cliente *Cliente) error
+	GetCuentas(clienteID int, soloPendientes bool) ([]CuentaPorCobrar, error)
+	GetCuentaByComprobante(comprobanteID int) (*CuentaPorCobrar, error)
+	// GetCuentasPendientes retorna las cuentas con saldo de todos los clientes
+	GetCuentasPendientes() ([]CuentaPorCobrar, error)
+	// RegistrarPago descuenta las aplicaciones de los saldos, guarda el pago y su
+	// ingreso en control diario en una sola transacción
+	RegistrarPago(pago *PagoCliente, ingreso *ControlDiario) error
+	// GetMovimientos retorna el saldo anterior al rango y los cargos y abonos del rango
+	GetMovimientos(clienteID int, rango RangoFechas) (float64, []MovimientoCuenta, error)
 }
 
 // ControlDiarioRepository define el puerto de persistencia para control diario
 type ControlDiarioRepository interface {
-	GetAll() ([]ControlDiario, error)
+	GetAll(filtro FiltroControlDiario) ([]ControlDiario, *TotalesControlDiario, error)
 	GetByFecha(fecha string) ([]ControlDiario, error)
 	GetByFechaHoy() ([]ControlDiario, error)
 	GetVerbena() ([]ControlDiario, error)
+	GetByID(id int) (*ControlDiario, error)
 	Create(control *ControlDiario) error
 	GenerarDesdeVentas(fecha string) (*ControlDiario, error)
 }
@@ -57,6 +196,10 @@ type ResumenMensualRepository interface {
 	GetByProductoID(productoID, mes, anio int) (*ResumenProducto, error)
 	Generar(mes, anio int) (*ResumenMensual, error)
 	Upsert(resumen *ResumenMensual) error
+	GetByAnio(anio int) ([]ResumenMensual, error)
+	CalcularAnio(anio int) ([]ResumenMensual, error)
+	CalcularPeriodo(inicio, fin time.Time) (*ResumenPeriodo, error)
+	GetMatrizMovimientos(mes, anio int, idCategoria *int) ([]MovimientoMensualProducto, error)
 }
 
 // UsuarioRepository define el puerto de persistencia para usuarios
@@ -74,9 +217,42 @@ type ReportesRepository interface {
 	GetProductosMasVendidos(limite int) ([]ReporteProductoVendido, error)
 	GetProductosMasIngresados(limite int) ([]ReporteProductoVendido, error)
 	GetValoracionInventario() ([]ReporteValoracion, error)
+	GetVentasPorVentana(dias []int) ([]VentasProductoVentana, error)
+	GetIngresosPorProducto(inicio, fin time.Time) ([]ReporteProductoVendido, error)
+	GetStockInmovilizado(dias int) ([]ReporteStockInmovilizado, error)
+	GetDescuentosPorPromocion(rango RangoFechas) ([]ReportePromocion, error)
+	GetResumenIGV(inicio, fin time.Time) (ventas, compras []ReporteIGVItem, err error)
+	GetComprasPorProveedor(rango RangoFechas) ([]ReporteCompraProveedor, error)
+	// GetVentasPorCategoria totaliza por la categoría propia de cada producto; la
+	// consolidación en niveles del árbol se hace en la aplicación
+	GetVentasPorCategoria(rango RangoFechas) ([]ReporteVentasCategoria, error)
 }
 
 // AlertasRepository define el puerto de persistencia para alertas
 type AlertasRepository interface {
 	GetStockBajo(limite int) ([]AlertaStockBajo, error)
 }
+
+// ArchivoRepository define el puerto de persistencia para imágenes y adjuntos
+type ArchivoRepository interface {
+	GetByEntidad(entidad string, idEntidad int) ([]Archivo, error)
+	// GetByEntidades agrupa los archivos de varias entidades por su ID en una sola consulta
+	GetByEntidades(entidad string, ids []int) (map[int][]Archivo, error)
+	GetByID(id int) (*Archivo, error)
+	Contar(entidad string, idEntidad int) (int, error)
+	Create(archivo *Archivo) error
+	Delete(id int) error
+}
+
+// AlmacenamientoArchivos guarda el contenido de los archivos; las rutas son relativas
+// al almacenamiento y las genera la aplicación
+type AlmacenamientoArchivos interface {
+	Guardar(ruta string, contenido []byte) error
+	Abrir(ruta string) (io.ReadCloser, error)
+	Eliminar(ruta string) error
+}
+
+// ProcesadorImagenes genera las miniaturas de las imágenes subidas
+type ProcesadorImagenes interface {
+	Miniatura(contenido []byte) ([]byte, error)
+}
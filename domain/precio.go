@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	EstadoProgramadoPendiente = "PENDIENTE"
+	EstadoProgramadoAplicado  = "APLICADO"
+	EstadoProgramadoCancelado = "CANCELADO"
+)
+
+// Ajustes de la actualización masiva de precios
+const (
+	AjustePorcentaje = "PORCENTAJE"
+	AjusteMonto      = "MONTO"
+)
+
+// ActualizacionMasivaPrecios sube (o baja, con Valor negativo) el precio de los
+// productos activos que cumplen todos los criterios indicados: la categoría con sus
+// subcategorías, los que se compraron al proveedor o la lista de IDs. Redondeo es el
+// múltiplo al que se redondea el precio nuevo (p. ej. 0.10); 0 redondea al céntimo.
+type ActualizacionMasivaPrecios struct {
+	Tipo         string
+	Valor        float64
+	Redondeo     float64
+	IDCategoria  *int
+	Proveedor    string
+	IDsProductos []int
+	Usuario      string
+	Motivo       string
+}
+
+// PrecioNuevo aplica el ajuste y el redondeo al precio actual
+func (a *ActualizacionMasivaPrecios) PrecioNuevo(actual float64) float64 {
+	precio := actual + a.Valor
+	if a.Tipo == AjustePorcentaje {
+		precio = actual * (1 + a.Valor/100)
+	}
+	if a.Redondeo > 0 {
+		precio = math.Round(precio/a.Redondeo) * a.Redondeo
+	}
+	// El redondeo final al céntimo quita el ruido de punto flotante del múltiplo; sumar
+	// 0 convierte un -0 en 0
+	return math.Round(precio*100)/100 + 0
+}
+
+// CambioMasivoPrecio es el precio anterior y el nuevo de un producto en una
+// actualización masiva
+type CambioMasivoPrecio struct {
+	IDProducto     int
+	Codigo         string
+	Nombre         string
+	PrecioAnterior float64
+	PrecioNuevo    float64
+}
+
+// CambioPrecio es un cambio de precio_unitario ya aplicado. IDProgramado indica el
+// cambio programado que lo originó, si lo hubo.
+type CambioPrecio struct {
+	ID             int
+	IDProducto     int
+	PrecioAnterior float64
+	PrecioNuevo    float64
+	Usuario        string
+	Motivo         string
+	IDProgramado   *int
+	FechaCambio    time.Time
+}
+
+// PrecioProgramado es un cambio de precio que entra en vigencia en FechaVigencia
+type PrecioProgramado struct {
+	ID              int
+	IDProducto      int
+	PrecioNuevo     float64
+	FechaVigencia   time.Time
+	Usuario         string
+	Estado          string
+	FechaAplicacion *time.Time
+	FechaCreacion   time.Time
+}
+
+// PrecioEnFecha retorna el precio vigente al cierre del día 'fecha'. Para fechas
+// pasadas se reconstruye desde el historial (el precio anterior del primer cambio
+// posterior); para fechas futuras se aplican los cambios pendientes que ya estarían
+// vigentes. historial y pendientes pueden venir en cualquier orden.
+func PrecioEnFecha(p *Producto, historial []CambioPrecio, pendientes []PrecioProgramado, fecha time.Time) float64 {
+	// Se comparan días (YYYY-MM-DD) para no depender de la zona horaria de cada valor
+	dia := fecha.Format("2006-01-02")
+	sort.Slice(historial, func(i, j int) bool { return historial[i].FechaCambio.Before(historial[j].FechaCambio) })
+	for _, c := range historial {
+		if c.FechaCambio.Format("2006-01-02") > dia {
+			return c.PrecioAnterior
+		}
+	}
+	sort.Slice(pendientes, func(i, j int) bool { return pendientes[i].FechaVigencia.Before(pendientes[j].FechaVigencia) })
+	precio := p.PrecioUnitario
+	for _, pp := range pendientes {
+		if pp.Estado == EstadoProgramadoPendiente && pp.FechaVigencia.Format("2006-01-02") <= dia {
+			precio = pp.PrecioNuevo
+		}
+	}
+	return precio
+}
@@ -1,7 +1,16 @@
 package domain
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
+// Producto puede ser un producto simple, un padre que agrupa variantes, una
+// variante (IDProductoPadre no nulo) que se distingue por sus Atributos o un kit,
+// cuyo StockActual es la cantidad armable con el stock de sus componentes. Un producto
+// inactivo está descontinuado: no se vende ni se compra, pero conserva su historial.
+// Imagenes solo se carga en las consultas del catálogo.
 type Producto struct {
 	ID                 int
 	Codigo             string
@@ -11,6 +20,65 @@ type Producto struct {
 	PrecioUnitario     float64
 	StockActual        int
 	StockInicial       int
+	ClaseABC           string
+	Tipo               string
+	IDProductoPadre    *int
+	Atributos          map[string]string
+	AfectacionIGV      string
+	TasaIGV            float64
+	Activo             bool
+	FechaBaja          *time.Time
+	Imagenes           []Archivo
 	FechaCreacion      time.Time
 	FechaActualizacion time.Time
 }
+
+func (p *Producto) EsKit() bool {
+	return p.Tipo == TipoProductoKit
+}
+
+func (p *Producto) EsVariante() bool {
+	return p.IDProductoPadre != nil
+}
+
+// DescribirAtributos retorna los valores de los atributos ordenados por nombre,
+// p. ej. "Rojo / M" para {color: Rojo, talla: M}
+func DescribirAtributos(atributos map[string]string) string {
+	claves := make([]string, 0, len(atributos))
+	for k := range atributos {
+		claves = append(claves, k)
+	}
+	sort.Strings(claves)
+	valores := make([]string, len(claves))
+	for i, k := range claves {
+		valores[i] = atributos[k]
+	}
+	return strings.Join(valores, " / ")
+}
+
+// NormalizarAtributos limpia claves (minúsculas) y valores, y descarta los vacíos
+func NormalizarAtributos(atributos map[string]string) map[string]string {
+	limpios := make(map[string]string, len(atributos))
+	for k, v := range atributos {
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.TrimSpace(v)
+		if k != "" && v != "" {
+			limpios[k] = v
+		}
+	}
+	return limpios
+}
+
+// MismosAtributos indica si dos variantes tienen la misma combinación de atributos,
+// sin distinguir mayúsculas en los valores
+func MismosAtributos(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !strings.EqualFold(b[k], v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	TipoPromocionPorcentaje    = "PORCENTAJE"
+	TipoPromocionMontoFijo     = "MONTO_FIJO"
+	TipoPromocionNxM           = "NXM"
+	TipoPromocionPrecioPaquete = "PRECIO_PAQUETE"
+)
+
+// Promocion es una regla de descuento que se evalúa al registrar una venta. Se
+// aplica a un producto (y sus variantes), a una categoría o, sin ninguno, a todo.
+// Las fechas, horas (HH:MM), días (0 = domingo) y lugar vacíos no restringen.
+type Promocion struct {
+	ID            int
+	Nombre        string
+	Tipo          string
+	IDProducto    *int
+	IDCategoria   *int
+	Valor         float64
+	CantidadLleva int
+	CantidadPaga  int
+	FechaInicio   *time.Time
+	FechaFin      *time.Time
+	HoraInicio    string
+	HoraFin       string
+	DiasSemana    []int
+	LugarVenta    string
+	Activa        bool
+	FechaCreacion time.Time
+}
+
+// Aplica indica si la promoción alcanza al producto. Una promoción de categoría
+// alcanza también a las subcategorías: la de "Bebidas" rige en "Bebidas > Gaseosas".
+func (p *Promocion) Aplica(producto *Producto, arbol *ArbolCategorias) bool {
+	if p.IDProducto != nil {
+		return *p.IDProducto == producto.ID || (producto.IDProductoPadre != nil && *p.IDProducto == *producto.IDProductoPadre)
+	}
+	if p.IDCategoria != nil {
+		if producto.IDCategoria == nil {
+			return false
+		}
+		return *p.IDCategoria == *producto.IDCategoria || arbol.EsDescendiente(*producto.IDCategoria, *p.IDCategoria)
+	}
+	return true
+}
+
+// Vigente indica si la promoción rige para una venta del día 'fecha', hecha a la
+// 'hora' (HH:MM) en 'lugar'. Sin hora, las promociones con horario no rigen. Una
+// ventana horaria con fin menor al inicio cruza la medianoche.
+func (p *Promocion) Vigente(fecha time.Time, hora, lugar string) bool {
+	if !p.Activa {
+		return false
+	}
+	dia := fecha.Format("2006-01-02")
+	if p.FechaInicio != nil && dia < p.FechaInicio.Format("2006-01-02") {
+		return false
+	}
+	if p.FechaFin != nil && dia > p.FechaFin.Format("2006-01-02") {
+		return false
+	}
+	if len(p.DiasSemana) > 0 && !slices.Contains(p.DiasSemana, int(fecha.Weekday())) {
+		return false
+	}
+	if p.LugarVenta != "" && !strings.EqualFold(p.LugarVenta, strings.TrimSpace(lugar)) {
+		return false
+	}
+	switch {
+	case p.HoraInicio == "" && p.HoraFin == "":
+		return true
+	case hora == "":
+		return false
+	case p.HoraInicio == "":
+		return hora < p.HoraFin
+	case p.HoraFin == "":
+		return hora >= p.HoraInicio
+	case p.HoraInicio <= p.HoraFin:
+		return hora >= p.HoraInicio && hora < p.HoraFin
+	default:
+		return hora >= p.HoraInicio || hora < p.HoraFin
+	}
+}
+
+// Descuento calcula el descuento de la promoción sobre 'cantidad' unidades a
+// 'precio', redondeado a céntimos y nunca mayor al importe
+func (p *Promocion) Descuento(precio float64, cantidad int) float64 {
+	importe := precio * float64(cantidad)
+	var descuento float64
+	switch p.Tipo {
+	case TipoPromocionPorcentaje:
+		descuento = importe * p.Valor / 100
+	case TipoPromocionMontoFijo:
+		descuento = math.Min(p.Valor, precio) * float64(cantidad)
+	case TipoPromocionNxM:
+		if p.CantidadLleva > 0 {
+			descuento = float64(cantidad/p.CantidadLleva*(p.CantidadLleva-p.CantidadPaga)) * precio
+		}
+	case TipoPromocionPrecioPaquete:
+		if p.CantidadLleva > 0 {
+			paquetes := cantidad / p.CantidadLleva
+			descuento = float64(paquetes) * (float64(p.CantidadLleva)*precio - p.Valor)
+		}
+	}
+	descuento = math.Round(math.Max(0, math.Min(descuento, importe))*100) / 100
+	return descuento
+}
+
+// MejorPromocion retorna la promoción aplicable y vigente que da el mayor descuento
+// a la venta, o nil si ninguna descuenta nada. Las promociones no se acumulan.
+// 'ahora' da la hora de las ventas de hoy registradas sin hora.
+func MejorPromocion(promociones []Promocion, arbol *ArbolCategorias, producto *Producto, salida *SalidaProducto, ahora time.Time) (*Promocion, float64) {
+	hora := horaVenta(salida.FechaSalida, ahora)
+	var mejor *Promocion
+	var mayor float64
+	for i := range promociones {
+		p := &promociones[i]
+		if !p.Aplica(producto, arbol) || !p.Vigente(salida.FechaSalida, hora, salida.LugarVenta) {
+			continue
+		}
+		if d := p.Descuento(salida.PrecioVenta, salida.Cantidad); d > mayor {
+			mejor, mayor = p, d
+		}
+	}
+	return mejor, mayor
+}
+
+// horaVenta es la hora (HH:MM) de una venta con fecha 'fecha': la que trae la fecha o,
+// en una venta de hoy registrada sin hora, la de 'ahora'. De una venta de otro día
+// registrada sin hora no se conoce y retorna "".
+func horaVenta(fecha, ahora time.Time) string {
+	if h, m, _ := fecha.Clock(); h != 0 || m != 0 {
+		return fecha.Format("15:04")
+	}
+	if fecha.Format("2006-01-02") == ahora.Format("2006-01-02") {
+		return ahora.Format("15:04")
+	}
+	return ""
+}
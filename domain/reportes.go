@@ -11,6 +11,10 @@ type ReporteInventarioItem struct {
 	PrecioUnitario float64
 	StockActual    int
 	ValorTotal     float64
+	// UnidadReporte y CantidadReporte expresan el stock en la unidad pedida; vacíos si no
+	// se pidió o el producto no tiene esa conversión
+	UnidadReporte   string
+	CantidadReporte float64
 }
 
 type ReporteMovimiento struct {
@@ -33,8 +37,13 @@ type ReporteProductoVendido struct {
 	Categoria     string
 	TotalVendido  int
 	TotalIngresos float64
+	// UnidadReporte y CantidadReporte expresan TotalVendido en la unidad pedida
+	UnidadReporte   string
+	CantidadReporte float64
 }
 
+// ReporteValoracion se emite por categoría; al consolidar en un nivel del árbol cada
+// fila suma las de sus subcategorías y NombreCategoria es la ruta ("Bebidas > Gaseosas")
 type ReporteValoracion struct {
 	IDCategoria     int
 	NombreCategoria string
@@ -43,12 +52,185 @@ type ReporteValoracion struct {
 	ValorTotal      float64
 }
 
+// AlertaStockBajo se emite por variante; IDProductoPadre permite agruparlas
 type AlertaStockBajo struct {
+	IDProducto      int
+	Codigo          string
+	Nombre          string
+	IDCategoria     *int
+	Categoria       string
+	StockActual     int
+	StockInicial    int
+	PrecioUnitario  float64
+	IDProductoPadre *int
+	Atributos       map[string]string
+}
+
+// VentasProductoVentana son las unidades vendidas de un producto en cada ventana de días
+type VentasProductoVentana struct {
 	IDProducto     int
 	Codigo         string
 	Nombre         string
+	IDCategoria    *int
 	Categoria      string
 	StockActual    int
-	StockInicial   int
 	PrecioUnitario float64
+	Vendido        map[int]int
+}
+
+// VelocidadVenta es la venta promedio diaria de un producto en una ventana de días
+type VelocidadVenta struct {
+	DiasVentana    int
+	TotalVendido   int
+	PromedioDiario float64
+}
+
+// ParametrosCobertura configura el cálculo de cobertura de stock
+type ParametrosCobertura struct {
+	Ventanas     []int
+	VentanaBase  int
+	DiasObjetivo int
+}
+
+// ReporteCobertura proyecta los días de stock restantes y la reposición sugerida.
+// DiasCobertura y FechaQuiebre son nil cuando el producto no tiene ventas en la ventana base.
+type ReporteCobertura struct {
+	IDProducto       int
+	Codigo           string
+	Nombre           string
+	IDCategoria      *int
+	Categoria        string
+	StockActual      int
+	PrecioUnitario   float64
+	Velocidades      []VelocidadVenta
+	PromedioDiario   float64
+	DiasCobertura    *float64
+	FechaQuiebre     *time.Time
+	CantidadSugerida int
+}
+
+// AlertasActivas agrupa los distintos tipos de alerta vigentes y su cantidad por categoría
+type AlertasActivas struct {
+	StockBajo       []AlertaStockBajo
+	QuiebrePrevisto []ReporteCobertura
+	PorCategoria    []AlertasCategoria
+}
+
+// AlertasCategoria cuenta las alertas de una categoría y sus subcategorías
+type AlertasCategoria struct {
+	IDCategoria     *int
+	Categoria       string
+	StockBajo       int
+	QuiebrePrevisto int
+}
+
+// ReporteVentasCategoria totaliza las ventas de una categoría (y sus subcategorías al
+// consolidar en un nivel del árbol)
+type ReporteVentasCategoria struct {
+	IDCategoria *int
+	Categoria   string
+	Ventas      int
+	Unidades    int
+	Ingresos    float64
+}
+
+// Clases de la clasificación ABC
+const (
+	ClaseA = "A"
+	ClaseB = "B"
+	ClaseC = "C"
+)
+
+// FrecuenciaConteoDias retorna cada cuántos días conviene contar un producto según su clase ABC
+func FrecuenciaConteoDias(clase string) int {
+	switch clase {
+	case ClaseA:
+		return 30
+	case ClaseB:
+		return 90
+	default:
+		return 180
+	}
+}
+
+// ReporteABC es la clasificación de un producto según su participación en los ingresos del periodo
+type ReporteABC struct {
+	IDProducto             int
+	Codigo                 string
+	Nombre                 string
+	Categoria              string
+	TotalVendido           int
+	TotalIngresos          float64
+	Participacion          float64
+	ParticipacionAcumulada float64
+	Clase                  string
+	FrecuenciaConteoDias   int
+}
+
+// ReporteStockInmovilizado es un producto con stock y sin ventas en los últimos días.
+// UltimaVenta y DiasSinVenta son nil si el producto nunca se vendió.
+type ReporteStockInmovilizado struct {
+	IDProducto        int
+	Codigo            string
+	Nombre            string
+	Categoria         string
+	StockActual       int
+	PrecioUnitario    float64
+	ValorInmovilizado float64
+	UltimaVenta       *time.Time
+	DiasSinVenta      *int
+}
+
+// ReportePromocion resume las ventas con una promoción y el descuento que costó
+type ReportePromocion struct {
+	IDPromocion    int
+	Nombre         string
+	Tipo           string
+	Ventas         int
+	Unidades       int
+	Ingresos       float64
+	CostoDescuento float64
+}
+
+// ReporteCompraProveedor resume lo comprado a un proveedor en el rango y lo devuelto
+// de esas compras, con el crédito que aún debe
+type ReporteCompraProveedor struct {
+	Proveedor         string
+	Entradas          int
+	Unidades          int
+	MontoCompras      float64
+	UnidadesDevueltas int
+	MontoDevuelto     float64
+	MontoNeto         float64
+	CreditoPendiente  float64
+}
+
+// ReporteAntiguedad reparte la deuda pendiente de un cliente según los días de
+// atraso de cada cuenta a la fecha del reporte
+type ReporteAntiguedad struct {
+	IDCliente int
+	Cliente   string
+	PorVencer float64
+	Dias1a30  float64
+	Dias31a60 float64
+	Dias61a90 float64
+	Mas90     float64
+	Total     float64
+}
+
+// Sumar agrega el saldo de una cuenta al tramo que corresponde a sus días de atraso
+func (r *ReporteAntiguedad) Sumar(saldo float64, diasVencida int) {
+	switch {
+	case diasVencida == 0:
+		r.PorVencer += saldo
+	case diasVencida <= 30:
+		r.Dias1a30 += saldo
+	case diasVencida <= 60:
+		r.Dias31a60 += saldo
+	case diasVencida <= 90:
+		r.Dias61a90 += saldo
+	default:
+		r.Mas90 += saldo
+	}
+	r.Total += saldo
 }
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type ResumenMensual struct {
 	ID                   int
@@ -13,6 +16,7 @@ type ResumenMensual struct {
 	Observaciones        string
 	FechaGeneracion      time.Time
 	FechaActualizacion   time.Time
+	Calculado            bool
 }
 
 // ResumenProducto es el resumen de movimientos de un producto en un mes/año
@@ -25,3 +29,103 @@ type ResumenProducto struct {
 	TotalSalidas  int
 	MontoSalidas  float64
 }
+
+// ResumenAnual agrupa los doce meses de un año con sus totales y promedios.
+// Los meses sin resumen persistido se calculan al vuelo (Calculado = true).
+type ResumenAnual struct {
+	Anio                 int
+	Meses                []ResumenMensual
+	TotalIngresos        float64
+	TotalGastosFijos     float64
+	TotalGastosVariables float64
+	Balance              float64
+	MesesConsiderados    int
+	PromedioIngresos     float64
+	PromedioGastos       float64
+	PromedioBalance      float64
+	MejorMes             *ResumenMensual
+	PeorMes              *ResumenMensual
+}
+
+// ResumenPeriodo totaliza ingresos y gastos de un rango arbitrario de fechas
+type ResumenPeriodo struct {
+	Inicio        time.Time
+	Fin           time.Time
+	TotalIngresos float64
+	TotalGastos   float64
+	Balance       float64
+}
+
+// Variacion expresa el cambio de un periodo respecto a otro de referencia.
+// Los porcentajes son nil cuando el valor de referencia es cero.
+type Variacion struct {
+	Ingresos    float64
+	Gastos      float64
+	Balance     float64
+	IngresosPct *float64
+	GastosPct   *float64
+	BalancePct  *float64
+}
+
+// ComparativoAnio es el resumen de un mismo mes en un año dado
+type ComparativoAnio struct {
+	Resumen   ResumenMensual
+	Variacion *Variacion
+}
+
+// ComparativoMensual compara el mismo mes a lo largo de varios años
+type ComparativoMensual struct {
+	Mes   int
+	Anios []ComparativoAnio
+}
+
+// ComparativoPeriodo compara un periodo contra el periodo anterior
+type ComparativoPeriodo struct {
+	Actual    ResumenPeriodo
+	Anterior  ResumenPeriodo
+	Variacion Variacion
+}
+
+// TotalGastos retorna la suma de gastos fijos y variables del mes
+func (r *ResumenMensual) TotalGastos() float64 {
+	return r.TotalGastosFijos + r.TotalGastosVariables
+}
+
+// NuevaVariacion calcula la variación absoluta y porcentual entre dos periodos
+func NuevaVariacion(ingresos, gastos, balance, ingresosRef, gastosRef, balanceRef float64) Variacion {
+	return Variacion{
+		Ingresos:    ingresos - ingresosRef,
+		Gastos:      gastos - gastosRef,
+		Balance:     balance - balanceRef,
+		IngresosPct: porcentajeCambio(ingresos, ingresosRef),
+		GastosPct:   porcentajeCambio(gastos, gastosRef),
+		BalancePct:  porcentajeCambio(balance, balanceRef),
+	}
+}
+
+func porcentajeCambio(actual, referencia float64) *float64 {
+	if referencia == 0 {
+		return nil
+	}
+	pct := (actual - referencia) / math.Abs(referencia) * 100
+	return &pct
+}
+
+// MovimientoMensualProducto es una fila de la matriz de movimientos del mes:
+// StockCierre = StockApertura + Entradas - Salidas + Ajustes
+type MovimientoMensualProducto struct {
+	IDProducto     int
+	Codigo         string
+	Nombre         string
+	Categoria      string
+	UnidadMedida   string
+	PrecioUnitario float64
+	StockApertura  int
+	Entradas       int
+	MontoEntradas  float64
+	Salidas        int
+	MontoSalidas   float64
+	Ajustes        int
+	StockCierre    int
+	ValorCierre    float64
+}
@@ -1,27 +1,54 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type SalidaProducto struct {
-	ID                 int
-	IDProducto         int
-	FechaSalida        time.Time
-	Cantidad           int
-	PrecioVenta        float64
-	Descuento          float64
+	ID            int
+	IDProducto    int
+	IDComprobante *int
+	// IDCliente es opcional salvo en las ventas al crédito
+	IDCliente   *int
+	FechaSalida time.Time
+	Cantidad    int
+	PrecioVenta float64
+	Descuento   float64
+	// IDPromocion es la promoción aplicada al registrar la venta y DescuentoPromocion
+	// lo que descontó; Descuento queda para el descuento manual del cajero
+	IDPromocion        *int
+	DescuentoPromocion float64
 	Total              float64
-	LugarVenta         string
-	TipoPago           string
-	Observaciones      string
-	UsuarioRegistro    string
+	// Impuesto desglosa el IGV incluido en Total según el producto al momento de la venta
+	Impuesto
+	LugarVenta      string
+	TipoPago        string
+	Observaciones   string
+	UsuarioRegistro string
+	// CodigoBarras identifica el producto al escanear cuando no se envía IDProducto; no se persiste
+	CodigoBarras string
+	// Unidad en la que vienen Cantidad y PrecioVenta; se convierte a la unidad base y no se persiste
+	Unidad             string
 	FechaCreacion      time.Time
 	FechaActualizacion time.Time
 }
 
+// Importe es el importe de la línea antes de descuentos. Sale del total y no de
+// PrecioVenta × Cantidad: al vender por caja el precio por unidad base se guarda
+// redondeado al céntimo y ese producto ya no coincide con lo cobrado.
+func (s *SalidaProducto) Importe() float64 {
+	return math.Round((s.Total+s.DescuentoPromocion+s.Descuento)*100) / 100
+}
+
 // SalidaConProducto es el modelo de lectura enriquecido con datos del producto
 type SalidaConProducto struct {
 	SalidaProducto
 	NombreProducto  string
 	CodigoProducto  string
 	NombreCategoria string
+	NombrePromocion string
+	NombreCliente   string
+	// Comprobante es la numeración del comprobante de la venta (serie-número)
+	Comprobante string
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// UnidadMedida es una unidad del catálogo (UNIDAD, CAJA, PAQUETE...)
+type UnidadMedida struct {
+	ID            int
+	Codigo        string
+	Nombre        string
+	FechaCreacion time.Time
+}
+
+// ConversionUnidad indica cuántas unidades base del producto contiene Unidad
+type ConversionUnidad struct {
+	IDProducto int
+	Unidad     string
+	Factor     int
+}
+
+func NormalizarUnidad(unidad string) string {
+	return strings.ToUpper(strings.TrimSpace(unidad))
+}
+
+// FactorConversion retorna por cuánto multiplicar una cantidad en 'unidad' para
+// llevarla a la unidad base del producto. Vacío o la unidad base valen 1.
+func FactorConversion(p *Producto, conversiones []ConversionUnidad, unidad string) (int, error) {
+	unidad = NormalizarUnidad(unidad)
+	if unidad == "" || unidad == NormalizarUnidad(p.UnidadMedida) {
+		return 1, nil
+	}
+	for _, c := range conversiones {
+		if c.Unidad == unidad {
+			return c.Factor, nil
+		}
+	}
+	return 0, &ErrValidation{Field: "unidad", Message: "el producto " + p.Codigo + " no tiene conversión a " + unidad}
+}
@@ -0,0 +1,90 @@
+// Package almacenamiento guarda el contenido de las imágenes y adjuntos. DiscoLocal es
+// la implementación por defecto; otro almacenamiento (p. ej. uno en la nube) solo
+// necesita cumplir domain.AlmacenamientoArchivos.
+package almacenamiento
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+// DiscoLocal guarda los archivos debajo de un directorio base
+type DiscoLocal struct {
+	base string
+}
+
+func NewDiscoLocal(dir string) (*DiscoLocal, error) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return nil, fmt.Errorf("no se pudo crear el directorio de archivos %s: %w", base, err)
+	}
+	return &DiscoLocal{base: base}, nil
+}
+
+var _ domain.AlmacenamientoArchivos = (*DiscoLocal)(nil)
+
+// resolver convierte la ruta relativa en absoluta sin permitir que salga del directorio base
+func (d *DiscoLocal) resolver(ruta string) (string, error) {
+	completa := filepath.Join(d.base, filepath.FromSlash(ruta))
+	if !strings.HasPrefix(completa, d.base+string(filepath.Separator)) {
+		return "", fmt.Errorf("ruta de archivo inválida: %s", ruta)
+	}
+	return completa, nil
+}
+
+// Guardar escribe primero en un temporal y luego lo renombra, para que nunca quede un
+// archivo a medio escribir con el nombre definitivo
+func (d *DiscoLocal) Guardar(ruta string, contenido []byte) error {
+	completa, err := d.resolver(ruta)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(completa), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(completa), ".subida-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(contenido); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), completa)
+}
+
+func (d *DiscoLocal) Abrir(ruta string) (io.ReadCloser, error) {
+	completa, err := d.resolver(ruta)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(completa)
+	if os.IsNotExist(err) {
+		return nil, &domain.ErrNotFound{Entity: "archivo", ID: ruta}
+	}
+	return f, err
+}
+
+// Eliminar no falla si el archivo ya no existe
+func (d *DiscoLocal) Eliminar(ruta string) error {
+	completa, err := d.resolver(ruta)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(completa); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package almacenamiento
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+const (
+	// ladoMiniatura es el lado mayor de las miniaturas, en píxeles
+	ladoMiniatura = 256
+	// maximoPixeles evita decodificar imágenes enormes (p. ej. un PNG pequeño que
+	// declara 50000x50000) que agotarían la memoria; alcanza para fotos de 16 MP
+	maximoPixeles = 16_000_000
+)
+
+// Miniaturas genera miniaturas JPEG con la biblioteca estándar
+type Miniaturas struct{}
+
+func NewMiniaturas() *Miniaturas {
+	return &Miniaturas{}
+}
+
+var _ domain.ProcesadorImagenes = (*Miniaturas)(nil)
+
+// Miniatura reduce la imagen para que quepa en ladoMiniatura sin deformarla. Las
+// transparencias quedan sobre fondo blanco porque JPEG no las admite.
+func (m *Miniaturas) Miniatura(contenido []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(contenido))
+	if err != nil {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "la imagen está dañada o no se reconoce su formato"}
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maximoPixeles {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "la imagen tiene dimensiones inválidas o demasiado grandes"}
+	}
+	origen, _, err := image.Decode(bytes.NewReader(contenido))
+	if err != nil {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "la imagen está dañada o no se reconoce su formato"}
+	}
+
+	ancho, alto := escalar(cfg.Width, cfg.Height)
+	destino := reducir(origen, ancho, alto)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, destino, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escalar calcula el tamaño de la miniatura; las imágenes pequeñas no se agrandan
+func escalar(ancho, alto int) (int, int) {
+	if ancho <= ladoMiniatura && alto <= ladoMiniatura {
+		return ancho, alto
+	}
+	if ancho >= alto {
+		return ladoMiniatura, max(1, alto*ladoMiniatura/ancho)
+	}
+	return max(1, ancho*ladoMiniatura/alto), ladoMiniatura
+}
+
+// reducir promedia los píxeles de origen que caen en cada píxel de destino, cada uno
+// compuesto sobre blanco, sin copiar la imagen de origen
+func reducir(origen image.Image, ancho, alto int) *image.RGBA {
+	b := origen.Bounds()
+	destino := image.NewRGBA(image.Rect(0, 0, ancho, alto))
+	for y := 0; y < alto; y++ {
+		y0 := b.Min.Y + y*b.Dy()/alto
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/alto)
+		for x := 0; x < ancho; x++ {
+			x0 := b.Min.X + x*b.Dx()/ancho
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/ancho)
+			// Los colores vienen premultiplicados en 16 bits: sobre blanco se suma la
+			// parte transparente
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := origen.At(sx, sy).RGBA()
+					r += uint64(cr + 0xffff - ca)
+					g += uint64(cg + 0xffff - ca)
+					bl += uint64(cb + 0xffff - ca)
+					n++
+				}
+			}
+			destino.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(bl / n >> 8), A: 255})
+		}
+	}
+	return destino
+}
@@ -0,0 +1,51 @@
+package comprobantes
+
+import (
+	"bytes"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Comandos ESC/POS usados; son comunes a las impresoras térmicas compatibles con Epson
+var (
+	escInicializar = []byte{0x1B, '@'}
+	escPC850       = []byte{0x1B, 't', 2}
+	escFinal       = []byte{0x1B, 'd', 4, 0x1D, 'V', 66, 0} // avanzar y cortar parcialmente
+)
+
+// ESCPOS genera los bytes listos para enviar a la impresora térmica. El texto va en
+// la página de códigos PC850 para imprimir tildes y eñes; los caracteres que no
+// existen en ella se reemplazan.
+func ESCPOS(d *domain.ComprobanteDetalle, n *domain.DatosNegocio, columnas int) ([]byte, error) {
+	t := componer(d, n, columnas)
+	codificador := encoding.ReplaceUnsupported(charmap.CodePage850.NewEncoder())
+	var buf bytes.Buffer
+	buf.Write(escInicializar)
+	buf.Write(escPC850)
+	for _, l := range t.lineas {
+		buf.Write([]byte{0x1B, 'a', byte(l.alinear)})
+		buf.Write([]byte{0x1B, 'E', booleano(l.negrita)})
+		tamano := byte(0)
+		if l.grande {
+			tamano = 0x11
+		}
+		buf.Write([]byte{0x1D, '!', tamano})
+		texto, err := codificador.String(l.texto)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(texto)
+		buf.WriteByte('\n')
+	}
+	buf.Write(escFinal)
+	return buf.Bytes(), nil
+}
+
+func booleano(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
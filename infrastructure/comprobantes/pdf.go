@@ -0,0 +1,58 @@
+package comprobantes
+
+import (
+	"bytes"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/go-pdf/fpdf"
+)
+
+// Medidas del PDF en milímetros. Courier a 7 pt ocupa 1,48 mm por carácter, así que
+// las 48 columnas del papel de 80 mm caben en el ancho útil.
+const (
+	anchoPapel      = 80.0
+	margen          = 4.0
+	fuente          = "Courier"
+	tamanoNormal    = 7.0
+	altoLinea       = 3.2
+	altoLineaGrande = 6.0
+)
+
+// PDF dibuja el comprobante en una sola página de 80 mm de ancho, con el alto justo
+// para su contenido, como sale de la ticketera
+func PDF(d *domain.ComprobanteDetalle, n *domain.DatosNegocio) ([]byte, error) {
+	t := componer(d, n, columnasPapel[80])
+	alto := 2 * margen
+	for _, l := range t.lineas {
+		alto += altoDe(l)
+	}
+	p := fpdf.NewCustom(&fpdf.InitType{UnitStr: "mm", Size: fpdf.SizeType{Wd: anchoPapel, Ht: alto}})
+	p.SetMargins(margen, margen, margen)
+	p.SetAutoPageBreak(false, 0)
+	p.SetTitle(d.Numeracion(), true)
+	p.AddPage()
+	tr := p.UnicodeTranslatorFromDescriptor("")
+	for _, l := range t.lineas {
+		estilo, tamano := "", tamanoNormal
+		if l.negrita {
+			estilo = "B"
+		}
+		if l.grande {
+			tamano *= 2
+		}
+		p.SetFont(fuente, estilo, tamano)
+		p.CellFormat(anchoPapel-2*margen, altoDe(l), tr(l.texto), "", 1, [...]string{"L", "C", "R"}[l.alinear], false, 0, "")
+	}
+	var buf bytes.Buffer
+	if err := p.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func altoDe(l linea) float64 {
+	if l.grande {
+		return altoLineaGrande
+	}
+	return altoLinea
+}
@@ -0,0 +1,194 @@
+// Package comprobantes imprime los comprobantes de venta en PDF (80 mm) y en bytes
+// ESC/POS para impresoras térmicas. Ambos formatos parten del mismo texto en
+// columnas fijas, así el PDF se ve igual al ticket impreso.
+package comprobantes
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
+)
+
+const simboloMoneda = "S/"
+
+// Columnas que caben en cada ancho de papel con la fuente normal de la impresora
+var columnasPapel = map[int]int{58: 32, 80: 48}
+
+// Columnas retorna los caracteres por línea para un ancho de papel en milímetros
+func Columnas(papel int) (int, error) {
+	c, ok := columnasPapel[papel]
+	if !ok {
+		return 0, &domain.ErrValidation{Field: "papel", Message: "debe ser 58 u 80"}
+	}
+	return c, nil
+}
+
+type alineacion byte
+
+const (
+	izquierda alineacion = iota
+	centro
+	derecha
+)
+
+// linea es una línea del ticket; las grandes se imprimen a doble ancho y alto, así
+// que solo admiten la mitad de columnas
+type linea struct {
+	texto   string
+	alinear alineacion
+	negrita bool
+	grande  bool
+}
+
+type ticket struct {
+	columnas int
+	lineas   []linea
+}
+
+func (t *ticket) agregar(texto string, alinear alineacion, negrita bool) {
+	for _, l := range envolver(texto, t.columnas) {
+		t.lineas = append(t.lineas, linea{texto: l, alinear: alinear, negrita: negrita})
+	}
+}
+
+func (t *ticket) grande(texto string) {
+	for _, l := range envolver(texto, t.columnas/2) {
+		t.lineas = append(t.lineas, linea{texto: l, alinear: centro, negrita: true, grande: true})
+	}
+}
+
+// par imprime un texto a la izquierda y un importe a la derecha en la misma línea
+func (t *ticket) par(izq, der string, negrita bool) {
+	espacio := t.columnas - utf8.RuneCountInString(der) - 1
+	if utf8.RuneCountInString(izq) > espacio {
+		izq = string([]rune(izq)[:max(espacio, 0)])
+	}
+	relleno := t.columnas - utf8.RuneCountInString(izq) - utf8.RuneCountInString(der)
+	t.lineas = append(t.lineas, linea{texto: izq + strings.Repeat(" ", max(relleno, 1)) + der, negrita: negrita})
+}
+
+func (t *ticket) separador() {
+	t.lineas = append(t.lineas, linea{texto: strings.Repeat("-", t.columnas)})
+}
+
+func moneda(v float64) string {
+	return pdf.Numero(v, 2)
+}
+
+// componer arma el texto del comprobante: datos del negocio, numeración, líneas con
+// sus descuentos, totales con el desglose del IGV y el pago
+func componer(d *domain.ComprobanteDetalle, n *domain.DatosNegocio, columnas int) *ticket {
+	t := &ticket{columnas: columnas}
+	if n.Nombre != "" {
+		t.grande(n.Nombre)
+	}
+	if n.RUC != "" {
+		t.agregar("RUC "+n.RUC, centro, false)
+	}
+	if n.Direccion != "" {
+		t.agregar(n.Direccion, centro, false)
+	}
+	if n.Telefono != "" {
+		t.agregar("Tel. "+n.Telefono, centro, false)
+	}
+	t.separador()
+	titulo := "TICKET"
+	if d.Tipo == domain.TipoComprobanteBoleta {
+		titulo = "BOLETA DE VENTA"
+	}
+	t.agregar(titulo, centro, true)
+	t.agregar(d.Numeracion(), centro, true)
+	t.separador()
+	t.agregar("Fecha: "+d.FechaEmision.Format("02/01/2006 15:04"), izquierda, false)
+	if d.LugarVenta != "" {
+		t.agregar("Lugar: "+d.LugarVenta, izquierda, false)
+	}
+	t.agregar("Cajero: "+d.UsuarioRegistro, izquierda, false)
+	if d.Cliente != nil {
+		t.agregar("Cliente: "+d.Cliente.Nombre, izquierda, false)
+		if d.Cliente.NumeroDocumento != "" {
+			t.agregar(d.Cliente.TipoDocumento+": "+d.Cliente.NumeroDocumento, izquierda, false)
+		}
+	}
+	t.separador()
+	t.par("Descripción", "Importe", true)
+	for _, l := range d.Lineas {
+		t.agregar(l.NombreProducto, izquierda, false)
+		t.par(fmt.Sprintf("  %d x %s", l.Cantidad, moneda(l.PrecioVenta)), moneda(l.Importe()), false)
+		if l.DescuentoPromocion > 0 {
+			promocion := "Promoción"
+			if l.NombrePromocion != "" {
+				promocion = l.NombrePromocion
+			}
+			t.par("  "+promocion, "-"+moneda(l.DescuentoPromocion), false)
+		}
+		if l.Descuento > 0 {
+			t.par("  Descuento", "-"+moneda(l.Descuento), false)
+		}
+		if l.Afectacion != domain.AfectacionGravado {
+			t.lineas = append(t.lineas, linea{texto: "  (" + strings.ToLower(l.Afectacion) + ")"})
+		}
+	}
+	t.separador()
+	t.par("Subtotal", moneda(d.Subtotal), false)
+	if d.Descuento > 0 {
+		t.par("Descuentos", "-"+moneda(d.Descuento), false)
+	}
+	if d.OpGravada > 0 {
+		t.par("Op. gravada", moneda(d.OpGravada), false)
+	}
+	if d.OpExonerada > 0 {
+		t.par("Op. exonerada", moneda(d.OpExonerada), false)
+	}
+	if d.OpInafecta > 0 {
+		t.par("Op. inafecta", moneda(d.OpInafecta), false)
+	}
+	t.par("IGV", moneda(d.IGV), false)
+	t.par("TOTAL", simboloMoneda+" "+moneda(d.Total), true)
+	if d.TipoPago != "" {
+		t.agregar("Pago: "+d.TipoPago, izquierda, false)
+	}
+	if d.Cuenta != nil {
+		t.agregar("Vence: "+d.Cuenta.FechaVencimiento.Format("02/01/2006"), izquierda, false)
+	}
+	if n.PieComprobante != "" {
+		t.separador()
+		for _, parrafo := range strings.Split(n.PieComprobante, "\n") {
+			t.agregar(parrafo, centro, false)
+		}
+	}
+	return t
+}
+
+// envolver parte el texto por palabras en líneas de hasta 'ancho' caracteres; las
+// palabras más largas que la línea se cortan
+func envolver(texto string, ancho int) []string {
+	var lineas []string
+	var actual []rune
+	for _, palabra := range strings.Fields(texto) {
+		p := []rune(palabra)
+		if len(actual) > 0 && len(actual)+1+len(p) > ancho {
+			lineas = append(lineas, string(actual))
+			actual = nil
+		}
+		for len(p) > ancho {
+			if len(actual) > 0 {
+				lineas = append(lineas, string(actual))
+				actual = nil
+			}
+			lineas = append(lineas, string(p[:ancho]))
+			p = p[ancho:]
+		}
+		if len(actual) > 0 {
+			actual = append(actual, ' ')
+		}
+		actual = append(actual, p...)
+	}
+	if len(actual) > 0 || len(lineas) == 0 {
+		lineas = append(lineas, string(actual))
+	}
+	return lineas
+}
@@ -3,12 +3,27 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 )
 
 type Config struct {
 	PostgresURI string
 	Port        string
 	GinMode     string
+	Negocio     Negocio
+	// IntervaloProgramador es cada cuánto se revisan los cambios de precio programados
+	IntervaloProgramador time.Duration
+	// ArchivosDir es el directorio donde se guardan las imágenes y adjuntos
+	ArchivosDir string
+}
+
+// Negocio son los datos iniciales del negocio, usados mientras no se guarden en
+// la configuración
+type Negocio struct {
+	Nombre    string
+	RUC       string
+	Direccion string
+	Telefono  string
 }
 
 func NewConfig() *Config {
@@ -16,6 +31,13 @@ func NewConfig() *Config {
 		PostgresURI: os.Getenv("POSTGRES_URI"),
 		Port:        os.Getenv("PORT"),
 		GinMode:     os.Getenv("GIN_MODE"),
+		ArchivosDir: os.Getenv("ARCHIVOS_DIR"),
+		Negocio: Negocio{
+			Nombre:    os.Getenv("NEGOCIO_NOMBRE"),
+			RUC:       os.Getenv("NEGOCIO_RUC"),
+			Direccion: os.Getenv("NEGOCIO_DIRECCION"),
+			Telefono:  os.Getenv("NEGOCIO_TELEFONO"),
+		},
 	}
 	if cfg.PostgresURI == "" {
 		log.Fatal("POSTGRES_URI no esta definida en las variables de entorno")
@@ -26,5 +48,16 @@ func NewConfig() *Config {
 	if cfg.GinMode == "" {
 		cfg.GinMode = "debug"
 	}
+	if cfg.ArchivosDir == "" {
+		cfg.ArchivosDir = "archivos"
+	}
+	cfg.IntervaloProgramador = time.Hour
+	if v := os.Getenv("PROGRAMADOR_INTERVALO"); v != "" {
+		intervalo, err := time.ParseDuration(v)
+		if err != nil || intervalo <= 0 {
+			log.Fatalf("PROGRAMADOR_INTERVALO inválido: %q (use p. ej. 15m o 1h)", v)
+		}
+		cfg.IntervaloProgramador = intervalo
+	}
 	return cfg
 }
@@ -0,0 +1,92 @@
+package etiquetas
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+)
+
+// Codificar genera el código de barras de la etiqueta en su simbología
+func Codificar(e *domain.Etiqueta) (barcode.Barcode, error) {
+	switch e.Simbologia {
+	case domain.SimbologiaEAN13:
+		return ean.Encode(e.Codigo)
+	case domain.SimbologiaCode128:
+		return code128.Encode(e.Codigo)
+	default:
+		return nil, fmt.Errorf("simbología no soportada: %s", e.Simbologia)
+	}
+}
+
+// modulos retorna las barras del código como una fila de verdaderos (negro) y falsos
+func modulos(bc barcode.Barcode) []bool {
+	b := bc.Bounds()
+	fila := make([]bool, b.Dx())
+	for x := range fila {
+		r, _, _, _ := bc.At(b.Min.X+x, b.Min.Y).RGBA()
+		fila[x] = r == 0
+	}
+	return fila
+}
+
+// EscribirPNG escala el código a los píxeles indicados; el ancho se redondea a un
+// múltiplo del número de módulos para que las barras queden nítidas
+func EscribirPNG(w io.Writer, bc barcode.Barcode, ancho, alto int) error {
+	n := bc.Bounds().Dx()
+	ancho = max(ancho/n, 1) * n
+	escalado, err := barcode.Scale(bc, ancho, alto)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, escalado)
+}
+
+// EscribirSVG dibuja cada barra como un rectángulo, con el texto legible debajo
+func EscribirSVG(w io.Writer, bc barcode.Barcode, texto string, modulo, alto int) error {
+	fila := modulos(bc)
+	margen := 10 * modulo
+	ancho := len(fila)*modulo + 2*margen
+	altoTotal := alto + 14
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", ancho, altoTotal, ancho, altoTotal); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", ancho, altoTotal)
+	for x := 0; x < len(fila); {
+		if !fila[x] {
+			x++
+			continue
+		}
+		inicio := x
+		for x < len(fila) && fila[x] {
+			x++
+		}
+		fmt.Fprintf(w, `<rect x="%d" y="0" width="%d" height="%d" fill="#000"/>`+"\n", margen+inicio*modulo, (x-inicio)*modulo, alto)
+	}
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-family="monospace" font-size="12" text-anchor="middle">%s</text>`+"\n", ancho/2, alto+12, escaparXML(texto))
+	_, err := io.WriteString(w, "</svg>\n")
+	return err
+}
+
+func escaparXML(s string) string {
+	var b []byte
+	for _, r := range s {
+		switch r {
+		case '&':
+			b = append(b, "&amp;"...)
+		case '<':
+			b = append(b, "&lt;"...)
+		case '>':
+			b = append(b, "&gt;"...)
+		case '"':
+			b = append(b, "&quot;"...)
+		default:
+			b = append(b, string(r)...)
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,150 @@
+package etiquetas
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	anchoA4       = 210.0
+	altoA4        = 297.0
+	fuente        = "Helvetica"
+	simboloMoneda = "S/"
+)
+
+// Formato es la distribución de etiquetas en una hoja A4, en milímetros
+type Formato struct {
+	Columnas  int
+	Filas     int
+	Ancho     float64
+	Alto      float64
+	MargenIzq float64
+	MargenSup float64
+	SepH      float64
+	SepV      float64
+}
+
+// Formatos son las planchas autoadhesivas A4 más comunes
+var Formatos = map[string]Formato{
+	"3x8":  {Columnas: 3, Filas: 8, Ancho: 70, Alto: 37.125},
+	"3x7":  {Columnas: 3, Filas: 7, Ancho: 70, Alto: 42.43},
+	"2x7":  {Columnas: 2, Filas: 7, Ancho: 99.1, Alto: 38.1, MargenIzq: 4.65, MargenSup: 15.15, SepH: 2.5},
+	"4x10": {Columnas: 4, Filas: 10, Ancho: 48.5, Alto: 25.4, MargenIzq: 8, MargenSup: 21.5},
+	"5x13": {Columnas: 5, Filas: 13, Ancho: 38.1, Alto: 21.2, MargenIzq: 4.75, MargenSup: 10.7, SepH: 2.5},
+}
+
+// FormatoPersonalizado reparte la hoja en columnas x filas con márgenes de 10 mm
+func FormatoPersonalizado(columnas, filas int) (Formato, error) {
+	if columnas < 1 || columnas > 6 {
+		return Formato{}, &domain.ErrValidation{Field: "columnas", Message: "debe estar entre 1 y 6"}
+	}
+	if filas < 1 || filas > 20 {
+		return Formato{}, &domain.ErrValidation{Field: "filas", Message: "debe estar entre 1 y 20"}
+	}
+	f := Formato{Columnas: columnas, Filas: filas, MargenIzq: 10, MargenSup: 10, SepH: 2, SepV: 2}
+	f.Ancho = (anchoA4 - 2*f.MargenIzq - float64(columnas-1)*f.SepH) / float64(columnas)
+	f.Alto = (altoA4 - 2*f.MargenSup - float64(filas-1)*f.SepV) / float64(filas)
+	return f, nil
+}
+
+// BuscarFormato retorna un formato predefinido por nombre
+func BuscarFormato(nombre string) (Formato, error) {
+	f, ok := Formatos[strings.ToLower(strings.TrimSpace(nombre))]
+	if !ok {
+		return Formato{}, &domain.ErrValidation{Field: "formato", Message: "debe ser 3x8, 3x7, 2x7, 4x10 o 5x13"}
+	}
+	return f, nil
+}
+
+// HojaPDF dibuja las etiquetas en hojas A4, de izquierda a derecha y de arriba
+// abajo. Las barras se trazan como vectores para que el escáner las lea al imprimir.
+func HojaPDF(etiquetas []domain.Etiqueta, f Formato, mostrarPrecio bool) ([]byte, error) {
+	p := fpdf.New("P", "mm", "A4", "")
+	p.SetMargins(0, 0, 0)
+	p.SetAutoPageBreak(false, 0)
+	tr := p.UnicodeTranslatorFromDescriptor("")
+
+	porHoja := f.Columnas * f.Filas
+	for i, e := range etiquetas {
+		if i%porHoja == 0 {
+			p.AddPage()
+		}
+		pos := i % porHoja
+		x := f.MargenIzq + float64(pos%f.Columnas)*(f.Ancho+f.SepH)
+		y := f.MargenSup + float64(pos/f.Columnas)*(f.Alto+f.SepV)
+		if err := dibujarEtiqueta(p, tr, &e, x, y, f.Ancho, f.Alto, mostrarPrecio); err != nil {
+			return nil, fmt.Errorf("etiqueta del producto %d: %w", e.IDProducto, err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := p.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func dibujarEtiqueta(p *fpdf.Fpdf, tr func(string) string, e *domain.Etiqueta, x, y, ancho, alto float64, mostrarPrecio bool) error {
+	bc, err := Codificar(e)
+	if err != nil {
+		return err
+	}
+	relleno := min(2.0, ancho*0.05)
+	x, y, ancho = x+relleno, y+relleno, ancho-2*relleno
+	fin := y + alto - 2*relleno
+	// Tamaño de letra proporcional al alto de la etiqueta (1 pt = 0,3528 mm)
+	tamano := min(9.0, alto/8/0.3528)
+	altoTexto := tamano * 0.3528 * 1.2
+
+	// Nombre en dos líneas, o una en etiquetas pequeñas
+	p.SetFont(fuente, "B", tamano)
+	maxLineas := 2
+	if alto < 30 {
+		maxLineas = 1
+	}
+	lineas := p.SplitLines([]byte(tr(e.Nombre)), ancho)
+	if len(lineas) > maxLineas {
+		lineas = lineas[:maxLineas]
+	}
+	for _, l := range lineas {
+		p.SetXY(x, y)
+		p.CellFormat(ancho, altoTexto, string(l), "", 0, "C", false, 0, "")
+		y += altoTexto
+	}
+	if mostrarPrecio {
+		p.SetXY(x, y)
+		p.CellFormat(ancho, altoTexto, tr(simboloMoneda+" "+pdf.Numero(e.PrecioUnitario, 2)), "", 0, "C", false, 0, "")
+		y += altoTexto
+	}
+
+	// Barras y código legible ocupan el resto de la etiqueta
+	p.SetFont(fuente, "", tamano*0.85)
+	altoCodigo := tamano * 0.85 * 0.3528 * 1.2
+	altoBarras := fin - y - altoCodigo - 0.5
+	if altoBarras < 3 {
+		return &domain.ErrValidation{Field: "formato", Message: "la etiqueta es muy pequeña para el código de barras"}
+	}
+	fila := modulos(bc)
+	modulo := ancho / float64(len(fila)+10)
+	inicio := x + (ancho-modulo*float64(len(fila)))/2
+	p.SetFillColor(0, 0, 0)
+	for i := 0; i < len(fila); {
+		if !fila[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(fila) && fila[j] {
+			j++
+		}
+		p.Rect(inicio+float64(i)*modulo, y+0.5, float64(j-i)*modulo, altoBarras, "F")
+		i = j
+	}
+	p.SetXY(x, fin-altoCodigo)
+	p.CellFormat(ancho, altoCodigo, tr(e.Codigo), "", 0, "C", false, 0, "")
+	return nil
+}
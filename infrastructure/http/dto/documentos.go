@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
+)
+
+// =============================================
+// Documentos imprimibles (PDF)
+// =============================================
+
+func InventarioPDF(items []domain.ReporteInventarioItem) pdf.Documento {
+	tabla := InventarioTabla(items)
+	unidades, valor := 0, 0.0
+	for _, i := range items {
+		unidades += i.StockActual
+		valor += i.ValorTotal
+	}
+	return pdf.Documento{
+		Nombre:     "inventario_actual",
+		Titulo:     "Inventario actual",
+		Horizontal: true,
+		Secciones: []pdf.Seccion{{
+			Datos: []pdf.Dato{
+				{Etiqueta: "Productos:", Valor: pdf.Numero(float64(len(items)), 0)},
+				{Etiqueta: "Unidades en stock:", Valor: pdf.Numero(float64(unidades), 0)},
+				{Etiqueta: "Valor total:", Valor: pdf.Numero(valor, 2)},
+			},
+			Tabla:   &tabla,
+			Totales: []any{"TOTAL", nil, nil, nil, nil, nil, unidades, valor},
+		}},
+	}
+}
+
+func MovimientosPDF(inicio, fin string, items []domain.ReporteMovimiento) pdf.Documento {
+	tabla := MovimientosTabla(items)
+	var montoEntradas, montoSalidas float64
+	var unidadesEntradas, unidadesSalidas int
+	for _, i := range items {
+		if i.Tipo == "ENTRADA" {
+			montoEntradas += i.Total
+			unidadesEntradas += i.Cantidad
+		} else {
+			montoSalidas += i.Total
+			unidadesSalidas += i.Cantidad
+		}
+	}
+	return pdf.Documento{
+		Nombre:     fmt.Sprintf("movimientos_%s_%s", inicio, fin),
+		Titulo:     "Movimientos de inventario",
+		Subtitulo:  fmt.Sprintf("Del %s al %s", inicio, fin),
+		Horizontal: true,
+		Secciones: []pdf.Seccion{{
+			Datos: []pdf.Dato{
+				{Etiqueta: "Entradas:", Valor: fmt.Sprintf("%s unidades por %s", pdf.Numero(float64(unidadesEntradas), 0), pdf.Numero(montoEntradas, 2))},
+				{Etiqueta: "Salidas:", Valor: fmt.Sprintf("%s unidades por %s", pdf.Numero(float64(unidadesSalidas), 0), pdf.Numero(montoSalidas, 2))},
+			},
+			Tabla:   &tabla,
+			Totales: []any{"TOTAL", nil, nil, nil, nil, unidadesEntradas + unidadesSalidas, nil, montoEntradas + montoSalidas, nil, nil},
+		}},
+	}
+}
+
+func ResumenMensualPDF(r *domain.ResumenMensual, items []domain.MovimientoMensualProducto) pdf.Documento {
+	tabla := MatrizMovimientosTabla(r.Mes, r.Anio, items)
+	var montoEntradas, montoSalidas, valorCierre float64
+	var entradas, salidas, ajustes int
+	for _, i := range items {
+		entradas += i.Entradas
+		montoEntradas += i.MontoEntradas
+		salidas += i.Salidas
+		montoSalidas += i.MontoSalidas
+		ajustes += i.Ajustes
+		valorCierre += i.ValorCierre
+	}
+	datos := []pdf.Dato{
+		{Etiqueta: "Total ingresos:", Valor: pdf.Numero(r.TotalIngresos, 2)},
+		{Etiqueta: "Gastos fijos:", Valor: pdf.Numero(r.TotalGastosFijos, 2)},
+		{Etiqueta: "Gastos variables:", Valor: pdf.Numero(r.TotalGastosVariables, 2)},
+		{Etiqueta: "Balance:", Valor: pdf.Numero(r.Balance, 2)},
+	}
+	if r.Observaciones != "" {
+		datos = append(datos, pdf.Dato{Etiqueta: "Observaciones:", Valor: r.Observaciones})
+	}
+	if r.Calculado {
+		datos = append(datos, pdf.Dato{Etiqueta: "Nota:", Valor: "Resumen calculado a partir de los movimientos (no cerrado)"})
+	}
+	return pdf.Documento{
+		Nombre:     fmt.Sprintf("resumen_mensual_%04d_%02d", r.Anio, r.Mes),
+		Titulo:     "Resumen mensual",
+		Subtitulo:  fmt.Sprintf("%s %d", nombresMeses[r.Mes], r.Anio),
+		Horizontal: true,
+		Secciones: []pdf.Seccion{
+			{Titulo: "Resultados del mes", Datos: datos},
+			{
+				Titulo:  "Movimientos por producto",
+				Tabla:   &tabla,
+				Totales: []any{"TOTAL", nil, nil, nil, nil, nil, nil, entradas, montoEntradas, salidas, montoSalidas, ajustes, nil, valorCierre},
+			},
+		},
+	}
+}
+
+func ControlDiarioPDF(fecha string, controles []domain.ControlDiario) pdf.Documento {
+	tabla := ControlDiariosTabla(controles)
+	var totalEntrada, totalSalida float64
+	for _, c := range controles {
+		totalEntrada += c.MontoEntrada
+		totalSalida += c.MontoSalida
+	}
+	return pdf.Documento{
+		Nombre:    "control_diario_" + fecha,
+		Titulo:    "Control diario de caja",
+		Subtitulo: "Fecha: " + fecha,
+		Secciones: []pdf.Seccion{{
+			Datos: []pdf.Dato{
+				{Etiqueta: "Total entradas:", Valor: pdf.Numero(totalEntrada, 2)},
+				{Etiqueta: "Total salidas:", Valor: pdf.Numero(totalSalida, 2)},
+				{Etiqueta: "Saldo del día:", Valor: pdf.Numero(totalEntrada-totalSalida, 2)},
+			},
+			Tabla:   &tabla,
+			Totales: []any{"TOTAL", nil, nil, totalEntrada, totalSalida, nil, nil, nil},
+		}},
+	}
+}
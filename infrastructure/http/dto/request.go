@@ -5,11 +5,14 @@ package dto
 // =============================================
 
 type CreateCategoriaRequest struct {
-	Nombre string `json:"nombre" binding:"required,min=1,max=100"`
+	Nombre           string `json:"nombre" binding:"required,min=1,max=100"`
+	IDCategoriaPadre *int   `json:"id_categoria_padre"`
 }
 
+// UpdateCategoriaRequest reemplaza el padre: sin id_categoria_padre la categoría pasa a ser raíz
 type UpdateCategoriaRequest struct {
-	Nombre string `json:"nombre" binding:"required,min=1,max=100"`
+	Nombre           string `json:"nombre" binding:"required,min=1,max=100"`
+	IDCategoriaPadre *int   `json:"id_categoria_padre"`
 }
 
 // =============================================
@@ -24,16 +27,136 @@ type CreateProductoRequest struct {
 	PrecioUnitario float64 `json:"precio_unitario" binding:"min=0"`
 	StockActual    int     `json:"stock_actual" binding:"min=0"`
 	StockInicial   int     `json:"stock_inicial" binding:"min=0"`
+	Tipo           string  `json:"tipo"`
+	AfectacionIGV  string  `json:"afectacion_igv"`
+	TasaIGV        float64 `json:"tasa_igv" binding:"min=0"`
 }
 
 type UpdateProductoRequest struct {
-	Codigo         string  `json:"codigo" binding:"required,min=1,max=50"`
-	Nombre         string  `json:"nombre" binding:"required,min=1,max=200"`
-	IDCategoria    *int    `json:"id_categoria"`
-	UnidadMedida   string  `json:"unidad_medida" binding:"max=20"`
-	PrecioUnitario float64 `json:"precio_unitario" binding:"min=0"`
-	StockActual    int     `json:"stock_actual" binding:"min=0"`
-	StockInicial   int     `json:"stock_inicial" binding:"min=0"`
+	Codigo         string            `json:"codigo" binding:"required,min=1,max=50"`
+	Nombre         string            `json:"nombre" binding:"required,min=1,max=200"`
+	IDCategoria    *int              `json:"id_categoria"`
+	UnidadMedida   string            `json:"unidad_medida" binding:"max=20"`
+	PrecioUnitario float64           `json:"precio_unitario" binding:"min=0"`
+	StockActual    int               `json:"stock_actual" binding:"min=0"`
+	StockInicial   int               `json:"stock_inicial" binding:"min=0"`
+	Atributos      map[string]string `json:"atributos"`
+	// Sin afectacion_igv se conserva la configuración de IGV del producto
+	AfectacionIGV string  `json:"afectacion_igv"`
+	TasaIGV       float64 `json:"tasa_igv" binding:"min=0"`
+}
+
+// CreateVarianteRequest hereda del padre la categoría y la unidad; el nombre y el
+// precio también si se omiten
+type CreateVarianteRequest struct {
+	Codigo         string            `json:"codigo" binding:"required,min=1,max=50"`
+	Nombre         string            `json:"nombre" binding:"max=200"`
+	Atributos      map[string]string `json:"atributos" binding:"required"`
+	PrecioUnitario float64           `json:"precio_unitario" binding:"min=0"`
+	StockActual    int               `json:"stock_actual" binding:"min=0"`
+	StockInicial   int               `json:"stock_inicial" binding:"min=0"`
+}
+
+type ComponenteKitRequest struct {
+	IDProducto int `json:"id_producto" binding:"required,min=1"`
+	Cantidad   int `json:"cantidad" binding:"required,min=1"`
+}
+
+type SetComponentesRequest struct {
+	Componentes []ComponenteKitRequest `json:"componentes" binding:"required,min=1,dive"`
+}
+
+type CreateCodigoBarrasRequest struct {
+	Codigo string `json:"codigo" binding:"required,max=50"`
+	Tipo   string `json:"tipo"`
+}
+
+// =============================================
+// Promociones DTOs
+// =============================================
+
+// PromocionRequest sirve para crear y reemplazar una promoción. valor es el
+// porcentaje, el monto por unidad o el precio del paquete según el tipo.
+type PromocionRequest struct {
+	Nombre        string  `json:"nombre" binding:"required,max=100"`
+	Tipo          string  `json:"tipo" binding:"required"`
+	IDProducto    *int    `json:"id_producto"`
+	IDCategoria   *int    `json:"id_categoria"`
+	Valor         float64 `json:"valor" binding:"min=0"`
+	CantidadLleva int     `json:"cantidad_lleva" binding:"min=0"`
+	CantidadPaga  int     `json:"cantidad_paga" binding:"min=0"`
+	FechaInicio   string  `json:"fecha_inicio"` // YYYY-MM-DD
+	FechaFin      string  `json:"fecha_fin"`    // YYYY-MM-DD
+	HoraInicio    string  `json:"hora_inicio"`  // HH:MM
+	HoraFin       string  `json:"hora_fin"`     // HH:MM
+	DiasSemana    []int   `json:"dias_semana"`  // 0 = domingo ... 6 = sábado
+	LugarVenta    string  `json:"lugar_venta" binding:"max=100"`
+	Activa        *bool   `json:"activa"`
+}
+
+// =============================================
+// Precios DTOs
+// =============================================
+
+type ProgramarPrecioRequest struct {
+	PrecioNuevo   float64 `json:"precio_nuevo" binding:"min=0"`
+	FechaVigencia string  `json:"fecha_vigencia" binding:"required"` // YYYY-MM-DD
+}
+
+// ActualizacionMasivaPreciosRequest ajusta por porcentaje o por monto el precio de los
+// productos que cumplen los criterios; valor negativo baja los precios
+type ActualizacionMasivaPreciosRequest struct {
+	Tipo         string  `json:"tipo" binding:"required,oneof=PORCENTAJE MONTO"`
+	Valor        float64 `json:"valor"`
+	Redondeo     float64 `json:"redondeo" binding:"min=0"`
+	IDCategoria  *int    `json:"id_categoria"`
+	Proveedor    string  `json:"proveedor" binding:"max=150"`
+	IDsProductos []int   `json:"ids_productos"`
+	Motivo       string  `json:"motivo" binding:"max=200"`
+}
+
+// =============================================
+// Unidades de medida DTOs
+// =============================================
+
+type CreateUnidadMedidaRequest struct {
+	Codigo string `json:"codigo" binding:"required,max=20"`
+	Nombre string `json:"nombre" binding:"max=50"`
+}
+
+type SetConversionUnidadRequest struct {
+	Factor int `json:"factor" binding:"required,min=1"`
+}
+
+// =============================================
+// Etiquetas DTOs
+// =============================================
+
+// ImpresionQuery elige el ancho del papel térmico en milímetros (80 por defecto)
+type ImpresionQuery struct {
+	Papel int `form:"papel" binding:"omitempty,oneof=58 80"`
+}
+
+type EtiquetaQuery struct {
+	Formato    string `form:"formato" binding:"omitempty,oneof=svg png"`
+	Simbologia string `form:"simbologia" binding:"omitempty,oneof=code128 ean13"`
+	Ancho      int    `form:"ancho" binding:"omitempty,min=50,max=2000"`
+	Alto       int    `form:"alto" binding:"omitempty,min=20,max=1000"`
+}
+
+type EtiquetaProductoRequest struct {
+	IDProducto int `json:"id_producto" binding:"required,min=1"`
+	Copias     int `json:"copias" binding:"min=0,max=1000"`
+}
+
+// HojaEtiquetasRequest usa un formato predefinido o, si se indican, columnas y filas
+type HojaEtiquetasRequest struct {
+	Productos     []EtiquetaProductoRequest `json:"productos" binding:"required,min=1,dive"`
+	Formato       string                    `json:"formato"`
+	Columnas      int                       `json:"columnas" binding:"min=0"`
+	Filas         int                       `json:"filas" binding:"min=0"`
+	Simbologia    string                    `json:"simbologia" binding:"omitempty,oneof=code128 ean13"`
+	MostrarPrecio *bool                     `json:"mostrar_precio"`
 }
 
 // =============================================
@@ -41,10 +164,13 @@ type UpdateProductoRequest struct {
 // =============================================
 
 type CreateEntradaProductoRequest struct {
-	IDProducto      int      `json:"id_producto" binding:"required"`
+	IDProducto      int      `json:"id_producto"`
+	CodigoBarras    string   `json:"codigo_barras" binding:"max=50"`
+	Unidad          string   `json:"unidad" binding:"max=20"`
 	FechaEntrada    string   `json:"fecha_entrada" binding:"required"`
 	Cantidad        int      `json:"cantidad" binding:"required,min=1"`
 	PrecioUnitario  *float64 `json:"precio_unitario"`
+	Proveedor       string   `json:"proveedor" binding:"max=150"`
 	Observaciones   string   `json:"observaciones"`
 	UsuarioRegistro string   `json:"usuario_registro" binding:"required,max=100"`
 }
@@ -54,17 +180,120 @@ type CreateEntradaProductoRequest struct {
 // =============================================
 
 type CreateSalidaProductoRequest struct {
-	IDProducto      int     `json:"id_producto" binding:"required"`
+	IDProducto      int     `json:"id_producto"`
+	CodigoBarras    string  `json:"codigo_barras" binding:"max=50"`
+	Unidad          string  `json:"unidad" binding:"max=20"`
 	FechaSalida     string  `json:"fecha_salida" binding:"required"`
 	Cantidad        int     `json:"cantidad" binding:"required,min=1"`
 	PrecioVenta     float64 `json:"precio_venta" binding:"min=0"`
 	Descuento       float64 `json:"descuento" binding:"min=0"`
 	LugarVenta      string  `json:"lugar_venta" binding:"max=100"`
 	TipoPago        string  `json:"tipo_pago" binding:"max=50"`
+	IDCliente       *int    `json:"id_cliente"`
 	Observaciones   string  `json:"observaciones"`
 	UsuarioRegistro string  `json:"usuario_registro" binding:"required,max=100"`
 }
 
+// =============================================
+// Ventas y Comprobantes DTOs
+// =============================================
+
+// CreateVentaRequest registra varias líneas bajo un mismo comprobante
+type CreateVentaRequest struct {
+	FechaSalida     string             `json:"fecha_salida" binding:"required"`
+	LugarVenta      string             `json:"lugar_venta" binding:"max=100"`
+	TipoPago        string             `json:"tipo_pago" binding:"max=50"`
+	IDCliente       *int               `json:"id_cliente"`
+	UsuarioRegistro string             `json:"usuario_registro" binding:"required,max=100"`
+	Items           []VentaItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type VentaItemRequest struct {
+	IDProducto    int     `json:"id_producto"`
+	CodigoBarras  string  `json:"codigo_barras" binding:"max=50"`
+	Unidad        string  `json:"unidad" binding:"max=20"`
+	Cantidad      int     `json:"cantidad" binding:"required,min=1"`
+	PrecioVenta   float64 `json:"precio_venta" binding:"min=0"`
+	Descuento     float64 `json:"descuento" binding:"min=0"`
+	Observaciones string  `json:"observaciones"`
+}
+
+// SerieComprobanteRequest crea una serie; al actualizar se ignoran serie y correlativo
+type SerieComprobanteRequest struct {
+	Tipo        string `json:"tipo" binding:"max=10"`
+	Serie       string `json:"serie" binding:"max=4"`
+	LugarVenta  string `json:"lugar_venta" binding:"max=100"`
+	Correlativo int    `json:"correlativo" binding:"min=0"`
+	Activa      *bool  `json:"activa"`
+}
+
+// =============================================
+// Clientes DTOs
+// =============================================
+
+// ClienteRequest sirve para crear y reemplazar un cliente. Sin limite_credito no hay
+// tope de deuda; dias_credito es 30 si no se envía.
+type ClienteRequest struct {
+	Nombre          string   `json:"nombre" binding:"required,max=150"`
+	TipoDocumento   string   `json:"tipo_documento" binding:"max=3"`
+	NumeroDocumento string   `json:"numero_documento" binding:"max=15"`
+	Telefono        string   `json:"telefono" binding:"max=30"`
+	Direccion       string   `json:"direccion" binding:"max=200"`
+	Email           string   `json:"email" binding:"omitempty,email,max=100"`
+	LimiteCredito   *float64 `json:"limite_credito" binding:"omitempty,min=0"`
+	DiasCredito     *int     `json:"dias_credito" binding:"omitempty,min=0"`
+	Activo          *bool    `json:"activo"`
+}
+
+// PagoClienteRequest registra un abono; sin id_cuenta se aplica a las cuentas más antiguas
+type PagoClienteRequest struct {
+	Fecha           string  `json:"fecha" binding:"required"`
+	Monto           float64 `json:"monto" binding:"required,gt=0"`
+	IDCuenta        *int    `json:"id_cuenta"`
+	TipoPago        string  `json:"tipo_pago" binding:"max=50"`
+	Observaciones   string  `json:"observaciones"`
+	UsuarioRegistro string  `json:"usuario_registro" binding:"required,max=100"`
+}
+
+// =============================================
+// Devoluciones DTOs
+// =============================================
+
+// CreateDevolucionRequest devuelve parte de una salida. destino es STOCK (por defecto)
+// o DANADO; sin monto_reembolso se reembolsa la parte proporcional de lo cobrado y con
+// metodo_reembolso CREDITO se descuenta de la cuenta por cobrar de la venta.
+type CreateDevolucionRequest struct {
+	IDSalida        int      `json:"id_salida" binding:"required,min=1"`
+	Fecha           string   `json:"fecha" binding:"required"`
+	Cantidad        int      `json:"cantidad" binding:"required,min=1"`
+	Destino         string   `json:"destino" binding:"max=10"`
+	MetodoReembolso string   `json:"metodo_reembolso" binding:"max=50"`
+	MontoReembolso  *float64 `json:"monto_reembolso" binding:"omitempty,min=0"`
+	Motivo          string   `json:"motivo"`
+	UsuarioRegistro string   `json:"usuario_registro" binding:"required,max=100"`
+}
+
+// CreateDevolucionProveedorRequest devuelve parte de una entrada. tipo_credito es
+// NOTA_CREDITO (por defecto) o REEMBOLSO; sin monto_esperado se espera recuperar lo
+// pagado por esas unidades.
+type CreateDevolucionProveedorRequest struct {
+	IDEntrada       int      `json:"id_entrada" binding:"required,min=1"`
+	Fecha           string   `json:"fecha" binding:"required"`
+	Cantidad        int      `json:"cantidad" binding:"required,min=1"`
+	TipoCredito     string   `json:"tipo_credito" binding:"max=15"`
+	MontoEsperado   *float64 `json:"monto_esperado" binding:"omitempty,min=0"`
+	Motivo          string   `json:"motivo"`
+	UsuarioRegistro string   `json:"usuario_registro" binding:"required,max=100"`
+}
+
+// LiquidarDevolucionProveedorRequest registra la nota de crédito o el reembolso recibido
+type LiquidarDevolucionProveedorRequest struct {
+	Fecha           string  `json:"fecha" binding:"required"`
+	Monto           float64 `json:"monto" binding:"min=0"`
+	NumeroNota      string  `json:"numero_nota" binding:"max=30"`
+	UsuarioRegistro string  `json:"usuario_registro" binding:"required,max=100"`
+}
+
 // =============================================
 // Control Diario DTOs
 // =============================================
@@ -112,3 +341,52 @@ type RefreshTokenRequest struct {
 type ConfigurarAlertaRequest struct {
 	LimiteStockBajo int `json:"limite_stock_bajo" binding:"required,min=1"`
 }
+
+// =============================================
+// Filtros de listados (query string)
+// =============================================
+
+type ListadoQuery struct {
+	Pagina int    `form:"pagina" binding:"omitempty,min=1"`
+	Limite int    `form:"limite" binding:"omitempty,min=1"`
+	Orden  string `form:"orden"`
+	Dir    string `form:"dir" binding:"omitempty,oneof=asc desc"`
+}
+
+type ListadoProductosQuery struct {
+	ListadoQuery
+	IDCategoria      *int   `form:"id_categoria"`
+	ClaseABC         string `form:"clase_abc"`
+	IDProductoPadre  *int   `form:"id_producto_padre"`
+	IncluirInactivos bool   `form:"incluir_inactivos"`
+}
+
+type ListadoEntradasQuery struct {
+	ListadoQuery
+	Desde       string `form:"desde"`
+	Hasta       string `form:"hasta"`
+	IDProducto  *int   `form:"id_producto"`
+	IDCategoria *int   `form:"id_categoria"`
+	Usuario     string `form:"usuario"`
+	Proveedor   string `form:"proveedor"`
+}
+
+type ListadoSalidasQuery struct {
+	ListadoQuery
+	Desde       string `form:"desde"`
+	Hasta       string `form:"hasta"`
+	IDProducto  *int   `form:"id_producto"`
+	IDCategoria *int   `form:"id_categoria"`
+	Lugar       string `form:"lugar"`
+	Usuario     string `form:"usuario"`
+	TipoPago    string `form:"tipo_pago"`
+	IDCliente   *int   `form:"id_cliente"`
+}
+
+type ListadoControlDiarioQuery struct {
+	ListadoQuery
+	Desde     string `form:"desde"`
+	Hasta     string `form:"hasta"`
+	Usuario   string `form:"usuario"`
+	EsVerbena *bool  `form:"es_verbena"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Mishka-GDI-Back/domain"
@@ -22,10 +23,20 @@ type Response struct {
 // =============================================
 
 type CategoriaResponse struct {
-	ID                 int       `json:"id_categoria"`
-	Nombre             string    `json:"nombre"`
-	FechaCreacion      time.Time `json:"fecha_creacion"`
-	FechaActualizacion time.Time `json:"fecha_actualizacion"`
+	ID                 int        `json:"id_categoria"`
+	Nombre             string     `json:"nombre"`
+	IDCategoriaPadre   *int       `json:"id_categoria_padre"`
+	Activo             bool       `json:"activo"`
+	FechaBaja          *time.Time `json:"fecha_baja,omitempty"`
+	FechaCreacion      time.Time  `json:"fecha_creacion"`
+	FechaActualizacion time.Time  `json:"fecha_actualizacion"`
+}
+
+// CategoriaNodoResponse es una categoría del árbol con sus subcategorías anidadas
+type CategoriaNodoResponse struct {
+	CategoriaResponse
+	Nivel         int                     `json:"nivel"`
+	Subcategorias []CategoriaNodoResponse `json:"subcategorias"`
 }
 
 type CategoriasResponse struct {
@@ -40,25 +51,214 @@ type CategoriasResponse struct {
 // =============================================
 
 type ProductoResponse struct {
-	ID                 int       `json:"id_producto"`
-	Codigo             string    `json:"codigo"`
-	Nombre             string    `json:"nombre"`
-	IDCategoria        *int      `json:"id_categoria"`
-	UnidadMedida       string    `json:"unidad_medida"`
-	PrecioUnitario     float64   `json:"precio_unitario"`
-	StockActual        int       `json:"stock_actual"`
-	StockInicial       int       `json:"stock_inicial"`
-	FechaCreacion      time.Time `json:"fecha_creacion"`
-	FechaActualizacion time.Time `json:"fecha_actualizacion"`
+	ID                 int               `json:"id_producto"`
+	Codigo             string            `json:"codigo"`
+	Nombre             string            `json:"nombre"`
+	IDCategoria        *int              `json:"id_categoria"`
+	UnidadMedida       string            `json:"unidad_medida"`
+	PrecioUnitario     float64           `json:"precio_unitario"`
+	StockActual        int               `json:"stock_actual"`
+	StockInicial       int               `json:"stock_inicial"`
+	ClaseABC           string            `json:"clase_abc"`
+	Tipo               string            `json:"tipo"`
+	IDProductoPadre    *int              `json:"id_producto_padre,omitempty"`
+	Atributos          map[string]string `json:"atributos,omitempty"`
+	AfectacionIGV      string            `json:"afectacion_igv"`
+	TasaIGV            float64           `json:"tasa_igv"`
+	Activo             bool              `json:"activo"`
+	FechaBaja          *time.Time        `json:"fecha_baja,omitempty"`
+	Imagenes           []ArchivoResponse `json:"imagenes"`
+	FechaCreacion      time.Time         `json:"fecha_creacion"`
+	FechaActualizacion time.Time         `json:"fecha_actualizacion"`
 }
 
-type ProductosResponse struct {
+// VariantesResponse lista las variantes de un producto padre con su stock total
+type VariantesResponse struct {
 	Success    bool               `json:"success"`
 	Message    string             `json:"message"`
+	Padre      ProductoResponse   `json:"padre"`
 	Data       []ProductoResponse `json:"data"`
+	StockTotal int                `json:"stock_total"`
 	TotalCount int                `json:"total_count"`
 }
 
+type ComponenteKitResponse struct {
+	IDProducto  int    `json:"id_producto"`
+	Codigo      string `json:"codigo"`
+	Nombre      string `json:"nombre"`
+	Cantidad    int    `json:"cantidad"`
+	StockActual int    `json:"stock_actual"`
+	AlcanzaPara int    `json:"alcanza_para"`
+}
+
+// ComponentesKitResponse muestra la lista de materiales y cuántos kits se pueden armar
+type ComponentesKitResponse struct {
+	Success    bool                    `json:"success"`
+	Message    string                  `json:"message"`
+	Kit        ProductoResponse        `json:"kit"`
+	Data       []ComponenteKitResponse `json:"data"`
+	Disponible int                     `json:"disponible"`
+}
+
+type PromocionResponse struct {
+	ID            int       `json:"id_promocion"`
+	Nombre        string    `json:"nombre"`
+	Tipo          string    `json:"tipo"`
+	IDProducto    *int      `json:"id_producto"`
+	IDCategoria   *int      `json:"id_categoria"`
+	Valor         float64   `json:"valor"`
+	CantidadLleva int       `json:"cantidad_lleva"`
+	CantidadPaga  int       `json:"cantidad_paga"`
+	FechaInicio   *string   `json:"fecha_inicio"`
+	FechaFin      *string   `json:"fecha_fin"`
+	HoraInicio    string    `json:"hora_inicio"`
+	HoraFin       string    `json:"hora_fin"`
+	DiasSemana    []int     `json:"dias_semana"`
+	LugarVenta    string    `json:"lugar_venta"`
+	Activa        bool      `json:"activa"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+type CambioPrecioResponse struct {
+	ID             int       `json:"id_historial"`
+	PrecioAnterior float64   `json:"precio_anterior"`
+	PrecioNuevo    float64   `json:"precio_nuevo"`
+	Usuario        string    `json:"usuario"`
+	Motivo         string    `json:"motivo"`
+	IDProgramado   *int      `json:"id_programado"`
+	FechaCambio    time.Time `json:"fecha_cambio"`
+}
+
+type PrecioProgramadoResponse struct {
+	ID              int        `json:"id_programado"`
+	IDProducto      int        `json:"id_producto"`
+	PrecioNuevo     float64    `json:"precio_nuevo"`
+	FechaVigencia   string     `json:"fecha_vigencia"`
+	Usuario         string     `json:"usuario"`
+	Estado          string     `json:"estado"`
+	FechaAplicacion *time.Time `json:"fecha_aplicacion"`
+	FechaCreacion   time.Time  `json:"fecha_creacion"`
+}
+
+// PreciosProductoResponse acompaña el historial o los cambios programados con el
+// precio actual del producto
+type PreciosProductoResponse struct {
+	Success      bool    `json:"success"`
+	Message      string  `json:"message"`
+	IDProducto   int     `json:"id_producto"`
+	PrecioActual float64 `json:"precio_actual"`
+	Data         any     `json:"data"`
+	TotalCount   int     `json:"total_count"`
+}
+
+type CambioMasivoPrecioResponse struct {
+	IDProducto     int     `json:"id_producto"`
+	Codigo         string  `json:"codigo"`
+	Nombre         string  `json:"nombre"`
+	PrecioAnterior float64 `json:"precio_anterior"`
+	PrecioNuevo    float64 `json:"precio_nuevo"`
+}
+
+// ActualizacionMasivaResponse lista los precios que cambian; en una simulación no se
+// aplicó ninguno
+type ActualizacionMasivaResponse struct {
+	Simulacion bool                         `json:"simulacion"`
+	Total      int                          `json:"total"`
+	Cambios    []CambioMasivoPrecioResponse `json:"cambios"`
+}
+
+type PrecioVigenteResponse struct {
+	IDProducto int     `json:"id_producto"`
+	Codigo     string  `json:"codigo"`
+	Nombre     string  `json:"nombre"`
+	Fecha      string  `json:"fecha"`
+	Precio     float64 `json:"precio"`
+}
+
+type UnidadMedidaResponse struct {
+	ID            int       `json:"id_unidad"`
+	Codigo        string    `json:"codigo"`
+	Nombre        string    `json:"nombre"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+type ConversionUnidadResponse struct {
+	Unidad string `json:"unidad"`
+	Factor int    `json:"factor"`
+}
+
+// ConversionesResponse lista las unidades en que se puede mover un producto; el
+// factor indica cuántas unidades base contiene cada una
+type ConversionesResponse struct {
+	Success    bool                       `json:"success"`
+	Message    string                     `json:"message"`
+	IDProducto int                        `json:"id_producto"`
+	UnidadBase string                     `json:"unidad_base"`
+	Data       []ConversionUnidadResponse `json:"data"`
+}
+
+type ProductosResponse struct {
+	Success      bool               `json:"success"`
+	Message      string             `json:"message"`
+	Data         []ProductoResponse `json:"data"`
+	TotalCount   int                `json:"total_count"`
+	Pagina       int                `json:"pagina,omitempty"`
+	Limite       int                `json:"limite,omitempty"`
+	TotalPaginas int                `json:"total_paginas,omitempty"`
+}
+
+// ProductoEscaneoResponse es la respuesta liviana para el punto de venta con escáner
+type ProductoEscaneoResponse struct {
+	IDProducto     int     `json:"id_producto"`
+	Codigo         string  `json:"codigo"`
+	Nombre         string  `json:"nombre"`
+	UnidadMedida   string  `json:"unidad_medida"`
+	PrecioUnitario float64 `json:"precio_unitario"`
+	StockActual    int     `json:"stock_actual"`
+	MiniaturaURL   string  `json:"miniatura_url,omitempty"`
+}
+
+type ImportacionFilaResponse struct {
+	Fila       int      `json:"fila"`
+	Codigo     string   `json:"codigo"`
+	Accion     string   `json:"accion,omitempty"`
+	IDProducto *int     `json:"id_producto,omitempty"`
+	Errores    []string `json:"errores,omitempty"`
+}
+
+// ImportacionResponse resume la importación de productos fila por fila
+type ImportacionResponse struct {
+	Simulacion       bool                      `json:"simulacion"`
+	Aplicada         bool                      `json:"aplicada"`
+	Creados          int                       `json:"creados"`
+	Actualizados     int                       `json:"actualizados"`
+	ConErrores       int                       `json:"con_errores"`
+	CategoriasNuevas []string                  `json:"categorias_nuevas"`
+	Filas            []ImportacionFilaResponse `json:"filas"`
+}
+
+// ArchivoResponse describe una imagen o adjunto; el contenido se descarga desde URL
+type ArchivoResponse struct {
+	ID              int       `json:"id_archivo"`
+	Tipo            string    `json:"tipo"`
+	NombreOriginal  string    `json:"nombre_original"`
+	ContentType     string    `json:"content_type"`
+	Tamano          int64     `json:"tamano"`
+	Orden           int       `json:"orden"`
+	URL             string    `json:"url"`
+	MiniaturaURL    string    `json:"miniatura_url,omitempty"`
+	UsuarioRegistro string    `json:"usuario_registro"`
+	FechaCreacion   time.Time `json:"fecha_creacion"`
+}
+
+type CodigoBarrasResponse struct {
+	ID            int       `json:"id_codigo_barras"`
+	IDProducto    int       `json:"id_producto"`
+	Codigo        string    `json:"codigo"`
+	Tipo          string    `json:"tipo"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
 // =============================================
 // Entrada Producto Response (con datos del producto)
 // =============================================
@@ -72,6 +272,11 @@ type EntradaProductoResponse struct {
 	FechaEntrada       time.Time `json:"fecha_entrada"`
 	Cantidad           int       `json:"cantidad"`
 	PrecioUnitario     *float64  `json:"precio_unitario"`
+	AfectacionIGV      string    `json:"afectacion_igv"`
+	TasaIGV            float64   `json:"tasa_igv"`
+	BaseImponible      float64   `json:"base_imponible"`
+	IGV                float64   `json:"igv"`
+	Proveedor          string    `json:"proveedor"`
 	Observaciones      string    `json:"observaciones"`
 	UsuarioRegistro    string    `json:"usuario_registro"`
 	FechaCreacion      time.Time `json:"fecha_creacion"`
@@ -79,10 +284,13 @@ type EntradaProductoResponse struct {
 }
 
 type EntradasResponse struct {
-	Success    bool                      `json:"success"`
-	Message    string                    `json:"message"`
-	Data       []EntradaProductoResponse `json:"data"`
-	TotalCount int                       `json:"total_count"`
+	Success      bool                      `json:"success"`
+	Message      string                    `json:"message"`
+	Data         []EntradaProductoResponse `json:"data"`
+	TotalCount   int                       `json:"total_count"`
+	Pagina       int                       `json:"pagina,omitempty"`
+	Limite       int                       `json:"limite,omitempty"`
+	TotalPaginas int                       `json:"total_paginas,omitempty"`
 }
 
 // =============================================
@@ -92,6 +300,10 @@ type EntradasResponse struct {
 type SalidaProductoResponse struct {
 	ID                 int       `json:"id_salida"`
 	IDProducto         int       `json:"id_producto"`
+	IDComprobante      *int      `json:"id_comprobante"`
+	Comprobante        string    `json:"comprobante"`
+	IDCliente          *int      `json:"id_cliente"`
+	NombreCliente      string    `json:"nombre_cliente"`
 	CodigoProducto     string    `json:"codigo_producto"`
 	NombreProducto     string    `json:"nombre_producto"`
 	NombreCategoria    string    `json:"nombre_categoria"`
@@ -99,7 +311,14 @@ type SalidaProductoResponse struct {
 	Cantidad           int       `json:"cantidad"`
 	PrecioVenta        float64   `json:"precio_venta"`
 	Descuento          float64   `json:"descuento"`
+	IDPromocion        *int      `json:"id_promocion"`
+	NombrePromocion    string    `json:"nombre_promocion"`
+	DescuentoPromocion float64   `json:"descuento_promocion"`
 	Total              float64   `json:"total"`
+	AfectacionIGV      string    `json:"afectacion_igv"`
+	TasaIGV            float64   `json:"tasa_igv"`
+	BaseImponible      float64   `json:"base_imponible"`
+	IGV                float64   `json:"igv"`
 	LugarVenta         string    `json:"lugar_venta"`
 	TipoPago           string    `json:"tipo_pago"`
 	Observaciones      string    `json:"observaciones"`
@@ -109,10 +328,182 @@ type SalidaProductoResponse struct {
 }
 
 type SalidasResponse struct {
-	Success    bool                     `json:"success"`
-	Message    string                   `json:"message"`
-	Data       []SalidaProductoResponse `json:"data"`
-	TotalCount int                      `json:"total_count"`
+	Success      bool                     `json:"success"`
+	Message      string                   `json:"message"`
+	Data         []SalidaProductoResponse `json:"data"`
+	TotalCount   int                      `json:"total_count"`
+	Pagina       int                      `json:"pagina,omitempty"`
+	Limite       int                      `json:"limite,omitempty"`
+	TotalPaginas int                      `json:"total_paginas,omitempty"`
+}
+
+// =============================================
+// Comprobantes Response
+// =============================================
+
+type ComprobanteResponse struct {
+	ID              int       `json:"id_comprobante"`
+	Tipo            string    `json:"tipo"`
+	Serie           string    `json:"serie"`
+	Numero          int       `json:"numero"`
+	Numeracion      string    `json:"numeracion"`
+	IDCliente       *int      `json:"id_cliente"`
+	NombreCliente   string    `json:"nombre_cliente,omitempty"`
+	FechaEmision    time.Time `json:"fecha_emision"`
+	LugarVenta      string    `json:"lugar_venta"`
+	TipoPago        string    `json:"tipo_pago"`
+	UsuarioRegistro string    `json:"usuario_registro"`
+	Subtotal        float64   `json:"subtotal"`
+	Descuento       float64   `json:"descuento"`
+	OpGravada       float64   `json:"op_gravada"`
+	OpExonerada     float64   `json:"op_exonerada"`
+	OpInafecta      float64   `json:"op_inafecta"`
+	IGV             float64   `json:"igv"`
+	Total           float64   `json:"total"`
+	// Cuenta es la cuenta por cobrar de las ventas al crédito
+	Cuenta *CuentaPorCobrarResponse `json:"cuenta_por_cobrar,omitempty"`
+	Lineas []SalidaProductoResponse `json:"lineas"`
+}
+
+type SerieComprobanteResponse struct {
+	ID            int       `json:"id_serie"`
+	Tipo          string    `json:"tipo"`
+	Serie         string    `json:"serie"`
+	LugarVenta    string    `json:"lugar_venta"`
+	Correlativo   int       `json:"correlativo"`
+	Activa        bool      `json:"activa"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// =============================================
+// Clientes Response
+// =============================================
+
+type ClienteResponse struct {
+	ID                 int       `json:"id_cliente"`
+	Nombre             string    `json:"nombre"`
+	TipoDocumento      string    `json:"tipo_documento"`
+	NumeroDocumento    string    `json:"numero_documento"`
+	Telefono           string    `json:"telefono"`
+	Direccion          string    `json:"direccion"`
+	Email              string    `json:"email"`
+	LimiteCredito      *float64  `json:"limite_credito"`
+	DiasCredito        int       `json:"dias_credito"`
+	Activo             bool      `json:"activo"`
+	Saldo              float64   `json:"saldo"`
+	FechaCreacion      time.Time `json:"fecha_creacion"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion"`
+}
+
+type CuentaPorCobrarResponse struct {
+	ID               int       `json:"id_cuenta"`
+	IDCliente        int       `json:"id_cliente"`
+	IDComprobante    int       `json:"id_comprobante"`
+	Comprobante      string    `json:"comprobante"`
+	FechaEmision     time.Time `json:"fecha_emision"`
+	FechaVencimiento time.Time `json:"fecha_vencimiento"`
+	Monto            float64   `json:"monto"`
+	Saldo            float64   `json:"saldo"`
+	Estado           string    `json:"estado"`
+	DiasVencida      int       `json:"dias_vencida"`
+}
+
+type AplicacionPagoResponse struct {
+	IDCuenta int     `json:"id_cuenta"`
+	Monto    float64 `json:"monto"`
+}
+
+type PagoClienteResponse struct {
+	ID              int                      `json:"id_pago"`
+	IDCliente       int                      `json:"id_cliente"`
+	Fecha           time.Time                `json:"fecha"`
+	Monto           float64                  `json:"monto"`
+	TipoPago        string                   `json:"tipo_pago"`
+	Observaciones   string                   `json:"observaciones"`
+	UsuarioRegistro string                   `json:"usuario_registro"`
+	IDControl       int                      `json:"id_control"`
+	Aplicaciones    []AplicacionPagoResponse `json:"aplicaciones"`
+}
+
+type MovimientoCuentaResponse struct {
+	Fecha      time.Time `json:"fecha"`
+	Tipo       string    `json:"tipo"`
+	Referencia string    `json:"referencia"`
+	Cargo      float64   `json:"cargo"`
+	Abono      float64   `json:"abono"`
+	Saldo      float64   `json:"saldo"`
+}
+
+type EstadoCuentaResponse struct {
+	IDCliente     int                        `json:"id_cliente"`
+	NombreCliente string                     `json:"nombre_cliente"`
+	Desde         string                     `json:"desde,omitempty"`
+	Hasta         string                     `json:"hasta,omitempty"`
+	SaldoInicial  float64                    `json:"saldo_inicial"`
+	Movimientos   []MovimientoCuentaResponse `json:"movimientos"`
+	TotalCargos   float64                    `json:"total_cargos"`
+	TotalAbonos   float64                    `json:"total_abonos"`
+	SaldoFinal    float64                    `json:"saldo_final"`
+}
+
+type ReporteAntiguedadItem struct {
+	IDCliente int     `json:"id_cliente"`
+	Cliente   string  `json:"cliente"`
+	PorVencer float64 `json:"por_vencer"`
+	Dias1a30  float64 `json:"dias_1_30"`
+	Dias31a60 float64 `json:"dias_31_60"`
+	Dias61a90 float64 `json:"dias_61_90"`
+	Mas90     float64 `json:"mas_90"`
+	Total     float64 `json:"total"`
+}
+
+// =============================================
+// Devoluciones Response
+// =============================================
+
+type DevolucionResponse struct {
+	ID              int       `json:"id_devolucion"`
+	IDSalida        int       `json:"id_salida"`
+	IDProducto      int       `json:"id_producto"`
+	NombreProducto  string    `json:"nombre_producto,omitempty"`
+	CodigoProducto  string    `json:"codigo_producto,omitempty"`
+	Comprobante     string    `json:"comprobante,omitempty"`
+	Fecha           time.Time `json:"fecha"`
+	Cantidad        int       `json:"cantidad"`
+	Destino         string    `json:"destino"`
+	MetodoReembolso string    `json:"metodo_reembolso"`
+	MontoReembolso  float64   `json:"monto_reembolso"`
+	BaseImponible   float64   `json:"base_imponible"`
+	IGV             float64   `json:"igv"`
+	Motivo          string    `json:"motivo"`
+	UsuarioRegistro string    `json:"usuario_registro"`
+	IDControl       *int      `json:"id_control"`
+	IDCuenta        *int      `json:"id_cuenta"`
+	FechaCreacion   time.Time `json:"fecha_creacion"`
+}
+
+type DevolucionProveedorResponse struct {
+	ID               int        `json:"id_devolucion"`
+	IDEntrada        int        `json:"id_entrada"`
+	IDProducto       int        `json:"id_producto"`
+	NombreProducto   string     `json:"nombre_producto,omitempty"`
+	CodigoProducto   string     `json:"codigo_producto,omitempty"`
+	Proveedor        string     `json:"proveedor"`
+	Fecha            time.Time  `json:"fecha"`
+	Cantidad         int        `json:"cantidad"`
+	TipoCredito      string     `json:"tipo_credito"`
+	MontoEsperado    float64    `json:"monto_esperado"`
+	BaseImponible    float64    `json:"base_imponible"`
+	IGV              float64    `json:"igv"`
+	Motivo           string     `json:"motivo"`
+	UsuarioRegistro  string     `json:"usuario_registro"`
+	Estado           string     `json:"estado"`
+	NumeroNota       string     `json:"numero_nota"`
+	MontoLiquidado   float64    `json:"monto_liquidado"`
+	Diferencia       float64    `json:"diferencia"`
+	FechaLiquidacion *time.Time `json:"fecha_liquidacion"`
+	IDControl        *int       `json:"id_control"`
+	FechaCreacion    time.Time  `json:"fecha_creacion"`
 }
 
 // =============================================
@@ -140,6 +531,9 @@ type ControlDiariosResponse struct {
 	TotalEntrada float64                 `json:"total_entrada"`
 	TotalSalida  float64                 `json:"total_salida"`
 	Balance      float64                 `json:"balance"`
+	Pagina       int                     `json:"pagina,omitempty"`
+	Limite       int                     `json:"limite,omitempty"`
+	TotalPaginas int                     `json:"total_paginas,omitempty"`
 }
 
 // =============================================
@@ -158,6 +552,60 @@ type ResumenMensualResponse struct {
 	Observaciones        string    `json:"observaciones"`
 	FechaGeneracion      time.Time `json:"fecha_generacion"`
 	FechaActualizacion   time.Time `json:"fecha_actualizacion"`
+	Calculado            bool      `json:"calculado"`
+}
+
+// =============================================
+// Resumen Anual y Comparativos Response
+// =============================================
+
+type ResumenAnualResponse struct {
+	Anio                 int                      `json:"anio"`
+	Meses                []ResumenMensualResponse `json:"meses"`
+	TotalIngresos        float64                  `json:"total_ingresos"`
+	TotalGastosFijos     float64                  `json:"total_gastos_fijos"`
+	TotalGastosVariables float64                  `json:"total_gastos_variables"`
+	Balance              float64                  `json:"balance"`
+	MesesConsiderados    int                      `json:"meses_considerados"`
+	PromedioIngresos     float64                  `json:"promedio_ingresos"`
+	PromedioGastos       float64                  `json:"promedio_gastos"`
+	PromedioBalance      float64                  `json:"promedio_balance"`
+	MejorMes             *ResumenMensualResponse  `json:"mejor_mes"`
+	PeorMes              *ResumenMensualResponse  `json:"peor_mes"`
+}
+
+type VariacionResponse struct {
+	Ingresos    float64  `json:"ingresos"`
+	Gastos      float64  `json:"gastos"`
+	Balance     float64  `json:"balance"`
+	IngresosPct *float64 `json:"ingresos_pct"`
+	GastosPct   *float64 `json:"gastos_pct"`
+	BalancePct  *float64 `json:"balance_pct"`
+}
+
+type ComparativoAnioResponse struct {
+	Resumen   ResumenMensualResponse `json:"resumen"`
+	Variacion *VariacionResponse     `json:"variacion"`
+}
+
+type ComparativoMensualResponse struct {
+	Mes       int                       `json:"mes"`
+	NombreMes string                    `json:"nombre_mes"`
+	Anios     []ComparativoAnioResponse `json:"anios"`
+}
+
+type ResumenPeriodoResponse struct {
+	Inicio        string  `json:"inicio"`
+	Fin           string  `json:"fin"`
+	TotalIngresos float64 `json:"total_ingresos"`
+	TotalGastos   float64 `json:"total_gastos"`
+	Balance       float64 `json:"balance"`
+}
+
+type ComparativoPeriodoResponse struct {
+	Actual    ResumenPeriodoResponse `json:"actual"`
+	Anterior  ResumenPeriodoResponse `json:"anterior"`
+	Variacion VariacionResponse      `json:"variacion"`
 }
 
 // =============================================
@@ -180,14 +628,16 @@ type AuthResponse struct {
 // =============================================
 
 type ReporteInventarioItem struct {
-	IDProducto     int     `json:"id_producto"`
-	Codigo         string  `json:"codigo"`
-	Nombre         string  `json:"nombre"`
-	Categoria      string  `json:"categoria"`
-	UnidadMedida   string  `json:"unidad_medida"`
-	PrecioUnitario float64 `json:"precio_unitario"`
-	StockActual    int     `json:"stock_actual"`
-	ValorTotal     float64 `json:"valor_total"`
+	IDProducto     int      `json:"id_producto"`
+	Codigo         string   `json:"codigo"`
+	Nombre         string   `json:"nombre"`
+	Categoria      string   `json:"categoria"`
+	UnidadMedida   string   `json:"unidad_medida"`
+	PrecioUnitario float64  `json:"precio_unitario"`
+	StockActual    int      `json:"stock_actual"`
+	ValorTotal     float64  `json:"valor_total"`
+	UnidadReporte  string   `json:"unidad_reporte,omitempty"`
+	StockReporte   *float64 `json:"stock_unidad_reporte,omitempty"`
 }
 
 type ReporteMovimientoItem struct {
@@ -204,12 +654,14 @@ type ReporteMovimientoItem struct {
 }
 
 type ReporteProductoVendidoItem struct {
-	IDProducto    int     `json:"id_producto"`
-	Codigo        string  `json:"codigo"`
-	Nombre        string  `json:"nombre"`
-	Categoria     string  `json:"categoria"`
-	TotalVendido  int     `json:"total_vendido"`
-	TotalIngresos float64 `json:"total_ingresos"`
+	IDProducto    int      `json:"id_producto"`
+	Codigo        string   `json:"codigo"`
+	Nombre        string   `json:"nombre"`
+	Categoria     string   `json:"categoria"`
+	TotalVendido  int      `json:"total_vendido"`
+	TotalIngresos float64  `json:"total_ingresos"`
+	UnidadReporte string   `json:"unidad_reporte,omitempty"`
+	TotalReporte  *float64 `json:"total_unidad_reporte,omitempty"`
 }
 
 type ReporteValoracionItem struct {
@@ -220,25 +672,130 @@ type ReporteValoracionItem struct {
 	ValorTotal      float64 `json:"valor_total"`
 }
 
+type VelocidadVentaItem struct {
+	DiasVentana    int     `json:"dias_ventana"`
+	TotalVendido   int     `json:"total_vendido"`
+	PromedioDiario float64 `json:"promedio_diario"`
+}
+
+type ReporteCoberturaItem struct {
+	IDProducto       int                  `json:"id_producto"`
+	Codigo           string               `json:"codigo"`
+	Nombre           string               `json:"nombre"`
+	Categoria        string               `json:"categoria"`
+	StockActual      int                  `json:"stock_actual"`
+	PrecioUnitario   float64              `json:"precio_unitario"`
+	Velocidades      []VelocidadVentaItem `json:"velocidades"`
+	PromedioDiario   float64              `json:"promedio_diario"`
+	DiasCobertura    *float64             `json:"dias_cobertura"`
+	FechaQuiebre     *string              `json:"fecha_quiebre"`
+	CantidadSugerida int                  `json:"cantidad_sugerida"`
+}
+
+type ReporteABCItem struct {
+	IDProducto             int     `json:"id_producto"`
+	Codigo                 string  `json:"codigo"`
+	Nombre                 string  `json:"nombre"`
+	Categoria              string  `json:"categoria"`
+	TotalVendido           int     `json:"total_vendido"`
+	TotalIngresos          float64 `json:"total_ingresos"`
+	Participacion          float64 `json:"participacion"`
+	ParticipacionAcumulada float64 `json:"participacion_acumulada"`
+	Clase                  string  `json:"clase"`
+	FrecuenciaConteoDias   int     `json:"frecuencia_conteo_dias"`
+}
+
+type ReporteIGVItem struct {
+	AfectacionIGV string  `json:"afectacion_igv"`
+	Operaciones   int     `json:"operaciones"`
+	BaseImponible float64 `json:"base_imponible"`
+	IGV           float64 `json:"igv"`
+	Total         float64 `json:"total"`
+}
+
+// ReporteIGVResponse muestra el débito (ventas) contra el crédito (entradas) del
+// mes; igv_por_pagar negativo es saldo a favor
+type ReporteIGVResponse struct {
+	Mes         int              `json:"mes"`
+	Anio        int              `json:"anio"`
+	Ventas      []ReporteIGVItem `json:"ventas"`
+	Compras     []ReporteIGVItem `json:"compras"`
+	IGVVentas   float64          `json:"igv_ventas"`
+	IGVCompras  float64          `json:"igv_compras"`
+	IGVPorPagar float64          `json:"igv_por_pagar"`
+}
+
+type ReportePromocionItem struct {
+	IDPromocion    int     `json:"id_promocion"`
+	Nombre         string  `json:"nombre"`
+	Tipo           string  `json:"tipo"`
+	Ventas         int     `json:"ventas"`
+	Unidades       int     `json:"unidades"`
+	Ingresos       float64 `json:"ingresos"`
+	CostoDescuento float64 `json:"costo_descuento"`
+}
+
+type ReporteCompraProveedorItem struct {
+	Proveedor         string  `json:"proveedor"`
+	Entradas          int     `json:"entradas"`
+	Unidades          int     `json:"unidades"`
+	MontoCompras      float64 `json:"monto_compras"`
+	UnidadesDevueltas int     `json:"unidades_devueltas"`
+	MontoDevuelto     float64 `json:"monto_devuelto"`
+	MontoNeto         float64 `json:"monto_neto"`
+	CreditoPendiente  float64 `json:"credito_pendiente"`
+}
+
+type ReporteVentasCategoriaItem struct {
+	IDCategoria *int    `json:"id_categoria"`
+	Categoria   string  `json:"categoria"`
+	Ventas      int     `json:"ventas"`
+	Unidades    int     `json:"unidades"`
+	Ingresos    float64 `json:"ingresos"`
+}
+
+type ReporteStockInmovilizadoItem struct {
+	IDProducto        int     `json:"id_producto"`
+	Codigo            string  `json:"codigo"`
+	Nombre            string  `json:"nombre"`
+	Categoria         string  `json:"categoria"`
+	StockActual       int     `json:"stock_actual"`
+	PrecioUnitario    float64 `json:"precio_unitario"`
+	ValorInmovilizado float64 `json:"valor_inmovilizado"`
+	UltimaVenta       *string `json:"ultima_venta"`
+	DiasSinVenta      *int    `json:"dias_sin_venta"`
+}
+
 // =============================================
 // Alertas Response
 // =============================================
 
 type AlertaStockBajoItem struct {
-	IDProducto     int     `json:"id_producto"`
-	Codigo         string  `json:"codigo"`
-	Nombre         string  `json:"nombre"`
-	Categoria      string  `json:"categoria"`
-	StockActual    int     `json:"stock_actual"`
-	StockInicial   int     `json:"stock_inicial"`
-	PrecioUnitario float64 `json:"precio_unitario"`
+	IDProducto      int     `json:"id_producto"`
+	Codigo          string  `json:"codigo"`
+	Nombre          string  `json:"nombre"`
+	Categoria       string  `json:"categoria"`
+	StockActual     int     `json:"stock_actual"`
+	StockInicial    int     `json:"stock_inicial"`
+	PrecioUnitario  float64 `json:"precio_unitario"`
+	IDProductoPadre *int    `json:"id_producto_padre,omitempty"`
+	Variante        string  `json:"variante,omitempty"`
+}
+
+type AlertasCategoriaItem struct {
+	IDCategoria     *int   `json:"id_categoria"`
+	Categoria       string `json:"categoria"`
+	StockBajo       int    `json:"stock_bajo"`
+	QuiebrePrevisto int    `json:"quiebre_previsto"`
 }
 
 type AlertasResponse struct {
-	Success     bool                  `json:"success"`
-	Message     string                `json:"message"`
-	StockBajo   []AlertaStockBajoItem `json:"stock_bajo"`
-	TotalAlerts int                   `json:"total_alertas"`
+	Success         bool                   `json:"success"`
+	Message         string                 `json:"message"`
+	StockBajo       []AlertaStockBajoItem  `json:"stock_bajo"`
+	QuiebrePrevisto []ReporteCoberturaItem `json:"quiebre_previsto,omitempty"`
+	PorCategoria    []AlertasCategoriaItem `json:"por_categoria,omitempty"`
+	TotalAlerts     int                    `json:"total_alertas"`
 }
 
 // =============================================
@@ -249,6 +806,9 @@ func CategoriaToResponse(categoria *domain.Categoria) CategoriaResponse {
 	return CategoriaResponse{
 		ID:                 categoria.ID,
 		Nombre:             categoria.Nombre,
+		IDCategoriaPadre:   categoria.IDPadre,
+		Activo:             categoria.Activo,
+		FechaBaja:          categoria.FechaBaja,
 		FechaCreacion:      categoria.FechaCreacion,
 		FechaActualizacion: categoria.FechaActualizacion,
 	}
@@ -264,6 +824,15 @@ func ProductoToResponse(producto *domain.Producto) ProductoResponse {
 		PrecioUnitario:     producto.PrecioUnitario,
 		StockActual:        producto.StockActual,
 		StockInicial:       producto.StockInicial,
+		ClaseABC:           producto.ClaseABC,
+		Tipo:               producto.Tipo,
+		IDProductoPadre:    producto.IDProductoPadre,
+		Atributos:          producto.Atributos,
+		AfectacionIGV:      producto.AfectacionIGV,
+		TasaIGV:            producto.TasaIGV,
+		Activo:             producto.Activo,
+		FechaBaja:          producto.FechaBaja,
+		Imagenes:           ArchivosToResponse(producto.Imagenes),
 		FechaCreacion:      producto.FechaCreacion,
 		FechaActualizacion: producto.FechaActualizacion,
 	}
@@ -279,6 +848,11 @@ func EntradaConProductoToResponse(entrada *domain.EntradaConProducto) EntradaPro
 		FechaEntrada:       entrada.FechaEntrada,
 		Cantidad:           entrada.Cantidad,
 		PrecioUnitario:     entrada.PrecioUnitario,
+		AfectacionIGV:      entrada.Afectacion,
+		TasaIGV:            entrada.Tasa,
+		BaseImponible:      entrada.BaseImponible,
+		IGV:                entrada.IGV,
+		Proveedor:          entrada.Proveedor,
 		Observaciones:      entrada.Observaciones,
 		UsuarioRegistro:    entrada.UsuarioRegistro,
 		FechaCreacion:      entrada.FechaCreacion,
@@ -290,6 +864,10 @@ func SalidaConProductoToResponse(salida *domain.SalidaConProducto) SalidaProduct
 	return SalidaProductoResponse{
 		ID:                 salida.ID,
 		IDProducto:         salida.IDProducto,
+		IDComprobante:      salida.IDComprobante,
+		Comprobante:        salida.Comprobante,
+		IDCliente:          salida.IDCliente,
+		NombreCliente:      salida.NombreCliente,
 		CodigoProducto:     salida.CodigoProducto,
 		NombreProducto:     salida.NombreProducto,
 		NombreCategoria:    salida.NombreCategoria,
@@ -297,7 +875,14 @@ func SalidaConProductoToResponse(salida *domain.SalidaConProducto) SalidaProduct
 		Cantidad:           salida.Cantidad,
 		PrecioVenta:        salida.PrecioVenta,
 		Descuento:          salida.Descuento,
+		IDPromocion:        salida.IDPromocion,
+		NombrePromocion:    salida.NombrePromocion,
+		DescuentoPromocion: salida.DescuentoPromocion,
 		Total:              salida.Total,
+		AfectacionIGV:      salida.Afectacion,
+		TasaIGV:            salida.Tasa,
+		BaseImponible:      salida.BaseImponible,
+		IGV:                salida.IGV,
 		LugarVenta:         salida.LugarVenta,
 		TipoPago:           salida.TipoPago,
 		Observaciones:      salida.Observaciones,
@@ -341,6 +926,80 @@ func ResumenMensualToResponse(r *domain.ResumenMensual) ResumenMensualResponse {
 		Observaciones:        r.Observaciones,
 		FechaGeneracion:      r.FechaGeneracion,
 		FechaActualizacion:   r.FechaActualizacion,
+		Calculado:            r.Calculado,
+	}
+}
+
+func ResumenAnualToResponse(r *domain.ResumenAnual) ResumenAnualResponse {
+	meses := make([]ResumenMensualResponse, len(r.Meses))
+	for i, m := range r.Meses {
+		meses[i] = ResumenMensualToResponse(&m)
+	}
+	response := ResumenAnualResponse{
+		Anio:                 r.Anio,
+		Meses:                meses,
+		TotalIngresos:        r.TotalIngresos,
+		TotalGastosFijos:     r.TotalGastosFijos,
+		TotalGastosVariables: r.TotalGastosVariables,
+		Balance:              r.Balance,
+		MesesConsiderados:    r.MesesConsiderados,
+		PromedioIngresos:     r.PromedioIngresos,
+		PromedioGastos:       r.PromedioGastos,
+		PromedioBalance:      r.PromedioBalance,
+	}
+	if r.MejorMes != nil {
+		mejor := ResumenMensualToResponse(r.MejorMes)
+		response.MejorMes = &mejor
+	}
+	if r.PeorMes != nil {
+		peor := ResumenMensualToResponse(r.PeorMes)
+		response.PeorMes = &peor
+	}
+	return response
+}
+
+func VariacionToResponse(v *domain.Variacion) VariacionResponse {
+	return VariacionResponse{
+		Ingresos:    v.Ingresos,
+		Gastos:      v.Gastos,
+		Balance:     v.Balance,
+		IngresosPct: v.IngresosPct,
+		GastosPct:   v.GastosPct,
+		BalancePct:  v.BalancePct,
+	}
+}
+
+func ComparativoMensualToResponse(c *domain.ComparativoMensual) ComparativoMensualResponse {
+	anios := make([]ComparativoAnioResponse, len(c.Anios))
+	for i, a := range c.Anios {
+		anios[i] = ComparativoAnioResponse{Resumen: ResumenMensualToResponse(&a.Resumen)}
+		if a.Variacion != nil {
+			v := VariacionToResponse(a.Variacion)
+			anios[i].Variacion = &v
+		}
+	}
+	return ComparativoMensualResponse{
+		Mes:       c.Mes,
+		NombreMes: nombresMeses[c.Mes],
+		Anios:     anios,
+	}
+}
+
+func ResumenPeriodoToResponse(r *domain.ResumenPeriodo) ResumenPeriodoResponse {
+	return ResumenPeriodoResponse{
+		Inicio:        r.Inicio.Format("2006-01-02"),
+		Fin:           r.Fin.Format("2006-01-02"),
+		TotalIngresos: r.TotalIngresos,
+		TotalGastos:   r.TotalGastos,
+		Balance:       r.Balance,
+	}
+}
+
+func ComparativoPeriodoToResponse(c *domain.ComparativoPeriodo) ComparativoPeriodoResponse {
+	return ComparativoPeriodoResponse{
+		Actual:    ResumenPeriodoToResponse(&c.Actual),
+		Anterior:  ResumenPeriodoToResponse(&c.Anterior),
+		Variacion: VariacionToResponse(&c.Variacion),
 	}
 }
 
@@ -358,7 +1017,17 @@ func ReporteInventarioToResponse(item *domain.ReporteInventarioItem) ReporteInve
 		PrecioUnitario: item.PrecioUnitario,
 		StockActual:    item.StockActual,
 		ValorTotal:     item.ValorTotal,
+		UnidadReporte:  item.UnidadReporte,
+		StockReporte:   cantidadReporte(item.UnidadReporte, item.CantidadReporte),
+	}
+}
+
+// cantidadReporte solo informa la cantidad convertida cuando hubo conversión
+func cantidadReporte(unidad string, cantidad float64) *float64 {
+	if unidad == "" {
+		return nil
 	}
+	return &cantidad
 }
 
 func ReporteMovimientoToResponse(item *domain.ReporteMovimiento) ReporteMovimientoItem {
@@ -384,6 +1053,8 @@ func ReporteProductoVendidoToResponse(item *domain.ReporteProductoVendido) Repor
 		Categoria:     item.Categoria,
 		TotalVendido:  item.TotalVendido,
 		TotalIngresos: item.TotalIngresos,
+		UnidadReporte: item.UnidadReporte,
+		TotalReporte:  cantidadReporte(item.UnidadReporte, item.CantidadReporte),
 	}
 }
 
@@ -397,15 +1068,77 @@ func ReporteValoracionToResponse(item *domain.ReporteValoracion) ReporteValoraci
 	}
 }
 
+func ReporteCoberturaToResponse(item *domain.ReporteCobertura) ReporteCoberturaItem {
+	response := ReporteCoberturaItem{
+		IDProducto:       item.IDProducto,
+		Codigo:           item.Codigo,
+		Nombre:           item.Nombre,
+		Categoria:        item.Categoria,
+		StockActual:      item.StockActual,
+		PrecioUnitario:   item.PrecioUnitario,
+		Velocidades:      make([]VelocidadVentaItem, len(item.Velocidades)),
+		PromedioDiario:   item.PromedioDiario,
+		DiasCobertura:    item.DiasCobertura,
+		CantidadSugerida: item.CantidadSugerida,
+	}
+	for i, v := range item.Velocidades {
+		response.Velocidades[i] = VelocidadVentaItem{
+			DiasVentana:    v.DiasVentana,
+			TotalVendido:   v.TotalVendido,
+			PromedioDiario: v.PromedioDiario,
+		}
+	}
+	if item.FechaQuiebre != nil {
+		fecha := item.FechaQuiebre.Format("2006-01-02")
+		response.FechaQuiebre = &fecha
+	}
+	return response
+}
+
+func ReporteABCToResponse(item *domain.ReporteABC) ReporteABCItem {
+	return ReporteABCItem{
+		IDProducto:             item.IDProducto,
+		Codigo:                 item.Codigo,
+		Nombre:                 item.Nombre,
+		Categoria:              item.Categoria,
+		TotalVendido:           item.TotalVendido,
+		TotalIngresos:          item.TotalIngresos,
+		Participacion:          item.Participacion,
+		ParticipacionAcumulada: item.ParticipacionAcumulada,
+		Clase:                  item.Clase,
+		FrecuenciaConteoDias:   item.FrecuenciaConteoDias,
+	}
+}
+
+func ReporteStockInmovilizadoToResponse(item *domain.ReporteStockInmovilizado) ReporteStockInmovilizadoItem {
+	response := ReporteStockInmovilizadoItem{
+		IDProducto:        item.IDProducto,
+		Codigo:            item.Codigo,
+		Nombre:            item.Nombre,
+		Categoria:         item.Categoria,
+		StockActual:       item.StockActual,
+		PrecioUnitario:    item.PrecioUnitario,
+		ValorInmovilizado: item.ValorInmovilizado,
+		DiasSinVenta:      item.DiasSinVenta,
+	}
+	if item.UltimaVenta != nil {
+		fecha := item.UltimaVenta.Format("2006-01-02")
+		response.UltimaVenta = &fecha
+	}
+	return response
+}
+
 func AlertaStockBajoToResponse(item *domain.AlertaStockBajo) AlertaStockBajoItem {
 	return AlertaStockBajoItem{
-		IDProducto:     item.IDProducto,
-		Codigo:         item.Codigo,
-		Nombre:         item.Nombre,
-		Categoria:      item.Categoria,
-		StockActual:    item.StockActual,
-		StockInicial:   item.StockInicial,
-		PrecioUnitario: item.PrecioUnitario,
+		IDProducto:      item.IDProducto,
+		Codigo:          item.Codigo,
+		Nombre:          item.Nombre,
+		Categoria:       item.Categoria,
+		StockActual:     item.StockActual,
+		StockInicial:    item.StockInicial,
+		PrecioUnitario:  item.PrecioUnitario,
+		IDProductoPadre: item.IDProductoPadre,
+		Variante:        domain.DescribirAtributos(item.Atributos),
 	}
 }
 
@@ -435,10 +1168,88 @@ func ResumenProductoToResponse(r *domain.ResumenProducto) ResumenProductoRespons
 	}
 }
 
+// =============================================
+// Matriz de movimientos mensual Response
+// =============================================
+
+type MovimientoMensualProductoItem struct {
+	IDProducto     int     `json:"id_producto"`
+	Codigo         string  `json:"codigo"`
+	Nombre         string  `json:"nombre"`
+	Categoria      string  `json:"categoria"`
+	UnidadMedida   string  `json:"unidad_medida"`
+	PrecioUnitario float64 `json:"precio_unitario"`
+	StockApertura  int     `json:"stock_apertura"`
+	Entradas       int     `json:"entradas"`
+	MontoEntradas  float64 `json:"monto_entradas"`
+	Salidas        int     `json:"salidas"`
+	MontoSalidas   float64 `json:"monto_salidas"`
+	Ajustes        int     `json:"ajustes"`
+	StockCierre    int     `json:"stock_cierre"`
+	ValorCierre    float64 `json:"valor_cierre"`
+}
+
+type MatrizMovimientosResponse struct {
+	Mes                int                             `json:"mes"`
+	NombreMes          string                          `json:"nombre_mes"`
+	Anio               int                             `json:"anio"`
+	Productos          []MovimientoMensualProductoItem `json:"productos"`
+	TotalMontoEntradas float64                         `json:"total_monto_entradas"`
+	TotalMontoSalidas  float64                         `json:"total_monto_salidas"`
+	TotalValorCierre   float64                         `json:"total_valor_cierre"`
+}
+
+func MovimientoMensualProductoToResponse(item *domain.MovimientoMensualProducto) MovimientoMensualProductoItem {
+	return MovimientoMensualProductoItem{
+		IDProducto:     item.IDProducto,
+		Codigo:         item.Codigo,
+		Nombre:         item.Nombre,
+		Categoria:      item.Categoria,
+		UnidadMedida:   item.UnidadMedida,
+		PrecioUnitario: item.PrecioUnitario,
+		StockApertura:  item.StockApertura,
+		Entradas:       item.Entradas,
+		MontoEntradas:  item.MontoEntradas,
+		Salidas:        item.Salidas,
+		MontoSalidas:   item.MontoSalidas,
+		Ajustes:        item.Ajustes,
+		StockCierre:    item.StockCierre,
+		ValorCierre:    item.ValorCierre,
+	}
+}
+
+func MatrizMovimientosToResponse(mes, anio int, items []domain.MovimientoMensualProducto) MatrizMovimientosResponse {
+	response := MatrizMovimientosResponse{
+		Mes:       mes,
+		NombreMes: nombresMeses[mes],
+		Anio:      anio,
+		Productos: make([]MovimientoMensualProductoItem, len(items)),
+	}
+	for i, item := range items {
+		response.Productos[i] = MovimientoMensualProductoToResponse(&item)
+		response.TotalMontoEntradas += item.MontoEntradas
+		response.TotalMontoSalidas += item.MontoSalidas
+		response.TotalValorCierre += item.ValorCierre
+	}
+	return response
+}
+
 // =============================================
 // Helper functions: listas
 // =============================================
 
+func ArbolCategoriasToResponse(nodos []*domain.NodoCategoria) []CategoriaNodoResponse {
+	result := make([]CategoriaNodoResponse, len(nodos))
+	for i, n := range nodos {
+		result[i] = CategoriaNodoResponse{
+			CategoriaResponse: CategoriaToResponse(&n.Categoria),
+			Nivel:             n.Nivel,
+			Subcategorias:     ArbolCategoriasToResponse(n.Subcategorias),
+		}
+	}
+	return result
+}
+
 func CategoriasToResponse(categorias []domain.Categoria) []CategoriaResponse {
 	responses := make([]CategoriaResponse, len(categorias))
 	for i, c := range categorias {
@@ -455,6 +1266,86 @@ func ProductosToResponse(productos []domain.Producto) []ProductoResponse {
 	return responses
 }
 
+func ProductoEscaneoToResponse(p *domain.Producto) ProductoEscaneoResponse {
+	r := ProductoEscaneoResponse{
+		IDProducto:     p.ID,
+		Codigo:         p.Codigo,
+		Nombre:         p.Nombre,
+		UnidadMedida:   p.UnidadMedida,
+		PrecioUnitario: p.PrecioUnitario,
+		StockActual:    p.StockActual,
+	}
+	if len(p.Imagenes) > 0 {
+		r.MiniaturaURL = ArchivoToResponse(&p.Imagenes[0]).MiniaturaURL
+	}
+	return r
+}
+
+func CodigoBarrasToResponse(cb *domain.CodigoBarras) CodigoBarrasResponse {
+	return CodigoBarrasResponse{
+		ID:            cb.ID,
+		IDProducto:    cb.IDProducto,
+		Codigo:        cb.Codigo,
+		Tipo:          cb.Tipo,
+		FechaCreacion: cb.FechaCreacion,
+	}
+}
+
+func ImportacionToResponse(r *domain.ResultadoImportacion) ImportacionResponse {
+	filas := make([]ImportacionFilaResponse, len(r.Filas))
+	for i, f := range r.Filas {
+		filas[i] = ImportacionFilaResponse{Fila: f.Numero, Codigo: f.Codigo, Accion: f.Accion, IDProducto: f.IDProducto, Errores: f.Errores}
+	}
+	categorias := r.CategoriasNuevas
+	if categorias == nil {
+		categorias = []string{}
+	}
+	return ImportacionResponse{
+		Simulacion:       r.Simulacion,
+		Aplicada:         r.Aplicada,
+		Creados:          r.Creados,
+		Actualizados:     r.Actualizados,
+		ConErrores:       r.ConErrores,
+		CategoriasNuevas: categorias,
+		Filas:            filas,
+	}
+}
+
+func ArchivoToResponse(a *domain.Archivo) ArchivoResponse {
+	r := ArchivoResponse{
+		ID:              a.ID,
+		Tipo:            a.Tipo,
+		NombreOriginal:  a.NombreOriginal,
+		ContentType:     a.ContentType,
+		Tamano:          a.Tamano,
+		Orden:           a.Orden,
+		URL:             fmt.Sprintf("/api/archivos/%d", a.ID),
+		UsuarioRegistro: a.UsuarioRegistro,
+		FechaCreacion:   a.FechaCreacion,
+	}
+	if a.RutaMiniatura != "" {
+		r.MiniaturaURL = r.URL + "/miniatura"
+	}
+	return r
+}
+
+// ArchivosToResponse retorna una lista vacía (no null) cuando no hay archivos
+func ArchivosToResponse(archivos []domain.Archivo) []ArchivoResponse {
+	responses := make([]ArchivoResponse, len(archivos))
+	for i, a := range archivos {
+		responses[i] = ArchivoToResponse(&a)
+	}
+	return responses
+}
+
+func CodigosBarrasToResponse(codigos []domain.CodigoBarras) []CodigoBarrasResponse {
+	responses := make([]CodigoBarrasResponse, len(codigos))
+	for i, cb := range codigos {
+		responses[i] = CodigoBarrasToResponse(&cb)
+	}
+	return responses
+}
+
 func EntradasConProductoToResponse(entradas []domain.EntradaConProducto) []EntradaProductoResponse {
 	responses := make([]EntradaProductoResponse, len(entradas))
 	for i, e := range entradas {
@@ -518,3 +1409,468 @@ func AlertasStockBajoToResponse(items []domain.AlertaStockBajo) []AlertaStockBaj
 	}
 	return responses
 }
+
+func ReportesCoberturaToResponse(items []domain.ReporteCobertura) []ReporteCoberturaItem {
+	responses := make([]ReporteCoberturaItem, len(items))
+	for i, item := range items {
+		responses[i] = ReporteCoberturaToResponse(&item)
+	}
+	return responses
+}
+
+func ReportesABCToResponse(items []domain.ReporteABC) []ReporteABCItem {
+	responses := make([]ReporteABCItem, len(items))
+	for i, item := range items {
+		responses[i] = ReporteABCToResponse(&item)
+	}
+	return responses
+}
+
+func ReportesStockInmovilizadoToResponse(items []domain.ReporteStockInmovilizado) []ReporteStockInmovilizadoItem {
+	responses := make([]ReporteStockInmovilizadoItem, len(items))
+	for i, item := range items {
+		responses[i] = ReporteStockInmovilizadoToResponse(&item)
+	}
+	return responses
+}
+
+func ComponentesKitToResponse(componentes []domain.ComponenteKit) []ComponenteKitResponse {
+	responses := make([]ComponenteKitResponse, len(componentes))
+	for i, c := range componentes {
+		responses[i] = ComponenteKitResponse{
+			IDProducto:  c.IDComponente,
+			Codigo:      c.Codigo,
+			Nombre:      c.Nombre,
+			Cantidad:    c.Cantidad,
+			StockActual: c.StockActual,
+			AlcanzaPara: domain.DisponibilidadKit([]domain.ComponenteKit{c}),
+		}
+	}
+	return responses
+}
+
+func UnidadesMedidaToResponse(unidades []domain.UnidadMedida) []UnidadMedidaResponse {
+	responses := make([]UnidadMedidaResponse, len(unidades))
+	for i, u := range unidades {
+		responses[i] = UnidadMedidaResponse{ID: u.ID, Codigo: u.Codigo, Nombre: u.Nombre, FechaCreacion: u.FechaCreacion}
+	}
+	return responses
+}
+
+func ConversionesUnidadToResponse(conversiones []domain.ConversionUnidad) []ConversionUnidadResponse {
+	responses := make([]ConversionUnidadResponse, len(conversiones))
+	for i, c := range conversiones {
+		responses[i] = ConversionUnidadResponse{Unidad: c.Unidad, Factor: c.Factor}
+	}
+	return responses
+}
+
+func CambiosPrecioToResponse(historial []domain.CambioPrecio) []CambioPrecioResponse {
+	responses := make([]CambioPrecioResponse, len(historial))
+	for i, c := range historial {
+		responses[i] = CambioPrecioResponse{
+			ID:             c.ID,
+			PrecioAnterior: c.PrecioAnterior,
+			PrecioNuevo:    c.PrecioNuevo,
+			Usuario:        c.Usuario,
+			Motivo:         c.Motivo,
+			IDProgramado:   c.IDProgramado,
+			FechaCambio:    c.FechaCambio,
+		}
+	}
+	return responses
+}
+
+func ActualizacionMasivaToResponse(cambios []domain.CambioMasivoPrecio, simulacion bool) ActualizacionMasivaResponse {
+	responses := make([]CambioMasivoPrecioResponse, len(cambios))
+	for i, c := range cambios {
+		responses[i] = CambioMasivoPrecioResponse{
+			IDProducto:     c.IDProducto,
+			Codigo:         c.Codigo,
+			Nombre:         c.Nombre,
+			PrecioAnterior: c.PrecioAnterior,
+			PrecioNuevo:    c.PrecioNuevo,
+		}
+	}
+	return ActualizacionMasivaResponse{Simulacion: simulacion, Total: len(cambios), Cambios: responses}
+}
+
+func PrecioProgramadoToResponse(p *domain.PrecioProgramado) PrecioProgramadoResponse {
+	return PrecioProgramadoResponse{
+		ID:              p.ID,
+		IDProducto:      p.IDProducto,
+		PrecioNuevo:     p.PrecioNuevo,
+		FechaVigencia:   p.FechaVigencia.Format("2006-01-02"),
+		Usuario:         p.Usuario,
+		Estado:          p.Estado,
+		FechaAplicacion: p.FechaAplicacion,
+		FechaCreacion:   p.FechaCreacion,
+	}
+}
+
+func PreciosProgramadosToResponse(programados []domain.PrecioProgramado) []PrecioProgramadoResponse {
+	responses := make([]PrecioProgramadoResponse, len(programados))
+	for i := range programados {
+		responses[i] = PrecioProgramadoToResponse(&programados[i])
+	}
+	return responses
+}
+
+func PromocionToResponse(p *domain.Promocion) PromocionResponse {
+	response := PromocionResponse{
+		ID:            p.ID,
+		Nombre:        p.Nombre,
+		Tipo:          p.Tipo,
+		IDProducto:    p.IDProducto,
+		IDCategoria:   p.IDCategoria,
+		Valor:         p.Valor,
+		CantidadLleva: p.CantidadLleva,
+		CantidadPaga:  p.CantidadPaga,
+		HoraInicio:    p.HoraInicio,
+		HoraFin:       p.HoraFin,
+		DiasSemana:    p.DiasSemana,
+		LugarVenta:    p.LugarVenta,
+		Activa:        p.Activa,
+		FechaCreacion: p.FechaCreacion,
+	}
+	if response.DiasSemana == nil {
+		response.DiasSemana = []int{}
+	}
+	if p.FechaInicio != nil {
+		fecha := p.FechaInicio.Format("2006-01-02")
+		response.FechaInicio = &fecha
+	}
+	if p.FechaFin != nil {
+		fecha := p.FechaFin.Format("2006-01-02")
+		response.FechaFin = &fecha
+	}
+	return response
+}
+
+func PromocionesToResponse(promociones []domain.Promocion) []PromocionResponse {
+	responses := make([]PromocionResponse, len(promociones))
+	for i := range promociones {
+		responses[i] = PromocionToResponse(&promociones[i])
+	}
+	return responses
+}
+
+func ReportesPromocionToResponse(items []domain.ReportePromocion) []ReportePromocionItem {
+	responses := make([]ReportePromocionItem, len(items))
+	for i, item := range items {
+		responses[i] = ReportePromocionItem{
+			IDPromocion:    item.IDPromocion,
+			Nombre:         item.Nombre,
+			Tipo:           item.Tipo,
+			Ventas:         item.Ventas,
+			Unidades:       item.Unidades,
+			Ingresos:       item.Ingresos,
+			CostoDescuento: item.CostoDescuento,
+		}
+	}
+	return responses
+}
+
+func reporteIGVItems(items []domain.ReporteIGVItem) []ReporteIGVItem {
+	responses := make([]ReporteIGVItem, len(items))
+	for i, item := range items {
+		responses[i] = ReporteIGVItem{
+			AfectacionIGV: item.Afectacion,
+			Operaciones:   item.Operaciones,
+			BaseImponible: item.BaseImponible,
+			IGV:           item.IGV,
+			Total:         item.Total,
+		}
+	}
+	return responses
+}
+
+func ReporteIGVToResponse(r *domain.ReporteIGV) ReporteIGVResponse {
+	return ReporteIGVResponse{
+		Mes:         r.Mes,
+		Anio:        r.Anio,
+		Ventas:      reporteIGVItems(r.Ventas),
+		Compras:     reporteIGVItems(r.Compras),
+		IGVVentas:   r.IGVVentas,
+		IGVCompras:  r.IGVCompras,
+		IGVPorPagar: r.IGVPorPagar,
+	}
+}
+
+func ComprobanteToResponse(d *domain.ComprobanteDetalle) ComprobanteResponse {
+	c := &d.Comprobante
+	response := ComprobanteResponse{
+		ID:              c.ID,
+		Tipo:            c.Tipo,
+		Serie:           c.Serie,
+		Numero:          c.Numero,
+		Numeracion:      c.Numeracion(),
+		IDCliente:       c.IDCliente,
+		FechaEmision:    c.FechaEmision,
+		LugarVenta:      c.LugarVenta,
+		TipoPago:        c.TipoPago,
+		UsuarioRegistro: c.UsuarioRegistro,
+		Subtotal:        c.Subtotal,
+		Descuento:       c.Descuento,
+		OpGravada:       c.OpGravada,
+		OpExonerada:     c.OpExonerada,
+		OpInafecta:      c.OpInafecta,
+		IGV:             c.IGV,
+		Total:           c.Total,
+		Lineas:          SalidasConProductoToResponse(d.Lineas),
+	}
+	if d.Cliente != nil {
+		response.NombreCliente = d.Cliente.Nombre
+	}
+	if d.Cuenta != nil {
+		cuenta := CuentaPorCobrarToResponse(d.Cuenta, time.Now())
+		response.Cuenta = &cuenta
+	}
+	return response
+}
+
+func SerieComprobanteToResponse(s *domain.SerieComprobante) SerieComprobanteResponse {
+	return SerieComprobanteResponse{
+		ID:            s.ID,
+		Tipo:          s.Tipo,
+		Serie:         s.Serie,
+		LugarVenta:    s.LugarVenta,
+		Correlativo:   s.Correlativo,
+		Activa:        s.Activa,
+		FechaCreacion: s.FechaCreacion,
+	}
+}
+
+func SeriesComprobanteToResponse(series []domain.SerieComprobante) []SerieComprobanteResponse {
+	responses := make([]SerieComprobanteResponse, len(series))
+	for i := range series {
+		responses[i] = SerieComprobanteToResponse(&series[i])
+	}
+	return responses
+}
+
+func ClienteToResponse(c *domain.ClienteConSaldo) ClienteResponse {
+	return ClienteResponse{
+		ID:                 c.ID,
+		Nombre:             c.Nombre,
+		TipoDocumento:      c.TipoDocumento,
+		NumeroDocumento:    c.NumeroDocumento,
+		Telefono:           c.Telefono,
+		Direccion:          c.Direccion,
+		Email:              c.Email,
+		LimiteCredito:      c.LimiteCredito,
+		DiasCredito:        c.DiasCredito,
+		Activo:             c.Activo,
+		Saldo:              c.Saldo,
+		FechaCreacion:      c.FechaCreacion,
+		FechaActualizacion: c.FechaActualizacion,
+	}
+}
+
+func ClientesToResponse(clientes []domain.ClienteConSaldo) []ClienteResponse {
+	responses := make([]ClienteResponse, len(clientes))
+	for i := range clientes {
+		responses[i] = ClienteToResponse(&clientes[i])
+	}
+	return responses
+}
+
+// CuentaPorCobrarToResponse calcula los días de atraso a la fecha dada
+func CuentaPorCobrarToResponse(c *domain.CuentaPorCobrar, fecha time.Time) CuentaPorCobrarResponse {
+	response := CuentaPorCobrarResponse{
+		ID:               c.ID,
+		IDCliente:        c.IDCliente,
+		IDComprobante:    c.IDComprobante,
+		Comprobante:      c.Comprobante,
+		FechaEmision:     c.FechaEmision,
+		FechaVencimiento: c.FechaVencimiento,
+		Monto:            c.Monto,
+		Saldo:            c.Saldo,
+		Estado:           c.Estado,
+	}
+	if c.Estado == domain.EstadoCuentaPendiente {
+		response.DiasVencida = c.DiasVencida(fecha)
+	}
+	return response
+}
+
+func CuentasPorCobrarToResponse(cuentas []domain.CuentaPorCobrar) []CuentaPorCobrarResponse {
+	hoy := time.Now()
+	responses := make([]CuentaPorCobrarResponse, len(cuentas))
+	for i := range cuentas {
+		responses[i] = CuentaPorCobrarToResponse(&cuentas[i], hoy)
+	}
+	return responses
+}
+
+func PagoClienteToResponse(p *domain.PagoCliente) PagoClienteResponse {
+	aplicaciones := make([]AplicacionPagoResponse, len(p.Aplicaciones))
+	for i, a := range p.Aplicaciones {
+		aplicaciones[i] = AplicacionPagoResponse{IDCuenta: a.IDCuenta, Monto: a.Monto}
+	}
+	return PagoClienteResponse{
+		ID:              p.ID,
+		IDCliente:       p.IDCliente,
+		Fecha:           p.Fecha,
+		Monto:           p.Monto,
+		TipoPago:        p.TipoPago,
+		Observaciones:   p.Observaciones,
+		UsuarioRegistro: p.UsuarioRegistro,
+		IDControl:       p.IDControl,
+		Aplicaciones:    aplicaciones,
+	}
+}
+
+func EstadoCuentaToResponse(e *domain.EstadoCuenta) EstadoCuentaResponse {
+	movimientos := make([]MovimientoCuentaResponse, len(e.Movimientos))
+	for i, m := range e.Movimientos {
+		movimientos[i] = MovimientoCuentaResponse{Fecha: m.Fecha, Tipo: m.Tipo, Referencia: m.Referencia, Cargo: m.Cargo, Abono: m.Abono, Saldo: m.Saldo}
+	}
+	return EstadoCuentaResponse{
+		IDCliente:     e.Cliente.ID,
+		NombreCliente: e.Cliente.Nombre,
+		Desde:         e.Desde,
+		Hasta:         e.Hasta,
+		SaldoInicial:  e.SaldoInicial,
+		Movimientos:   movimientos,
+		TotalCargos:   e.TotalCargos,
+		TotalAbonos:   e.TotalAbonos,
+		SaldoFinal:    e.SaldoFinal,
+	}
+}
+
+func ReporteAntiguedadToResponse(items []domain.ReporteAntiguedad) []ReporteAntiguedadItem {
+	responses := make([]ReporteAntiguedadItem, len(items))
+	for i, r := range items {
+		responses[i] = ReporteAntiguedadItem{
+			IDCliente: r.IDCliente,
+			Cliente:   r.Cliente,
+			PorVencer: r.PorVencer,
+			Dias1a30:  r.Dias1a30,
+			Dias31a60: r.Dias31a60,
+			Dias61a90: r.Dias61a90,
+			Mas90:     r.Mas90,
+			Total:     r.Total,
+		}
+	}
+	return responses
+}
+
+func DevolucionToResponse(d *domain.DevolucionCliente) DevolucionResponse {
+	return DevolucionResponse{
+		ID:              d.ID,
+		IDSalida:        d.IDSalida,
+		IDProducto:      d.IDProducto,
+		Fecha:           d.Fecha,
+		Cantidad:        d.Cantidad,
+		Destino:         d.Destino,
+		MetodoReembolso: d.MetodoReembolso,
+		MontoReembolso:  d.MontoReembolso,
+		BaseImponible:   d.BaseImponible,
+		IGV:             d.IGV,
+		Motivo:          d.Motivo,
+		UsuarioRegistro: d.UsuarioRegistro,
+		IDControl:       d.IDControl,
+		IDCuenta:        d.IDCuenta,
+		FechaCreacion:   d.FechaCreacion,
+	}
+}
+
+func DevolucionConProductoToResponse(d *domain.DevolucionConProducto) DevolucionResponse {
+	response := DevolucionToResponse(&d.DevolucionCliente)
+	response.NombreProducto = d.NombreProducto
+	response.CodigoProducto = d.CodigoProducto
+	response.Comprobante = d.Comprobante
+	return response
+}
+
+func DevolucionesToResponse(devoluciones []domain.DevolucionConProducto) []DevolucionResponse {
+	responses := make([]DevolucionResponse, len(devoluciones))
+	for i := range devoluciones {
+		responses[i] = DevolucionConProductoToResponse(&devoluciones[i])
+	}
+	return responses
+}
+
+func ReportesCompraProveedorToResponse(items []domain.ReporteCompraProveedor) []ReporteCompraProveedorItem {
+	responses := make([]ReporteCompraProveedorItem, len(items))
+	for i, r := range items {
+		responses[i] = ReporteCompraProveedorItem{
+			Proveedor:         r.Proveedor,
+			Entradas:          r.Entradas,
+			Unidades:          r.Unidades,
+			MontoCompras:      r.MontoCompras,
+			UnidadesDevueltas: r.UnidadesDevueltas,
+			MontoDevuelto:     r.MontoDevuelto,
+			MontoNeto:         r.MontoNeto,
+			CreditoPendiente:  r.CreditoPendiente,
+		}
+	}
+	return responses
+}
+
+func DevolucionProveedorToResponse(d *domain.DevolucionProveedor) DevolucionProveedorResponse {
+	return DevolucionProveedorResponse{
+		ID:               d.ID,
+		IDEntrada:        d.IDEntrada,
+		IDProducto:       d.IDProducto,
+		Proveedor:        d.Proveedor,
+		Fecha:            d.Fecha,
+		Cantidad:         d.Cantidad,
+		TipoCredito:      d.TipoCredito,
+		MontoEsperado:    d.MontoEsperado,
+		BaseImponible:    d.BaseImponible,
+		IGV:              d.IGV,
+		Motivo:           d.Motivo,
+		UsuarioRegistro:  d.UsuarioRegistro,
+		Estado:           d.Estado,
+		NumeroNota:       d.NumeroNota,
+		MontoLiquidado:   d.MontoLiquidado,
+		Diferencia:       d.Diferencia(),
+		FechaLiquidacion: d.FechaLiquidacion,
+		IDControl:        d.IDControl,
+		FechaCreacion:    d.FechaCreacion,
+	}
+}
+
+func DevolucionProveedorConProductoToResponse(d *domain.DevolucionProveedorConProducto) DevolucionProveedorResponse {
+	response := DevolucionProveedorToResponse(&d.DevolucionProveedor)
+	response.NombreProducto = d.NombreProducto
+	response.CodigoProducto = d.CodigoProducto
+	return response
+}
+
+func DevolucionesProveedorToResponse(devoluciones []domain.DevolucionProveedorConProducto) []DevolucionProveedorResponse {
+	responses := make([]DevolucionProveedorResponse, len(devoluciones))
+	for i := range devoluciones {
+		responses[i] = DevolucionProveedorConProductoToResponse(&devoluciones[i])
+	}
+	return responses
+}
+
+func ReportesVentasCategoriaToResponse(items []domain.ReporteVentasCategoria) []ReporteVentasCategoriaItem {
+	responses := make([]ReporteVentasCategoriaItem, len(items))
+	for i, r := range items {
+		responses[i] = ReporteVentasCategoriaItem{
+			IDCategoria: r.IDCategoria,
+			Categoria:   r.Categoria,
+			Ventas:      r.Ventas,
+			Unidades:    r.Unidades,
+			Ingresos:    r.Ingresos,
+		}
+	}
+	return responses
+}
+
+func AlertasCategoriaToResponse(items []domain.AlertasCategoria) []AlertasCategoriaItem {
+	responses := make([]AlertasCategoriaItem, len(items))
+	for i, a := range items {
+		responses[i] = AlertasCategoriaItem{
+			IDCategoria:     a.IDCategoria,
+			Categoria:       a.Categoria,
+			StockBajo:       a.StockBajo,
+			QuiebrePrevisto: a.QuiebrePrevisto,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,574 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+)
+
+// =============================================
+// Tablas exportables (CSV / XLSX)
+// =============================================
+
+func CategoriasTabla(categorias []domain.Categoria) export.Tabla {
+	t := export.Tabla{
+		Nombre: "categorias",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "ID Categoría padre", Tipo: export.Entero},
+			{Titulo: "Activo", Tipo: export.Texto},
+			{Titulo: "Fecha de creación", Tipo: export.Fecha},
+		},
+	}
+	for _, c := range categorias {
+		t.Filas = append(t.Filas, []any{c.ID, c.Nombre, c.IDPadre, c.Activo, c.FechaCreacion})
+	}
+	return t
+}
+
+func ProductosTabla(productos []domain.Producto) export.Tabla {
+	t := export.Tabla{
+		Nombre: "productos",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "ID Categoría", Tipo: export.Entero},
+			{Titulo: "Unidad de medida", Tipo: export.Texto},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Stock actual", Tipo: export.Entero},
+			{Titulo: "Stock inicial", Tipo: export.Entero},
+			{Titulo: "Clase ABC", Tipo: export.Texto},
+			{Titulo: "Tipo", Tipo: export.Texto},
+			{Titulo: "ID Producto padre", Tipo: export.Entero},
+			{Titulo: "Variante", Tipo: export.Texto},
+			{Titulo: "Afectación IGV", Tipo: export.Texto},
+			{Titulo: "Tasa IGV", Tipo: export.Porcentaje},
+			{Titulo: "Activo", Tipo: export.Texto},
+		},
+	}
+	for _, p := range productos {
+		t.Filas = append(t.Filas, []any{p.ID, p.Codigo, p.Nombre, p.IDCategoria, p.UnidadMedida, p.PrecioUnitario, p.StockActual, p.StockInicial, p.ClaseABC, p.Tipo, p.IDProductoPadre, domain.DescribirAtributos(p.Atributos), p.AfectacionIGV, p.TasaIGV, p.Activo})
+	}
+	return t
+}
+
+func EntradasTabla(entradas []domain.EntradaConProducto) export.Tabla {
+	t := export.Tabla{
+		Nombre: "entradas",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Fecha", Tipo: export.Fecha},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Producto", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Cantidad", Tipo: export.Entero},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Afectación IGV", Tipo: export.Texto},
+			{Titulo: "Base imponible", Tipo: export.Moneda},
+			{Titulo: "IGV", Tipo: export.Moneda},
+			{Titulo: "Proveedor", Tipo: export.Texto},
+			{Titulo: "Observaciones", Tipo: export.Texto},
+			{Titulo: "Usuario", Tipo: export.Texto},
+		},
+	}
+	for _, e := range entradas {
+		t.Filas = append(t.Filas, []any{e.ID, e.FechaEntrada, e.CodigoProducto, e.NombreProducto, e.NombreCategoria, e.Cantidad, e.PrecioUnitario, e.Afectacion, e.BaseImponible, e.IGV, e.Proveedor, e.Observaciones, e.UsuarioRegistro})
+	}
+	return t
+}
+
+func SalidasTabla(salidas []domain.SalidaConProducto) export.Tabla {
+	t := export.Tabla{
+		Nombre: "salidas",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Fecha", Tipo: export.Fecha},
+			{Titulo: "Comprobante", Tipo: export.Texto},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Producto", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Cantidad", Tipo: export.Entero},
+			{Titulo: "Precio de venta", Tipo: export.Moneda},
+			{Titulo: "Descuento", Tipo: export.Moneda},
+			{Titulo: "Promoción", Tipo: export.Texto},
+			{Titulo: "Descuento promoción", Tipo: export.Moneda},
+			{Titulo: "Total", Tipo: export.Moneda},
+			{Titulo: "Afectación IGV", Tipo: export.Texto},
+			{Titulo: "Base imponible", Tipo: export.Moneda},
+			{Titulo: "IGV", Tipo: export.Moneda},
+			{Titulo: "Lugar de venta", Tipo: export.Texto},
+			{Titulo: "Tipo de pago", Tipo: export.Texto},
+			{Titulo: "Cliente", Tipo: export.Texto},
+			{Titulo: "Observaciones", Tipo: export.Texto},
+			{Titulo: "Usuario", Tipo: export.Texto},
+		},
+	}
+	for _, s := range salidas {
+		t.Filas = append(t.Filas, []any{s.ID, s.FechaSalida, s.Comprobante, s.CodigoProducto, s.NombreProducto, s.NombreCategoria, s.Cantidad, s.PrecioVenta, s.Descuento, s.NombrePromocion, s.DescuentoPromocion, s.Total, s.Afectacion, s.BaseImponible, s.IGV, s.LugarVenta, s.TipoPago, s.NombreCliente, s.Observaciones, s.UsuarioRegistro})
+	}
+	return t
+}
+
+func ControlDiariosTabla(controles []domain.ControlDiario) export.Tabla {
+	t := export.Tabla{
+		Nombre: "control_diario",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Fecha", Tipo: export.Fecha},
+			{Titulo: "Descripción", Tipo: export.Texto},
+			{Titulo: "Monto entrada", Tipo: export.Moneda},
+			{Titulo: "Monto salida", Tipo: export.Moneda},
+			{Titulo: "Observaciones", Tipo: export.Texto},
+			{Titulo: "Verbena", Tipo: export.Texto},
+			{Titulo: "Usuario", Tipo: export.Texto},
+		},
+	}
+	for _, c := range controles {
+		t.Filas = append(t.Filas, []any{c.ID, c.Fecha, c.Descripcion, c.MontoEntrada, c.MontoSalida, c.Observaciones, c.EsVerbena, c.UsuarioRegistro})
+	}
+	return t
+}
+
+func ResumenesMensualesTabla(nombre string, resumenes []domain.ResumenMensual) export.Tabla {
+	t := export.Tabla{
+		Nombre: nombre,
+		Columnas: []export.Columna{
+			{Titulo: "Mes", Tipo: export.Entero},
+			{Titulo: "Nombre del mes", Tipo: export.Texto},
+			{Titulo: "Año", Tipo: export.Entero},
+			{Titulo: "Total ingresos", Tipo: export.Moneda},
+			{Titulo: "Gastos fijos", Tipo: export.Moneda},
+			{Titulo: "Gastos variables", Tipo: export.Moneda},
+			{Titulo: "Balance", Tipo: export.Moneda},
+			{Titulo: "Observaciones", Tipo: export.Texto},
+			{Titulo: "Calculado", Tipo: export.Texto},
+		},
+	}
+	for _, r := range resumenes {
+		t.Filas = append(t.Filas, []any{r.Mes, nombresMeses[r.Mes], r.Anio, r.TotalIngresos, r.TotalGastosFijos, r.TotalGastosVariables, r.Balance, r.Observaciones, r.Calculado})
+	}
+	return t
+}
+
+func ResumenProductoTabla(r *domain.ResumenProducto) export.Tabla {
+	return export.Tabla{
+		Nombre: fmt.Sprintf("resumen_producto_%d", r.IDProducto),
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Mes", Tipo: export.Entero},
+			{Titulo: "Año", Tipo: export.Entero},
+			{Titulo: "Unidades ingresadas", Tipo: export.Entero},
+			{Titulo: "Monto entradas", Tipo: export.Moneda},
+			{Titulo: "Unidades vendidas", Tipo: export.Entero},
+			{Titulo: "Monto salidas", Tipo: export.Moneda},
+		},
+		Filas: [][]any{{r.IDProducto, r.Mes, r.Anio, r.TotalEntradas, r.MontoEntradas, r.TotalSalidas, r.MontoSalidas}},
+	}
+}
+
+func MatrizMovimientosTabla(mes, anio int, items []domain.MovimientoMensualProducto) export.Tabla {
+	t := export.Tabla{
+		Nombre: fmt.Sprintf("movimientos_%04d_%02d", anio, mes),
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Unidad", Tipo: export.Texto},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Stock apertura", Tipo: export.Entero},
+			{Titulo: "Entradas", Tipo: export.Entero},
+			{Titulo: "Monto entradas", Tipo: export.Moneda},
+			{Titulo: "Salidas", Tipo: export.Entero},
+			{Titulo: "Monto salidas", Tipo: export.Moneda},
+			{Titulo: "Ajustes", Tipo: export.Entero},
+			{Titulo: "Stock cierre", Tipo: export.Entero},
+			{Titulo: "Valor cierre", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.UnidadMedida, i.PrecioUnitario, i.StockApertura, i.Entradas, i.MontoEntradas, i.Salidas, i.MontoSalidas, i.Ajustes, i.StockCierre, i.ValorCierre})
+	}
+	return t
+}
+
+func ComparativoMensualTabla(c *domain.ComparativoMensual) export.Tabla {
+	t := export.Tabla{
+		Nombre: fmt.Sprintf("comparativo_%s", nombresMeses[c.Mes]),
+		Columnas: []export.Columna{
+			{Titulo: "Año", Tipo: export.Entero},
+			{Titulo: "Total ingresos", Tipo: export.Moneda},
+			{Titulo: "Total gastos", Tipo: export.Moneda},
+			{Titulo: "Balance", Tipo: export.Moneda},
+			{Titulo: "Variación ingresos", Tipo: export.Moneda},
+			{Titulo: "Variación ingresos %", Tipo: export.Porcentaje},
+			{Titulo: "Variación balance", Tipo: export.Moneda},
+			{Titulo: "Variación balance %", Tipo: export.Porcentaje},
+		},
+	}
+	for _, a := range c.Anios {
+		fila := []any{a.Resumen.Anio, a.Resumen.TotalIngresos, a.Resumen.TotalGastos(), a.Resumen.Balance, nil, nil, nil, nil}
+		if v := a.Variacion; v != nil {
+			fila[4], fila[5], fila[6], fila[7] = v.Ingresos, v.IngresosPct, v.Balance, v.BalancePct
+		}
+		t.Filas = append(t.Filas, fila)
+	}
+	return t
+}
+
+func ComparativoPeriodoTabla(c *domain.ComparativoPeriodo) export.Tabla {
+	t := export.Tabla{
+		Nombre: "comparativo_periodos",
+		Columnas: []export.Columna{
+			{Titulo: "Periodo", Tipo: export.Texto},
+			{Titulo: "Inicio", Tipo: export.Fecha},
+			{Titulo: "Fin", Tipo: export.Fecha},
+			{Titulo: "Total ingresos", Tipo: export.Moneda},
+			{Titulo: "Total gastos", Tipo: export.Moneda},
+			{Titulo: "Balance", Tipo: export.Moneda},
+		},
+	}
+	t.Filas = append(t.Filas,
+		[]any{"Actual", c.Actual.Inicio, c.Actual.Fin, c.Actual.TotalIngresos, c.Actual.TotalGastos, c.Actual.Balance},
+		[]any{"Anterior", c.Anterior.Inicio, c.Anterior.Fin, c.Anterior.TotalIngresos, c.Anterior.TotalGastos, c.Anterior.Balance},
+		[]any{"Variación", nil, nil, c.Variacion.Ingresos, c.Variacion.Gastos, c.Variacion.Balance},
+	)
+	return t
+}
+
+func InventarioTabla(items []domain.ReporteInventarioItem) export.Tabla {
+	t := export.Tabla{
+		Nombre: "inventario_actual",
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Unidad de medida", Tipo: export.Texto},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Stock actual", Tipo: export.Entero},
+			{Titulo: "Valor total", Tipo: export.Moneda},
+		},
+	}
+	conUnidad := slices.ContainsFunc(items, func(i domain.ReporteInventarioItem) bool { return i.UnidadReporte != "" })
+	if conUnidad {
+		t.Columnas = append(t.Columnas, export.Columna{Titulo: "Unidad reporte", Tipo: export.Texto}, export.Columna{Titulo: "Stock en unidad reporte", Tipo: export.Decimal})
+	}
+	for _, i := range items {
+		fila := []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.UnidadMedida, i.PrecioUnitario, i.StockActual, i.ValorTotal}
+		if conUnidad {
+			fila = append(fila, i.UnidadReporte, cantidadReporte(i.UnidadReporte, i.CantidadReporte))
+		}
+		t.Filas = append(t.Filas, fila)
+	}
+	return t
+}
+
+func MovimientosTabla(items []domain.ReporteMovimiento) export.Tabla {
+	t := export.Tabla{
+		Nombre: "movimientos",
+		Columnas: []export.Columna{
+			{Titulo: "Fecha", Tipo: export.Fecha},
+			{Titulo: "Tipo", Tipo: export.Texto},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Cantidad", Tipo: export.Entero},
+			{Titulo: "Precio", Tipo: export.Moneda},
+			{Titulo: "Total", Tipo: export.Moneda},
+			{Titulo: "Lugar de venta", Tipo: export.Texto},
+			{Titulo: "Tipo de pago", Tipo: export.Texto},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.Fecha, i.Tipo, i.Codigo, i.Nombre, i.Categoria, i.Cantidad, i.Precio, i.Total, i.LugarVenta, i.TipoPago})
+	}
+	return t
+}
+
+func ProductosVendidosTabla(nombre, tituloCantidad, tituloMonto string, items []domain.ReporteProductoVendido) export.Tabla {
+	t := export.Tabla{
+		Nombre: nombre,
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: tituloCantidad, Tipo: export.Entero},
+			{Titulo: tituloMonto, Tipo: export.Moneda},
+		},
+	}
+	conUnidad := slices.ContainsFunc(items, func(i domain.ReporteProductoVendido) bool { return i.UnidadReporte != "" })
+	if conUnidad {
+		t.Columnas = append(t.Columnas, export.Columna{Titulo: "Unidad reporte", Tipo: export.Texto}, export.Columna{Titulo: tituloCantidad + " (unidad reporte)", Tipo: export.Decimal})
+	}
+	for _, i := range items {
+		fila := []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.TotalVendido, i.TotalIngresos}
+		if conUnidad {
+			fila = append(fila, i.UnidadReporte, cantidadReporte(i.UnidadReporte, i.CantidadReporte))
+		}
+		t.Filas = append(t.Filas, fila)
+	}
+	return t
+}
+
+func ValoracionTabla(items []domain.ReporteValoracion) export.Tabla {
+	t := export.Tabla{
+		Nombre: "valoracion_inventario",
+		Columnas: []export.Columna{
+			{Titulo: "ID Categoría", Tipo: export.Entero},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Productos", Tipo: export.Entero},
+			{Titulo: "Unidades", Tipo: export.Entero},
+			{Titulo: "Valor total", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDCategoria, i.NombreCategoria, i.TotalProductos, i.TotalUnidades, i.ValorTotal})
+	}
+	return t
+}
+
+func CoberturaTabla(items []domain.ReporteCobertura) export.Tabla {
+	t := export.Tabla{
+		Nombre: "cobertura_stock",
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Stock actual", Tipo: export.Entero},
+		},
+	}
+	// Las ventanas son las mismas para todos los productos
+	var ventanas []domain.VelocidadVenta
+	if len(items) > 0 {
+		ventanas = items[0].Velocidades
+	}
+	for _, v := range ventanas {
+		t.Columnas = append(t.Columnas, export.Columna{Titulo: fmt.Sprintf("Promedio diario %d días", v.DiasVentana), Tipo: export.Decimal})
+	}
+	t.Columnas = append(t.Columnas,
+		export.Columna{Titulo: "Días de cobertura", Tipo: export.Decimal},
+		export.Columna{Titulo: "Fecha de quiebre", Tipo: export.Fecha},
+		export.Columna{Titulo: "Cantidad sugerida", Tipo: export.Entero},
+	)
+	for _, i := range items {
+		fila := []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.StockActual}
+		for _, v := range i.Velocidades {
+			fila = append(fila, v.PromedioDiario)
+		}
+		fila = append(fila, i.DiasCobertura, i.FechaQuiebre, i.CantidadSugerida)
+		t.Filas = append(t.Filas, fila)
+	}
+	return t
+}
+
+func ABCTabla(items []domain.ReporteABC) export.Tabla {
+	t := export.Tabla{
+		Nombre: "clasificacion_abc",
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Unidades vendidas", Tipo: export.Entero},
+			{Titulo: "Ingresos", Tipo: export.Moneda},
+			{Titulo: "Participación %", Tipo: export.Porcentaje},
+			{Titulo: "Participación acumulada %", Tipo: export.Porcentaje},
+			{Titulo: "Clase", Tipo: export.Texto},
+			{Titulo: "Frecuencia de conteo (días)", Tipo: export.Entero},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.TotalVendido, i.TotalIngresos, i.Participacion, i.ParticipacionAcumulada, i.Clase, i.FrecuenciaConteoDias})
+	}
+	return t
+}
+
+func StockInmovilizadoTabla(items []domain.ReporteStockInmovilizado) export.Tabla {
+	t := export.Tabla{
+		Nombre: "stock_inmovilizado",
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Stock actual", Tipo: export.Entero},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Valor inmovilizado", Tipo: export.Moneda},
+			{Titulo: "Última venta", Tipo: export.Fecha},
+			{Titulo: "Días sin venta", Tipo: export.Entero},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.StockActual, i.PrecioUnitario, i.ValorInmovilizado, i.UltimaVenta, i.DiasSinVenta})
+	}
+	return t
+}
+
+// IGVTabla lista ventas y compras por afectación; la última fila es el IGV por pagar
+func IGVTabla(r *domain.ReporteIGV) export.Tabla {
+	t := export.Tabla{
+		Nombre: fmt.Sprintf("igv_%04d_%02d", r.Anio, r.Mes),
+		Columnas: []export.Columna{
+			{Titulo: "Operación", Tipo: export.Texto},
+			{Titulo: "Afectación IGV", Tipo: export.Texto},
+			{Titulo: "Operaciones", Tipo: export.Entero},
+			{Titulo: "Base imponible", Tipo: export.Moneda},
+			{Titulo: "IGV", Tipo: export.Moneda},
+			{Titulo: "Total", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range r.Ventas {
+		t.Filas = append(t.Filas, []any{"Ventas", i.Afectacion, i.Operaciones, i.BaseImponible, i.IGV, i.Total})
+	}
+	for _, i := range r.Compras {
+		t.Filas = append(t.Filas, []any{"Compras", i.Afectacion, i.Operaciones, i.BaseImponible, i.IGV, i.Total})
+	}
+	t.Filas = append(t.Filas, []any{"IGV por pagar", "", nil, nil, r.IGVPorPagar, nil})
+	return t
+}
+
+func PromocionesTabla(items []domain.ReportePromocion) export.Tabla {
+	t := export.Tabla{
+		Nombre: "descuentos_promociones",
+		Columnas: []export.Columna{
+			{Titulo: "ID Promoción", Tipo: export.Entero},
+			{Titulo: "Promoción", Tipo: export.Texto},
+			{Titulo: "Tipo", Tipo: export.Texto},
+			{Titulo: "Ventas", Tipo: export.Entero},
+			{Titulo: "Unidades", Tipo: export.Entero},
+			{Titulo: "Ingresos", Tipo: export.Moneda},
+			{Titulo: "Costo del descuento", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDPromocion, i.Nombre, i.Tipo, i.Ventas, i.Unidades, i.Ingresos, i.CostoDescuento})
+	}
+	return t
+}
+
+func ComprasProveedorTabla(items []domain.ReporteCompraProveedor) export.Tabla {
+	t := export.Tabla{
+		Nombre: "compras_proveedor",
+		Columnas: []export.Columna{
+			{Titulo: "Proveedor", Tipo: export.Texto},
+			{Titulo: "Entradas", Tipo: export.Entero},
+			{Titulo: "Unidades", Tipo: export.Entero},
+			{Titulo: "Compras", Tipo: export.Moneda},
+			{Titulo: "Unidades devueltas", Tipo: export.Entero},
+			{Titulo: "Monto devuelto", Tipo: export.Moneda},
+			{Titulo: "Compras netas", Tipo: export.Moneda},
+			{Titulo: "Crédito pendiente", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.Proveedor, i.Entradas, i.Unidades, i.MontoCompras, i.UnidadesDevueltas, i.MontoDevuelto, i.MontoNeto, i.CreditoPendiente})
+	}
+	return t
+}
+
+func VentasCategoriaTabla(items []domain.ReporteVentasCategoria) export.Tabla {
+	t := export.Tabla{
+		Nombre: "ventas_categoria",
+		Columnas: []export.Columna{
+			{Titulo: "ID Categoría", Tipo: export.Entero},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Ventas", Tipo: export.Entero},
+			{Titulo: "Unidades", Tipo: export.Entero},
+			{Titulo: "Ingresos", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDCategoria, i.Categoria, i.Ventas, i.Unidades, i.Ingresos})
+	}
+	return t
+}
+
+func ClientesTabla(clientes []domain.ClienteConSaldo) export.Tabla {
+	t := export.Tabla{
+		Nombre: "clientes",
+		Columnas: []export.Columna{
+			{Titulo: "ID", Tipo: export.Entero},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Tipo documento", Tipo: export.Texto},
+			{Titulo: "Número documento", Tipo: export.Texto},
+			{Titulo: "Teléfono", Tipo: export.Texto},
+			{Titulo: "Dirección", Tipo: export.Texto},
+			{Titulo: "Email", Tipo: export.Texto},
+			{Titulo: "Límite de crédito", Tipo: export.Moneda},
+			{Titulo: "Días de crédito", Tipo: export.Entero},
+			{Titulo: "Saldo", Tipo: export.Moneda},
+			{Titulo: "Activo", Tipo: export.Texto},
+		},
+	}
+	for _, c := range clientes {
+		t.Filas = append(t.Filas, []any{c.ID, c.Nombre, c.TipoDocumento, c.NumeroDocumento, c.Telefono, c.Direccion, c.Email, c.LimiteCredito, c.DiasCredito, c.Saldo, c.Activo})
+	}
+	return t
+}
+
+func EstadoCuentaTabla(e *domain.EstadoCuenta) export.Tabla {
+	t := export.Tabla{
+		Nombre: fmt.Sprintf("estado_cuenta_cliente_%d", e.Cliente.ID),
+		Columnas: []export.Columna{
+			{Titulo: "Fecha", Tipo: export.Fecha},
+			{Titulo: "Tipo", Tipo: export.Texto},
+			{Titulo: "Referencia", Tipo: export.Texto},
+			{Titulo: "Cargo", Tipo: export.Moneda},
+			{Titulo: "Abono", Tipo: export.Moneda},
+			{Titulo: "Saldo", Tipo: export.Moneda},
+		},
+	}
+	t.Filas = append(t.Filas, []any{nil, "", "Saldo inicial", nil, nil, e.SaldoInicial})
+	for _, m := range e.Movimientos {
+		t.Filas = append(t.Filas, []any{m.Fecha, m.Tipo, m.Referencia, m.Cargo, m.Abono, m.Saldo})
+	}
+	t.Filas = append(t.Filas, []any{nil, "", "Totales", e.TotalCargos, e.TotalAbonos, e.SaldoFinal})
+	return t
+}
+
+func AntiguedadTabla(items []domain.ReporteAntiguedad) export.Tabla {
+	t := export.Tabla{
+		Nombre: "antiguedad_cuentas_por_cobrar",
+		Columnas: []export.Columna{
+			{Titulo: "ID Cliente", Tipo: export.Entero},
+			{Titulo: "Cliente", Tipo: export.Texto},
+			{Titulo: "Por vencer", Tipo: export.Moneda},
+			{Titulo: "1-30 días", Tipo: export.Moneda},
+			{Titulo: "31-60 días", Tipo: export.Moneda},
+			{Titulo: "61-90 días", Tipo: export.Moneda},
+			{Titulo: "Más de 90 días", Tipo: export.Moneda},
+			{Titulo: "Total", Tipo: export.Moneda},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDCliente, i.Cliente, i.PorVencer, i.Dias1a30, i.Dias31a60, i.Dias61a90, i.Mas90, i.Total})
+	}
+	return t
+}
+
+func AlertasStockBajoTabla(items []domain.AlertaStockBajo) export.Tabla {
+	t := export.Tabla{
+		Nombre: "alertas_stock_bajo",
+		Columnas: []export.Columna{
+			{Titulo: "ID Producto", Tipo: export.Entero},
+			{Titulo: "Código", Tipo: export.Texto},
+			{Titulo: "Nombre", Tipo: export.Texto},
+			{Titulo: "Categoría", Tipo: export.Texto},
+			{Titulo: "Stock actual", Tipo: export.Entero},
+			{Titulo: "Stock inicial", Tipo: export.Entero},
+			{Titulo: "Precio unitario", Tipo: export.Moneda},
+			{Titulo: "Variante", Tipo: export.Texto},
+		},
+	}
+	for _, i := range items {
+		t.Filas = append(t.Filas, []any{i.IDProducto, i.Codigo, i.Nombre, i.Categoria, i.StockActual, i.StockInicial, i.PrecioUnitario, domain.DescribirAtributos(i.Atributos)})
+	}
+	return t
+}
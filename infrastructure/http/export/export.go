@@ -0,0 +1,275 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formato es el formato de respuesta solicitado por el cliente
+type Formato string
+
+const (
+	JSON Formato = "json"
+	CSV  Formato = "csv"
+	XLSX Formato = "xlsx"
+)
+
+const (
+	mimeCSV  = "text/csv"
+	mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ErrFormatoNoSoportado indica un valor de ?format= desconocido
+var ErrFormatoNoSoportado = errors.New("formato no soportado, use json, csv o xlsx")
+
+// TipoColumna define cómo se formatea el valor de una columna
+type TipoColumna int
+
+const (
+	Texto TipoColumna = iota
+	Entero
+	Decimal
+	Moneda
+	Porcentaje
+	Fecha
+)
+
+// Columna es una columna exportable con su título en español
+type Columna struct {
+	Titulo string
+	Tipo   TipoColumna
+}
+
+// Tabla es un reporte listo para exportar. Las filas deben tener un valor por columna;
+// se aceptan punteros nil para celdas vacías.
+type Tabla struct {
+	Nombre   string
+	Columnas []Columna
+	Filas    [][]any
+}
+
+// Negociar determina el formato a partir de ?format= o, en su defecto, del encabezado Accept
+func Negociar(c *gin.Context) (Formato, error) {
+	if f := strings.ToLower(strings.TrimSpace(c.Query("format"))); f != "" {
+		switch Formato(f) {
+		case JSON, CSV, XLSX:
+			return Formato(f), nil
+		default:
+			return JSON, ErrFormatoNoSoportado
+		}
+	}
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, mimeXLSX):
+		return XLSX, nil
+	case strings.Contains(accept, mimeCSV):
+		return CSV, nil
+	default:
+		return JSON, nil
+	}
+}
+
+// Escribir envía la tabla como archivo descargable en el formato indicado
+func Escribir(c *gin.Context, formato Formato, tabla Tabla) error {
+	nombre := fmt.Sprintf("%s_%s.%s", tabla.Nombre, time.Now().Format("20060102"), formato)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nombre))
+	switch formato {
+	case CSV:
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		return escribirCSV(c.Writer, tabla)
+	case XLSX:
+		c.Header("Content-Type", mimeXLSX)
+		return escribirXLSX(c.Writer, tabla)
+	default:
+		return ErrFormatoNoSoportado
+	}
+}
+
+// escribirCSV escribe fila por fila. El BOM inicial permite que Excel reconozca el UTF-8.
+func escribirCSV(w io.Writer, tabla Tabla) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	encabezados := make([]string, len(tabla.Columnas))
+	for i, col := range tabla.Columnas {
+		encabezados[i] = col.Titulo
+	}
+	if err := cw.Write(encabezados); err != nil {
+		return err
+	}
+	registro := make([]string, len(tabla.Columnas))
+	for _, fila := range tabla.Filas {
+		for i, col := range tabla.Columnas {
+			registro[i] = formatearTexto(ValorCelda(fila, i), col.Tipo)
+		}
+		if err := cw.Write(registro); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatearTexto(v any, tipo TipoColumna) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		if x {
+			return "Sí"
+		}
+		return "No"
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		if tipo == Entero {
+			return strconv.FormatFloat(x, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(x, 'f', 2, 64)
+	case time.Time:
+		return x.Format("2006-01-02")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// escribirXLSX usa el StreamWriter de excelize para no construir la hoja completa en memoria
+func escribirXLSX(w io.Writer, tabla Tabla) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	hoja := nombreHoja(tabla.Nombre)
+	if err := f.SetSheetName("Sheet1", hoja); err != nil {
+		return err
+	}
+	sw, err := f.NewStreamWriter(hoja)
+	if err != nil {
+		return err
+	}
+	estilos, err := crearEstilos(f)
+	if err != nil {
+		return err
+	}
+
+	if err := sw.SetColWidth(1, max(len(tabla.Columnas), 1), 18); err != nil {
+		return err
+	}
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	encabezados := make([]any, len(tabla.Columnas))
+	for i, col := range tabla.Columnas {
+		encabezados[i] = excelize.Cell{StyleID: estilos.encabezado, Value: col.Titulo}
+	}
+	if err := sw.SetRow("A1", encabezados); err != nil {
+		return err
+	}
+	for n, fila := range tabla.Filas {
+		celdas := make([]any, len(tabla.Columnas))
+		for i, col := range tabla.Columnas {
+			v := ValorCelda(fila, i)
+			if b, ok := v.(bool); ok {
+				v = formatearTexto(b, col.Tipo)
+			}
+			celdas[i] = excelize.Cell{StyleID: estilos.porTipo[col.Tipo], Value: v}
+		}
+		celda, err := excelize.CoordinatesToCellName(1, n+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(celda, celdas); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+type estilosXLSX struct {
+	encabezado int
+	porTipo    map[TipoColumna]int
+}
+
+func crearEstilos(f *excelize.File) (estilosXLSX, error) {
+	e := estilosXLSX{porTipo: map[TipoColumna]int{}}
+	var err error
+	e.encabezado, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+	})
+	if err != nil {
+		return e, err
+	}
+	formatos := map[TipoColumna]string{
+		Entero:     "#,##0",
+		Decimal:    "#,##0.00",
+		Moneda:     "#,##0.00",
+		Porcentaje: `0.00"%"`,
+		Fecha:      "dd/mm/yyyy",
+	}
+	for tipo, formato := range formatos {
+		fmtCustom := formato
+		id, err := f.NewStyle(&excelize.Style{CustomNumFmt: &fmtCustom})
+		if err != nil {
+			return e, err
+		}
+		e.porTipo[tipo] = id
+	}
+	return e, nil
+}
+
+// nombreHoja ajusta el nombre al límite de 31 caracteres de Excel
+func nombreHoja(nombre string) string {
+	if nombre == "" {
+		return "Reporte"
+	}
+	if r := []rune(nombre); len(r) > 31 {
+		return string(r[:31])
+	}
+	return nombre
+}
+
+// ValorCelda retorna el valor de la columna i desreferenciando punteros
+func ValorCelda(fila []any, i int) any {
+	if i >= len(fila) {
+		return nil
+	}
+	switch x := fila[i].(type) {
+	case *float64:
+		if x == nil {
+			return nil
+		}
+		return *x
+	case *int:
+		if x == nil {
+			return nil
+		}
+		return *x
+	case *string:
+		if x == nil {
+			return nil
+		}
+		return *x
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return *x
+	default:
+		return x
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,16 +20,20 @@ func NewAlertasHandler(service application.AlertasService) *AlertasHandler {
 
 func (h *AlertasHandler) GetAlertasActivas(c *gin.Context) {
 	limite, _ := strconv.Atoi(c.DefaultQuery("limite", "3"))
-	items, err := h.service.GetAlertasActivas(limite)
+	diasQuiebre, _ := strconv.Atoi(c.DefaultQuery("dias_quiebre", "7"))
+	nivel, _ := strconv.Atoi(c.DefaultQuery("nivel", "0"))
+	alertas, err := h.service.GetAlertasActivas(limite, diasQuiebre, nivel)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, dto.AlertasResponse{
-		Success:     true,
-		Message:     "Alertas activas",
-		StockBajo:   dto.AlertasStockBajoToResponse(items),
-		TotalAlerts: len(items),
+		Success:         true,
+		Message:         "Alertas activas",
+		StockBajo:       dto.AlertasStockBajoToResponse(alertas.StockBajo),
+		QuiebrePrevisto: dto.ReportesCoberturaToResponse(alertas.QuiebrePrevisto),
+		PorCategoria:    dto.AlertasCategoriaToResponse(alertas.PorCategoria),
+		TotalAlerts:     len(alertas.StockBajo) + len(alertas.QuiebrePrevisto),
 	})
 }
 
@@ -39,6 +44,9 @@ func (h *AlertasHandler) GetStockBajo(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.AlertasStockBajoTabla(items) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.AlertasResponse{
 		Success:     true,
 		Message:     "Productos con stock bajo",
@@ -47,6 +55,25 @@ func (h *AlertasHandler) GetStockBajo(c *gin.Context) {
 	})
 }
 
+func (h *AlertasHandler) GetQuiebrePrevisto(c *gin.Context) {
+	dias, _ := strconv.Atoi(c.DefaultQuery("dias", "7"))
+	items, err := h.service.GetQuiebrePrevisto(dias)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.CoberturaTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.AlertasResponse{
+		Success:         true,
+		Message:         "Productos con quiebre de stock previsto",
+		StockBajo:       []dto.AlertaStockBajoItem{},
+		QuiebrePrevisto: dto.ReportesCoberturaToResponse(items),
+		TotalAlerts:     len(items),
+	})
+}
+
 func (h *AlertasHandler) ConfigurarAlerta(c *gin.Context) {
 	var req dto.ConfigurarAlertaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
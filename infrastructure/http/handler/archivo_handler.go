@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// margenMultipart cubre los encabezados y demás campos del formulario
+const margenMultipart = 1 << 20
+
+type ArchivoHandler struct {
+	service application.ArchivoService
+}
+
+func NewArchivoHandler(service application.ArchivoService) *ArchivoHandler {
+	return &ArchivoHandler{service: service}
+}
+
+// Imágenes de productos y adjuntos de entradas y control diario: cada ruta fija la entidad
+func (h *ArchivoHandler) GetImagenes(c *gin.Context) {
+	h.listar(c, domain.EntidadProducto)
+}
+
+func (h *ArchivoHandler) SubirImagen(c *gin.Context) {
+	h.subir(c, domain.EntidadProducto)
+}
+
+func (h *ArchivoHandler) EliminarImagen(c *gin.Context) {
+	h.eliminar(c, domain.EntidadProducto)
+}
+
+func (h *ArchivoHandler) GetAdjuntosEntrada(c *gin.Context) {
+	h.listar(c, domain.EntidadEntrada)
+}
+
+func (h *ArchivoHandler) SubirAdjuntoEntrada(c *gin.Context) {
+	h.subir(c, domain.EntidadEntrada)
+}
+
+func (h *ArchivoHandler) EliminarAdjuntoEntrada(c *gin.Context) {
+	h.eliminar(c, domain.EntidadEntrada)
+}
+
+func (h *ArchivoHandler) GetAdjuntosControl(c *gin.Context) {
+	h.listar(c, domain.EntidadControlDiario)
+}
+
+func (h *ArchivoHandler) SubirAdjuntoControl(c *gin.Context) {
+	h.subir(c, domain.EntidadControlDiario)
+}
+
+func (h *ArchivoHandler) EliminarAdjuntoControl(c *gin.Context) {
+	h.eliminar(c, domain.EntidadControlDiario)
+}
+
+func (h *ArchivoHandler) listar(c *gin.Context, entidad string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	archivos, err := h.service.GetByEntidad(entidad, id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Archivos obtenidos exitosamente",
+		Data:    dto.ArchivosToResponse(archivos),
+	})
+}
+
+// subir guarda el archivo recibido. El tipo se detecta del contenido; el Content-Type
+// que declara el cliente no se usa.
+func (h *ArchivoHandler) subir(c *gin.Context, entidad string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	maximo := int64(domain.TamanoMaximoAdjunto)
+	if domain.TipoArchivoEntidad(entidad) == domain.TipoArchivoImagen {
+		maximo = domain.TamanoMaximoImagen
+	}
+	contenido, nombre, ok := leerArchivoSubido(c, maximo)
+	if !ok {
+		return
+	}
+	registrado, err := h.service.Subir(entidad, id, nombre, http.DetectContentType(contenido), contenido, c.GetString("username"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Archivo subido exitosamente",
+		Data:    dto.ArchivoToResponse(registrado),
+	})
+}
+
+// leerArchivoSubido lee el campo "archivo" de un formulario multipart sin aceptar más
+// de 'maximo' bytes. Si falla ya respondió al cliente y retorna false.
+func leerArchivoSubido(c *gin.Context, maximo int64) ([]byte, string, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maximo+margenMultipart)
+	cabecera, err := c.FormFile("archivo")
+	if err != nil {
+		var demasiadoGrande *http.MaxBytesError
+		if errors.As(err, &demasiadoGrande) {
+			handleDomainError(c, &domain.ErrValidation{Field: "archivo", Message: fmt.Sprintf("no puede superar %d MB", maximo>>20)})
+			return nil, "", false
+		}
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: "envíe el archivo en el campo 'archivo' de un formulario multipart"})
+		return nil, "", false
+	}
+	archivo, err := cabecera.Open()
+	if err != nil {
+		handleDomainError(c, err)
+		return nil, "", false
+	}
+	defer archivo.Close()
+	// Se lee un byte más del máximo para detectar los archivos demasiado grandes
+	contenido, err := io.ReadAll(io.LimitReader(archivo, maximo+1))
+	if err != nil {
+		handleDomainError(c, err)
+		return nil, "", false
+	}
+	return contenido, cabecera.Filename, true
+}
+
+func (h *ArchivoHandler) eliminar(c *gin.Context, entidad string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	idArchivo, err := strconv.Atoi(c.Param("idArchivo"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	if err := h.service.Eliminar(entidad, id, idArchivo); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Archivo eliminado exitosamente"})
+}
+
+// Descargar devuelve el contenido con su tipo; las imágenes y los PDF se muestran en
+// el navegador
+func (h *ArchivoHandler) Descargar(c *gin.Context) {
+	h.enviar(c, false)
+}
+
+// Miniatura devuelve la miniatura JPEG de una imagen
+func (h *ArchivoHandler) Miniatura(c *gin.Context) {
+	h.enviar(c, true)
+}
+
+func (h *ArchivoHandler) enviar(c *gin.Context, miniatura bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	archivo, contenido, err := h.service.Abrir(id, miniatura)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	defer contenido.Close()
+	tipo := archivo.ContentType
+	if miniatura {
+		tipo = "image/jpeg"
+	} else if archivo.NombreOriginal != "" {
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", archivo.NombreOriginal))
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=86400")
+	// El tamaño registrado es el del original; el de la miniatura no se conoce
+	largo := archivo.Tamano
+	if miniatura {
+		largo = -1
+	}
+	c.DataFromReader(http.StatusOK, largo, tipo, contenido, nil)
+}
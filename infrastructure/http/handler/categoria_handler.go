@@ -6,6 +6,7 @@ import (
 
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,12 +18,17 @@ func NewCategoriaHandler(service application.CategoriaService) *CategoriaHandler
 	return &CategoriaHandler{service: service}
 }
 
+// GetAll lista las categorías activas; ?incluir_inactivos=true suma las dadas de baja
 func (h *CategoriaHandler) GetAll(c *gin.Context) {
-	categorias, err := h.service.GetAll()
+	incluirInactivos, _ := strconv.ParseBool(c.Query("incluir_inactivos"))
+	categorias, err := h.service.GetAll(incluirInactivos)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.CategoriasTabla(categorias) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.CategoriasResponse{
 		Success:    true,
 		Message:    "Categorías obtenidas exitosamente",
@@ -31,6 +37,21 @@ func (h *CategoriaHandler) GetAll(c *gin.Context) {
 	})
 }
 
+// GetArbol retorna las categorías raíz con sus subcategorías anidadas
+func (h *CategoriaHandler) GetArbol(c *gin.Context) {
+	incluirInactivos, _ := strconv.ParseBool(c.Query("incluir_inactivos"))
+	arbol, err := h.service.GetArbol(incluirInactivos)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Árbol de categorías obtenido exitosamente",
+		Data:    dto.ArbolCategoriasToResponse(arbol),
+	})
+}
+
 func (h *CategoriaHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -55,7 +76,7 @@ func (h *CategoriaHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
 		return
 	}
-	categoria, err := h.service.Create(req.Nombre)
+	categoria, err := h.service.Create(req.Nombre, req.IDCategoriaPadre)
 	if err != nil {
 		handleDomainError(c, err)
 		return
@@ -78,7 +99,7 @@ func (h *CategoriaHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
 		return
 	}
-	categoria, err := h.service.Update(id, req.Nombre)
+	categoria, err := h.service.Update(id, req.Nombre, req.IDCategoriaPadre)
 	if err != nil {
 		handleDomainError(c, err)
 		return
@@ -100,5 +121,23 @@ func (h *CategoriaHandler) Delete(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Categoría eliminada exitosamente"})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Categoría dada de baja exitosamente"})
+}
+
+func (h *CategoriaHandler) Restaurar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	categoria, err := h.service.Restaurar(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Categoría restaurada exitosamente",
+		Data:    dto.CategoriaToResponse(categoria),
+	})
 }
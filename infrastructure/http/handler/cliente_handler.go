@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/gin-gonic/gin"
+)
+
+type ClienteHandler struct {
+	service application.ClienteService
+}
+
+func NewClienteHandler(service application.ClienteService) *ClienteHandler {
+	return &ClienteHandler{service: service}
+}
+
+// GetAll lista los clientes con su saldo; ?q busca por nombre, documento o teléfono
+func (h *ClienteHandler) GetAll(c *gin.Context) {
+	clientes, err := h.service.GetAll(c.Query("q"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.ClientesTabla(clientes) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Clientes obtenidos exitosamente",
+		Data:    dto.ClientesToResponse(clientes),
+	})
+}
+
+func (h *ClienteHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	cliente, err := h.service.GetByID(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Cliente encontrado",
+		Data:    dto.ClienteToResponse(cliente),
+	})
+}
+
+func (h *ClienteHandler) Create(c *gin.Context) {
+	cliente, ok := bindCliente(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.Create(cliente)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Cliente creado exitosamente",
+		Data:    dto.ClienteToResponse(&domain.ClienteConSaldo{Cliente: *result}),
+	})
+}
+
+func (h *ClienteHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	cliente, ok := bindCliente(c)
+	if !ok {
+		return
+	}
+	if _, err := h.service.Update(id, cliente); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	actualizado, err := h.service.GetByID(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Cliente actualizado exitosamente",
+		Data:    dto.ClienteToResponse(actualizado),
+	})
+}
+
+func bindCliente(c *gin.Context) (*domain.Cliente, bool) {
+	var req dto.ClienteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return nil, false
+	}
+	cliente := &domain.Cliente{
+		Nombre:          req.Nombre,
+		TipoDocumento:   req.TipoDocumento,
+		NumeroDocumento: req.NumeroDocumento,
+		Telefono:        req.Telefono,
+		Direccion:       req.Direccion,
+		Email:           req.Email,
+		LimiteCredito:   req.LimiteCredito,
+		DiasCredito:     30,
+		Activo:          req.Activo == nil || *req.Activo,
+	}
+	if req.DiasCredito != nil {
+		cliente.DiasCredito = *req.DiasCredito
+	}
+	return cliente, true
+}
+
+// GetCuentas lista las cuentas por cobrar del cliente; ?pendientes=true omite las pagadas
+func (h *ClienteHandler) GetCuentas(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	soloPendientes, _ := strconv.ParseBool(c.Query("pendientes"))
+	cuentas, err := h.service.GetCuentas(id, soloPendientes)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Cuentas por cobrar obtenidas exitosamente",
+		Data:    dto.CuentasPorCobrarToResponse(cuentas),
+	})
+}
+
+func (h *ClienteHandler) RegistrarPago(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.PagoClienteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fecha, err := time.Parse("2006-01-02", req.Fecha)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		return
+	}
+	pago, err := h.service.RegistrarPago(&domain.PagoCliente{
+		IDCliente:       id,
+		Fecha:           fecha,
+		Monto:           req.Monto,
+		TipoPago:        req.TipoPago,
+		Observaciones:   req.Observaciones,
+		UsuarioRegistro: req.UsuarioRegistro,
+	}, req.IDCuenta)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Pago registrado exitosamente",
+		Data:    dto.PagoClienteToResponse(pago),
+	})
+}
+
+// GetEstadoCuenta lista cargos y abonos del cliente con saldo acumulado en ?desde&hasta
+func (h *ClienteHandler) GetEstadoCuenta(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	estado, err := h.service.GetEstadoCuenta(id, domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.EstadoCuentaTabla(estado) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Estado de cuenta generado",
+		Data:    dto.EstadoCuentaToResponse(estado),
+	})
+}
+
+// GetAntiguedad reparte la deuda por tramos de atraso a ?fecha (hoy por defecto)
+func (h *ClienteHandler) GetAntiguedad(c *gin.Context) {
+	fecha := time.Now()
+	if v := c.Query("fecha"); v != "" {
+		var err error
+		if fecha, err = time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+			return
+		}
+	}
+	items, err := h.service.GetAntiguedad(fecha)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.AntiguedadTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Antigüedad de cuentas por cobrar generada",
+		Data:    dto.ReporteAntiguedadToResponse(items),
+	})
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/comprobantes"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type ComprobanteHandler struct {
+	service              application.ComprobanteService
+	configuracionService application.ConfiguracionService
+}
+
+func NewComprobanteHandler(service application.ComprobanteService, configuracionService application.ConfiguracionService) *ComprobanteHandler {
+	return &ComprobanteHandler{service: service, configuracionService: configuracionService}
+}
+
+func (h *ComprobanteHandler) GetByID(c *gin.Context) {
+	detalle, ok := h.detalle(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Comprobante encontrado",
+		Data:    dto.ComprobanteToResponse(detalle),
+	})
+}
+
+// GetPDF genera el comprobante imprimible en PDF de 80 mm
+func (h *ComprobanteHandler) GetPDF(c *gin.Context) {
+	detalle, ok := h.detalle(c)
+	if !ok {
+		return
+	}
+	negocio, err := h.configuracionService.GetNegocio()
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	contenido, err := comprobantes.PDF(detalle, negocio)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Error al generar el PDF", Error: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", detalle.Numeracion()+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", contenido)
+}
+
+// GetESCPOS devuelve los bytes ESC/POS para enviar directo a la impresora térmica
+func (h *ComprobanteHandler) GetESCPOS(c *gin.Context) {
+	var q dto.ImpresionQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	columnas, err := comprobantes.Columnas(cmp.Or(q.Papel, 80))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	detalle, ok := h.detalle(c)
+	if !ok {
+		return
+	}
+	negocio, err := h.configuracionService.GetNegocio()
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	contenido, err := comprobantes.ESCPOS(detalle, negocio, columnas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Error al generar el comprobante", Error: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", detalle.Numeracion()+".bin"))
+	c.Data(http.StatusOK, "application/octet-stream", contenido)
+}
+
+func (h *ComprobanteHandler) detalle(c *gin.Context) (*domain.ComprobanteDetalle, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return nil, false
+	}
+	detalle, err := h.service.GetDetalle(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return nil, false
+	}
+	return detalle, true
+}
+
+func (h *ComprobanteHandler) GetSeries(c *gin.Context) {
+	series, err := h.service.GetSeries()
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Series obtenidas exitosamente",
+		Data:    dto.SeriesComprobanteToResponse(series),
+	})
+}
+
+func (h *ComprobanteHandler) CreateSerie(c *gin.Context) {
+	var req dto.SerieComprobanteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	serie, err := h.service.CreateSerie(&domain.SerieComprobante{
+		Tipo:        req.Tipo,
+		Serie:       req.Serie,
+		LugarVenta:  req.LugarVenta,
+		Correlativo: req.Correlativo,
+		Activa:      req.Activa == nil || *req.Activa,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Serie creada exitosamente",
+		Data:    dto.SerieComprobanteToResponse(serie),
+	})
+}
+
+func (h *ComprobanteHandler) UpdateSerie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.SerieComprobanteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	serie, err := h.service.UpdateSerie(id, &domain.SerieComprobante{
+		Tipo:       req.Tipo,
+		LugarVenta: req.LugarVenta,
+		Activa:     req.Activa == nil || *req.Activa,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Serie actualizada exitosamente",
+		Data:    dto.SerieComprobanteToResponse(serie),
+	})
+}
+
+// GetConfiguracion lista los datos del negocio impresos en los comprobantes
+func (h *ComprobanteHandler) GetConfiguracion(c *gin.Context) {
+	valores, err := h.configuracionService.GetAll()
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Configuración obtenida exitosamente",
+		Data:    valores,
+	})
+}
+
+// UpdateConfiguracion guarda solo las claves enviadas
+func (h *ComprobanteHandler) UpdateConfiguracion(c *gin.Context) {
+	var req map[string]string
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	valores, err := h.configuracionService.Update(req)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Configuración actualizada exitosamente",
+		Data:    valores,
+	})
+}
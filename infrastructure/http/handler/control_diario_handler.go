@@ -7,36 +7,51 @@ import (
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
 	"github.com/gin-gonic/gin"
 )
 
 type ControlDiarioHandler struct {
 	service application.ControlDiarioService
+	pdf     *pdf.Generador
 }
 
-func NewControlDiarioHandler(service application.ControlDiarioService) *ControlDiarioHandler {
-	return &ControlDiarioHandler{service: service}
+func NewControlDiarioHandler(service application.ControlDiarioService, generador *pdf.Generador) *ControlDiarioHandler {
+	return &ControlDiarioHandler{service: service, pdf: generador}
 }
 
 func (h *ControlDiarioHandler) GetAll(c *gin.Context) {
-	controles, err := h.service.GetAll()
+	var q dto.ListadoControlDiarioQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	filtro := domain.FiltroControlDiario{
+		RangoFechas: domain.RangoFechas{Desde: q.Desde, Hasta: q.Hasta},
+		Usuario:     q.Usuario,
+		EsVerbena:   q.EsVerbena,
+	}
+	filtro.Paginacion, filtro.Orden = listado(c, q.ListadoQuery)
+	controles, totales, err := h.service.GetAll(filtro)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
-	var totalEntrada, totalSalida float64
-	for _, ctrl := range controles {
-		totalEntrada += ctrl.MontoEntrada
-		totalSalida += ctrl.MontoSalida
+	if exportar(c, func() export.Tabla { return dto.ControlDiariosTabla(controles) }) {
+		return
 	}
 	c.JSON(http.StatusOK, dto.ControlDiariosResponse{
 		Success:      true,
 		Message:      "Controles diarios obtenidos exitosamente",
 		Data:         dto.ControlDiariosToResponse(controles),
-		TotalCount:   len(controles),
-		TotalEntrada: totalEntrada,
-		TotalSalida:  totalSalida,
-		Balance:      totalEntrada - totalSalida,
+		TotalCount:   totales.Registros,
+		TotalEntrada: totales.MontoEntrada,
+		TotalSalida:  totales.MontoSalida,
+		Balance:      totales.MontoEntrada - totales.MontoSalida,
+		Pagina:       filtro.Pagina,
+		Limite:       filtro.Limite,
+		TotalPaginas: filtro.TotalPaginas(totales.Registros),
 	})
 }
 
@@ -52,6 +67,9 @@ func (h *ControlDiarioHandler) GetByFecha(c *gin.Context) {
 		totalEntrada += ctrl.MontoEntrada
 		totalSalida += ctrl.MontoSalida
 	}
+	if exportar(c, func() export.Tabla { return dto.ControlDiariosTabla(controles) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ControlDiariosResponse{
 		Success:      true,
 		Message:      "Controles del día obtenidos",
@@ -63,6 +81,16 @@ func (h *ControlDiarioHandler) GetByFecha(c *gin.Context) {
 	})
 }
 
+func (h *ControlDiarioHandler) GetByFechaPDF(c *gin.Context) {
+	fecha := c.Param("fecha")
+	controles, err := h.service.GetByFecha(fecha)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	enviarPDF(c, h.pdf, dto.ControlDiarioPDF(fecha, controles))
+}
+
 func (h *ControlDiarioHandler) GetHoy(c *gin.Context) {
 	controles, err := h.service.GetHoy()
 	if err != nil {
@@ -74,6 +102,9 @@ func (h *ControlDiarioHandler) GetHoy(c *gin.Context) {
 		totalEntrada += ctrl.MontoEntrada
 		totalSalida += ctrl.MontoSalida
 	}
+	if exportar(c, func() export.Tabla { return dto.ControlDiariosTabla(controles) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ControlDiariosResponse{
 		Success:      true,
 		Message:      "Control del día de hoy",
@@ -96,6 +127,9 @@ func (h *ControlDiarioHandler) GetVerbena(c *gin.Context) {
 		totalEntrada += ctrl.MontoEntrada
 		totalSalida += ctrl.MontoSalida
 	}
+	if exportar(c, func() export.Tabla { return dto.ControlDiariosTabla(controles) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ControlDiariosResponse{
 		Success:      true,
 		Message:      "Controles de verbena obtenidos",
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type DevolucionHandler struct {
+	service application.DevolucionService
+}
+
+func NewDevolucionHandler(service application.DevolucionService) *DevolucionHandler {
+	return &DevolucionHandler{service: service}
+}
+
+// GetAll lista las devoluciones de ?desde&hasta, las más recientes primero
+func (h *DevolucionHandler) GetAll(c *gin.Context) {
+	devoluciones, err := h.service.GetAll(domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devoluciones obtenidas exitosamente",
+		Data:    dto.DevolucionesToResponse(devoluciones),
+	})
+}
+
+func (h *DevolucionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	devolucion, err := h.service.GetByID(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devolución encontrada",
+		Data:    dto.DevolucionConProductoToResponse(devolucion),
+	})
+}
+
+// GetBySalida lista lo devuelto de una salida
+func (h *DevolucionHandler) GetBySalida(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	devoluciones, err := h.service.GetBySalida(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devoluciones de la salida obtenidas exitosamente",
+		Data:    dto.DevolucionesToResponse(devoluciones),
+	})
+}
+
+func (h *DevolucionHandler) Create(c *gin.Context) {
+	var req dto.CreateDevolucionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fecha, err := time.Parse("2006-01-02", req.Fecha)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		return
+	}
+	devolucion, err := h.service.Create(&domain.DevolucionCliente{
+		IDSalida:        req.IDSalida,
+		Fecha:           fecha,
+		Cantidad:        req.Cantidad,
+		Destino:         req.Destino,
+		MetodoReembolso: req.MetodoReembolso,
+		Motivo:          req.Motivo,
+		UsuarioRegistro: req.UsuarioRegistro,
+	}, req.MontoReembolso)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Devolución registrada exitosamente",
+		Data:    dto.DevolucionToResponse(devolucion),
+	})
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type DevolucionProveedorHandler struct {
+	service application.DevolucionProveedorService
+}
+
+func NewDevolucionProveedorHandler(service application.DevolucionProveedorService) *DevolucionProveedorHandler {
+	return &DevolucionProveedorHandler{service: service}
+}
+
+// GetAll lista las devoluciones a proveedores; filtra por ?desde&hasta, ?proveedor y ?estado
+func (h *DevolucionProveedorHandler) GetAll(c *gin.Context) {
+	devoluciones, err := h.service.GetAll(domain.FiltroDevolucionesProveedor{
+		RangoFechas: domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")},
+		Proveedor:   c.Query("proveedor"),
+		Estado:      c.Query("estado"),
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devoluciones a proveedores obtenidas exitosamente",
+		Data:    dto.DevolucionesProveedorToResponse(devoluciones),
+	})
+}
+
+func (h *DevolucionProveedorHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	devolucion, err := h.service.GetByID(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devolución a proveedor encontrada",
+		Data:    dto.DevolucionProveedorConProductoToResponse(devolucion),
+	})
+}
+
+// GetByEntrada lista lo devuelto al proveedor de una entrada
+func (h *DevolucionProveedorHandler) GetByEntrada(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	devoluciones, err := h.service.GetByEntrada(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devoluciones de la entrada obtenidas exitosamente",
+		Data:    dto.DevolucionesProveedorToResponse(devoluciones),
+	})
+}
+
+func (h *DevolucionProveedorHandler) Create(c *gin.Context) {
+	var req dto.CreateDevolucionProveedorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fecha, err := time.Parse("2006-01-02", req.Fecha)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		return
+	}
+	devolucion, err := h.service.Create(&domain.DevolucionProveedor{
+		IDEntrada:       req.IDEntrada,
+		Fecha:           fecha,
+		Cantidad:        req.Cantidad,
+		TipoCredito:     req.TipoCredito,
+		Motivo:          req.Motivo,
+		UsuarioRegistro: req.UsuarioRegistro,
+	}, req.MontoEsperado)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Devolución a proveedor registrada exitosamente",
+		Data:    dto.DevolucionProveedorToResponse(devolucion),
+	})
+}
+
+// Liquidar cierra la devolución con la nota de crédito o el reembolso del proveedor
+func (h *DevolucionProveedorHandler) Liquidar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.LiquidarDevolucionProveedorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fecha, err := time.Parse("2006-01-02", req.Fecha)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		return
+	}
+	devolucion, err := h.service.Liquidar(id, &domain.LiquidacionProveedor{
+		Fecha:           fecha,
+		Monto:           req.Monto,
+		NumeroNota:      req.NumeroNota,
+		UsuarioRegistro: req.UsuarioRegistro,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Devolución a proveedor liquidada exitosamente",
+		Data:    dto.DevolucionProveedorConProductoToResponse(devolucion),
+	})
+}
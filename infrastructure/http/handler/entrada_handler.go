@@ -8,6 +8,7 @@ import (
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,16 +21,35 @@ func NewEntradaHandler(service application.EntradaProductoService) *EntradaHandl
 }
 
 func (h *EntradaHandler) GetAll(c *gin.Context) {
-	entradas, err := h.service.GetAll()
+	var q dto.ListadoEntradasQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	filtro := domain.FiltroEntradas{
+		RangoFechas: domain.RangoFechas{Desde: q.Desde, Hasta: q.Hasta},
+		IDProducto:  q.IDProducto,
+		IDCategoria: q.IDCategoria,
+		Usuario:     q.Usuario,
+		Proveedor:   q.Proveedor,
+	}
+	filtro.Paginacion, filtro.Orden = listado(c, q.ListadoQuery)
+	entradas, total, err := h.service.GetAll(filtro)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.EntradasTabla(entradas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.EntradasResponse{
-		Success:    true,
-		Message:    "Entradas obtenidas exitosamente",
-		Data:       dto.EntradasConProductoToResponse(entradas),
-		TotalCount: len(entradas),
+		Success:      true,
+		Message:      "Entradas obtenidas exitosamente",
+		Data:         dto.EntradasConProductoToResponse(entradas),
+		TotalCount:   total,
+		Pagina:       filtro.Pagina,
+		Limite:       filtro.Limite,
+		TotalPaginas: filtro.TotalPaginas(total),
 	})
 }
 
@@ -62,6 +82,9 @@ func (h *EntradaHandler) GetByProductoID(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.EntradasTabla(entradas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.EntradasResponse{
 		Success:    true,
 		Message:    "Entradas del producto obtenidas",
@@ -77,6 +100,9 @@ func (h *EntradaHandler) GetByFecha(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.EntradasTabla(entradas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.EntradasResponse{
 		Success:    true,
 		Message:    "Entradas de la fecha obtenidas",
@@ -98,9 +124,12 @@ func (h *EntradaHandler) Create(c *gin.Context) {
 	}
 	entrada := &domain.EntradaProducto{
 		IDProducto:      req.IDProducto,
+		CodigoBarras:    req.CodigoBarras,
+		Unidad:          req.Unidad,
 		FechaEntrada:    fechaEntrada,
 		Cantidad:        req.Cantidad,
 		PrecioUnitario:  req.PrecioUnitario,
+		Proveedor:       req.Proveedor,
 		Observaciones:   req.Observaciones,
 		UsuarioRegistro: req.UsuarioRegistro,
 	}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/etiquetas"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type EtiquetasHandler struct {
+	service application.EtiquetasService
+}
+
+func NewEtiquetasHandler(service application.EtiquetasService) *EtiquetasHandler {
+	return &EtiquetasHandler{service: service}
+}
+
+// GetEtiqueta devuelve el código de barras del producto como imagen SVG (por defecto) o PNG
+func (h *EtiquetasHandler) GetEtiqueta(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var q dto.EtiquetaQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	etiqueta, err := h.service.GetEtiqueta(id, q.Simbologia)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	bc, err := etiquetas.Codificar(etiqueta)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "No se pudo generar el código de barras", Error: err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	tipo := "image/svg+xml"
+	if q.Formato == "png" {
+		tipo = "image/png"
+		err = etiquetas.EscribirPNG(&buf, bc, cmp.Or(q.Ancho, 400), cmp.Or(q.Alto, 120))
+	} else {
+		err = etiquetas.EscribirSVG(&buf, bc, etiqueta.Codigo, 2, cmp.Or(q.Alto, 80))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Error al generar la imagen", Error: err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, tipo, buf.Bytes())
+}
+
+// GetHoja genera una hoja A4 en PDF con las etiquetas de los productos solicitados
+func (h *EtiquetasHandler) GetHoja(c *gin.Context) {
+	var req dto.HojaEtiquetasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	var formato etiquetas.Formato
+	var err error
+	if req.Columnas > 0 || req.Filas > 0 {
+		formato, err = etiquetas.FormatoPersonalizado(req.Columnas, req.Filas)
+	} else {
+		formato, err = etiquetas.BuscarFormato(cmp.Or(req.Formato, "3x8"))
+	}
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	solicitudes := make([]domain.SolicitudEtiqueta, len(req.Productos))
+	for i, p := range req.Productos {
+		solicitudes[i] = domain.SolicitudEtiqueta{IDProducto: p.IDProducto, Copias: p.Copias}
+	}
+	lista, err := h.service.GetEtiquetas(solicitudes, req.Simbologia)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	contenido, err := etiquetas.HojaPDF(lista, formato, req.MostrarPrecio == nil || *req.MostrarPrecio)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	nombre := fmt.Sprintf("etiquetas_%s.pdf", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nombre))
+	c.Data(http.StatusOK, "application/pdf", contenido)
+}
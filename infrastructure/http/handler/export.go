@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
+	"github.com/gin-gonic/gin"
+)
+
+// exportar atiende las solicitudes CSV/XLSX. Devuelve true si la respuesta ya fue
+// enviada; con formato JSON devuelve false y el handler continúa normalmente.
+func exportar(c *gin.Context, construir func() export.Tabla) bool {
+	formato, err := export.Negociar(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Formato inválido",
+			Error:   err.Error(),
+		})
+		return true
+	}
+	if formato == export.JSON {
+		return false
+	}
+	if err := export.Escribir(c, formato, construir()); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Success: false,
+			Message: "Error al generar el archivo",
+			Error:   err.Error(),
+		})
+	}
+	return true
+}
+
+// enviarPDF genera el documento y lo envía como descarga
+func enviarPDF(c *gin.Context, generador *pdf.Generador, doc pdf.Documento) {
+	contenido, err := generador.Generar(doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Success: false,
+			Message: "Error al generar el PDF",
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdf.NombreArchivo(doc)))
+	c.Data(http.StatusOK, "application/pdf", contenido)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/gin-gonic/gin"
+)
+
+// listado traduce los parámetros comunes de paginación y orden. Las exportaciones
+// CSV/XLSX devuelven todos los registros que cumplen el filtro.
+func listado(c *gin.Context, q dto.ListadoQuery) (domain.Paginacion, domain.Orden) {
+	p := domain.Paginacion{Pagina: q.Pagina, Limite: q.Limite}
+	if formato, err := export.Negociar(c); err == nil && formato != export.JSON {
+		p.Todos = true
+	}
+	p.Normalizar()
+	return p, domain.Orden{Campo: q.Orden, Desc: q.Dir == "desc"}
+}
+
+func parametrosInvalidos(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, dto.Response{
+		Success: false,
+		Message: "Parámetros inválidos",
+		Error:   err.Error(),
+	})
+}
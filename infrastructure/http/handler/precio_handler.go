@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type PrecioHandler struct {
+	service application.PrecioService
+}
+
+func NewPrecioHandler(service application.PrecioService) *PrecioHandler {
+	return &PrecioHandler{service: service}
+}
+
+func (h *PrecioHandler) GetHistorial(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	producto, historial, err := h.service.GetHistorial(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreciosProductoResponse{
+		Success:      true,
+		Message:      "Historial de precios obtenido",
+		IDProducto:   producto.ID,
+		PrecioActual: producto.PrecioUnitario,
+		Data:         dto.CambiosPrecioToResponse(historial),
+		TotalCount:   len(historial),
+	})
+}
+
+func (h *PrecioHandler) GetProgramados(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	producto, programados, err := h.service.GetProgramados(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreciosProductoResponse{
+		Success:      true,
+		Message:      "Cambios de precio programados obtenidos",
+		IDProducto:   producto.ID,
+		PrecioActual: producto.PrecioUnitario,
+		Data:         dto.PreciosProgramadosToResponse(programados),
+		TotalCount:   len(programados),
+	})
+}
+
+func (h *PrecioHandler) Programar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.ProgramarPrecioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fechaVigencia, err := time.ParseInLocation("2006-01-02", req.FechaVigencia, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		return
+	}
+	programado, err := h.service.Programar(id, req.PrecioNuevo, fechaVigencia, c.GetString("username"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Cambio de precio programado exitosamente",
+		Data:    dto.PrecioProgramadoToResponse(programado),
+	})
+}
+
+func (h *PrecioHandler) Cancelar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	idProgramado, err := strconv.Atoi(c.Param("idProgramado"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	if err := h.service.Cancelar(id, idProgramado); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Cambio de precio cancelado exitosamente"})
+}
+
+// GetPrecioEnFecha retorna el precio vigente en ?fecha=YYYY-MM-DD (por defecto hoy)
+func (h *PrecioHandler) GetPrecioEnFecha(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	fecha := time.Now()
+	if f := c.Query("fecha"); f != "" {
+		if fecha, err = time.ParseInLocation("2006-01-02", f, time.Local); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+			return
+		}
+	}
+	producto, precio, err := h.service.GetPrecioEnFecha(id, fecha)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Precio vigente obtenido",
+		Data: dto.PrecioVigenteResponse{
+			IDProducto: producto.ID,
+			Codigo:     producto.Codigo,
+			Nombre:     producto.Nombre,
+			Fecha:      fecha.Format("2006-01-02"),
+			Precio:     precio,
+		},
+	})
+}
+
+// ActualizarMasivo cambia el precio de varios productos en una sola transacción; con
+// ?simular=true solo muestra los precios anteriores y nuevos
+func (h *PrecioHandler) ActualizarMasivo(c *gin.Context) {
+	var req dto.ActualizacionMasivaPreciosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	simular, _ := strconv.ParseBool(c.Query("simular"))
+	cambios, err := h.service.ActualizarMasivo(&domain.ActualizacionMasivaPrecios{
+		Tipo:         req.Tipo,
+		Valor:        req.Valor,
+		Redondeo:     req.Redondeo,
+		IDCategoria:  req.IDCategoria,
+		Proveedor:    req.Proveedor,
+		IDsProductos: req.IDsProductos,
+		Usuario:      c.GetString("username"),
+		Motivo:       req.Motivo,
+	}, simular)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	mensaje := "Precios actualizados exitosamente"
+	if simular {
+		mensaje = "Vista previa de la actualización: no se aplicaron cambios"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: mensaje,
+		Data:    dto.ActualizacionMasivaToResponse(cambios, simular),
+	})
+}
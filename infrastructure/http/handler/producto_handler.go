@@ -7,6 +7,8 @@ import (
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/Mishka-GDI-Back/infrastructure/importacion"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,16 +21,29 @@ func NewProductoHandler(service application.ProductoService) *ProductoHandler {
 }
 
 func (h *ProductoHandler) GetAll(c *gin.Context) {
-	productos, err := h.service.GetAll()
+	var q dto.ListadoProductosQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	filtro := domain.FiltroProductos{IDCategoria: q.IDCategoria, ClaseABC: q.ClaseABC, IDProductoPadre: q.IDProductoPadre, IncluirInactivos: q.IncluirInactivos}
+	filtro.Paginacion, filtro.Orden = listado(c, q.ListadoQuery)
+	productos, total, err := h.service.GetAll(filtro)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.ProductosTabla(productos) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ProductosResponse{
-		Success:    true,
-		Message:    "Productos obtenidos exitosamente",
-		Data:       dto.ProductosToResponse(productos),
-		TotalCount: len(productos),
+		Success:      true,
+		Message:      "Productos obtenidos exitosamente",
+		Data:         dto.ProductosToResponse(productos),
+		TotalCount:   total,
+		Pagina:       filtro.Pagina,
+		Limite:       filtro.Limite,
+		TotalPaginas: filtro.TotalPaginas(total),
 	})
 }
 
@@ -64,6 +79,9 @@ func (h *ProductoHandler) Create(c *gin.Context) {
 		PrecioUnitario: req.PrecioUnitario,
 		StockActual:    req.StockActual,
 		StockInicial:   req.StockInicial,
+		Tipo:           req.Tipo,
+		AfectacionIGV:  req.AfectacionIGV,
+		TasaIGV:        req.TasaIGV,
 	}
 	result, err := h.service.Create(producto)
 	if err != nil {
@@ -96,8 +114,11 @@ func (h *ProductoHandler) Update(c *gin.Context) {
 		PrecioUnitario: req.PrecioUnitario,
 		StockActual:    req.StockActual,
 		StockInicial:   req.StockInicial,
+		Atributos:      req.Atributos,
+		AfectacionIGV:  req.AfectacionIGV,
+		TasaIGV:        req.TasaIGV,
 	}
-	result, err := h.service.Update(id, producto)
+	result, err := h.service.Update(id, producto, c.GetString("username"))
 	if err != nil {
 		handleDomainError(c, err)
 		return
@@ -119,7 +140,130 @@ func (h *ProductoHandler) Delete(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Producto eliminado exitosamente"})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Producto dado de baja exitosamente"})
+}
+
+func (h *ProductoHandler) Restaurar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	producto, err := h.service.Restaurar(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Producto restaurado exitosamente",
+		Data:    dto.ProductoToResponse(producto),
+	})
+}
+
+func (h *ProductoHandler) GetVariantes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	padre, variantes, err := h.service.GetVariantes(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	stockTotal := padre.StockActual
+	for _, v := range variantes {
+		stockTotal += v.StockActual
+	}
+	c.JSON(http.StatusOK, dto.VariantesResponse{
+		Success:    true,
+		Message:    "Variantes obtenidas exitosamente",
+		Padre:      dto.ProductoToResponse(padre),
+		Data:       dto.ProductosToResponse(variantes),
+		StockTotal: stockTotal,
+		TotalCount: len(variantes),
+	})
+}
+
+func (h *ProductoHandler) CreateVariante(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.CreateVarianteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	variante := &domain.Producto{
+		Codigo:         req.Codigo,
+		Nombre:         req.Nombre,
+		Atributos:      req.Atributos,
+		PrecioUnitario: req.PrecioUnitario,
+		StockActual:    req.StockActual,
+		StockInicial:   req.StockInicial,
+	}
+	result, err := h.service.CreateVariante(id, variante)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Variante creada exitosamente",
+		Data:    dto.ProductoToResponse(result),
+	})
+}
+
+func (h *ProductoHandler) GetComponentes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	kit, componentes, err := h.service.GetComponentes(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ComponentesKitResponse{
+		Success:    true,
+		Message:    "Componentes del kit obtenidos",
+		Kit:        dto.ProductoToResponse(kit),
+		Data:       dto.ComponentesKitToResponse(componentes),
+		Disponible: domain.DisponibilidadKit(componentes),
+	})
+}
+
+func (h *ProductoHandler) SetComponentes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.SetComponentesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	componentes := make([]domain.ComponenteKit, len(req.Componentes))
+	for i, comp := range req.Componentes {
+		componentes[i] = domain.ComponenteKit{IDKit: id, IDComponente: comp.IDProducto, Cantidad: comp.Cantidad}
+	}
+	kit, componentes, err := h.service.SetComponentes(id, componentes)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ComponentesKitResponse{
+		Success:    true,
+		Message:    "Componentes del kit actualizados",
+		Kit:        dto.ProductoToResponse(kit),
+		Data:       dto.ComponentesKitToResponse(componentes),
+		Disponible: domain.DisponibilidadKit(componentes),
+	})
 }
 
 func (h *ProductoHandler) GetStockBajo(c *gin.Context) {
@@ -129,6 +273,9 @@ func (h *ProductoHandler) GetStockBajo(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.ProductosTabla(productos) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ProductosResponse{
 		Success:    true,
 		Message:    "Productos con stock bajo",
@@ -139,11 +286,16 @@ func (h *ProductoHandler) GetStockBajo(c *gin.Context) {
 
 func (h *ProductoHandler) Search(c *gin.Context) {
 	termino := c.Query("q")
-	productos, err := h.service.Search(termino)
+	limite, _ := strconv.Atoi(c.DefaultQuery("limite", "20"))
+	incluirInactivos, _ := strconv.ParseBool(c.Query("incluir_inactivos"))
+	productos, err := h.service.Search(termino, limite, incluirInactivos)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.ProductosTabla(productos) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.ProductosResponse{
 		Success:    true,
 		Message:    "Resultados de búsqueda",
@@ -151,3 +303,116 @@ func (h *ProductoHandler) Search(c *gin.Context) {
 		TotalCount: len(productos),
 	})
 }
+
+func (h *ProductoHandler) GetByCodigoBarras(c *gin.Context) {
+	producto, err := h.service.GetByCodigoBarras(c.Param("code"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Producto encontrado",
+		Data:    dto.ProductoEscaneoToResponse(producto),
+	})
+}
+
+func (h *ProductoHandler) GetCodigosBarras(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	codigos, err := h.service.GetCodigosBarras(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Códigos de barras del producto",
+		Data:    dto.CodigosBarrasToResponse(codigos),
+	})
+}
+
+func (h *ProductoHandler) AgregarCodigoBarras(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.CreateCodigoBarrasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	codigo, err := h.service.AgregarCodigoBarras(id, req.Codigo, req.Tipo)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Código de barras agregado exitosamente",
+		Data:    dto.CodigoBarrasToResponse(codigo),
+	})
+}
+
+func (h *ProductoHandler) EliminarCodigoBarras(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	idCodigo, err := strconv.Atoi(c.Param("idCodigo"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	if err := h.service.EliminarCodigoBarras(id, idCodigo); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Código de barras eliminado exitosamente"})
+}
+
+// Importar crea o actualiza productos desde un CSV o XLSX enviado en el campo "archivo".
+// Con ?simular=true solo valida y muestra qué haría con cada fila. Si alguna fila tiene
+// errores no se importa nada.
+func (h *ProductoHandler) Importar(c *gin.Context) {
+	contenido, nombre, ok := leerArchivoSubido(c, domain.TamanoMaximoAdjunto)
+	if !ok {
+		return
+	}
+	filas, err := importacion.LeerFilas(nombre, contenido)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	simular, _ := strconv.ParseBool(c.Query("simular"))
+	resultado, err := h.service.Importar(filas, simular, c.GetString("username"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	switch {
+	case resultado.ConErrores > 0:
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "El archivo tiene filas con errores; no se importó nada",
+			Data:    dto.ImportacionToResponse(resultado),
+		})
+	case simular:
+		c.JSON(http.StatusOK, dto.Response{
+			Success: true,
+			Message: "Simulación de importación: no se aplicaron cambios",
+			Data:    dto.ImportacionToResponse(resultado),
+		})
+	default:
+		c.JSON(http.StatusOK, dto.Response{
+			Success: true,
+			Message: "Productos importados exitosamente",
+			Data:    dto.ImportacionToResponse(resultado),
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type PromocionHandler struct {
+	service application.PromocionService
+}
+
+func NewPromocionHandler(service application.PromocionService) *PromocionHandler {
+	return &PromocionHandler{service: service}
+}
+
+// GetAll lista las promociones; ?activas=true omite las desactivadas
+func (h *PromocionHandler) GetAll(c *gin.Context) {
+	soloActivas, _ := strconv.ParseBool(c.Query("activas"))
+	promociones, err := h.service.GetAll(soloActivas)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Promociones obtenidas exitosamente",
+		Data:    dto.PromocionesToResponse(promociones),
+	})
+}
+
+func (h *PromocionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	promocion, err := h.service.GetByID(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Promoción encontrada",
+		Data:    dto.PromocionToResponse(promocion),
+	})
+}
+
+func (h *PromocionHandler) Create(c *gin.Context) {
+	promocion, ok := bindPromocion(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.Create(promocion)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Promoción creada exitosamente",
+		Data:    dto.PromocionToResponse(result),
+	})
+}
+
+func (h *PromocionHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	promocion, ok := bindPromocion(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.Update(id, promocion)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Promoción actualizada exitosamente",
+		Data:    dto.PromocionToResponse(result),
+	})
+}
+
+func (h *PromocionHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	if err := h.service.Desactivar(id); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Promoción desactivada exitosamente"})
+}
+
+// bindPromocion lee el cuerpo y convierte las fechas; si falla ya respondió 400
+func bindPromocion(c *gin.Context) (*domain.Promocion, bool) {
+	var req dto.PromocionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return nil, false
+	}
+	promocion := &domain.Promocion{
+		Nombre:        req.Nombre,
+		Tipo:          req.Tipo,
+		IDProducto:    req.IDProducto,
+		IDCategoria:   req.IDCategoria,
+		Valor:         req.Valor,
+		CantidadLleva: req.CantidadLleva,
+		CantidadPaga:  req.CantidadPaga,
+		HoraInicio:    req.HoraInicio,
+		HoraFin:       req.HoraFin,
+		DiasSemana:    req.DiasSemana,
+		LugarVenta:    req.LugarVenta,
+		Activa:        req.Activa == nil || *req.Activa,
+	}
+	for _, f := range []struct {
+		valor   string
+		destino **time.Time
+	}{{req.FechaInicio, &promocion.FechaInicio}, {req.FechaFin, &promocion.FechaFin}} {
+		if f.valor == "" {
+			continue
+		}
+		fecha, err := time.Parse("2006-01-02", f.valor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+			return nil, false
+		}
+		*f.destino = &fecha
+	}
+	return promocion, true
+}
@@ -1,28 +1,37 @@
 package handler
 
 import (
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
 	"github.com/gin-gonic/gin"
 )
 
 type ReportesHandler struct {
 	service application.ReportesService
+	pdf     *pdf.Generador
 }
 
-func NewReportesHandler(service application.ReportesService) *ReportesHandler {
-	return &ReportesHandler{service: service}
+func NewReportesHandler(service application.ReportesService, generador *pdf.Generador) *ReportesHandler {
+	return &ReportesHandler{service: service, pdf: generador}
 }
 
 func (h *ReportesHandler) GetInventarioActual(c *gin.Context) {
-	items, err := h.service.GetInventarioActual()
+	items, err := h.service.GetInventarioActual(c.Query("unidad"))
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.InventarioTabla(items) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Inventario actual",
@@ -38,6 +47,9 @@ func (h *ReportesHandler) GetMovimientos(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.MovimientosTabla(items) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Movimientos de " + inicio + " a " + fin,
@@ -45,13 +57,38 @@ func (h *ReportesHandler) GetMovimientos(c *gin.Context) {
 	})
 }
 
+func (h *ReportesHandler) GetInventarioActualPDF(c *gin.Context) {
+	items, err := h.service.GetInventarioActual(c.Query("unidad"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	enviarPDF(c, h.pdf, dto.InventarioPDF(items))
+}
+
+func (h *ReportesHandler) GetMovimientosPDF(c *gin.Context) {
+	inicio := c.Param("inicio")
+	fin := c.Param("fin")
+	items, err := h.service.GetMovimientos(inicio, fin)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	enviarPDF(c, h.pdf, dto.MovimientosPDF(inicio, fin, items))
+}
+
 func (h *ReportesHandler) GetProductosMasVendidos(c *gin.Context) {
 	limite, _ := strconv.Atoi(c.DefaultQuery("limite", "10"))
-	items, err := h.service.GetProductosMasVendidos(limite)
+	items, err := h.service.GetProductosMasVendidos(limite, c.Query("unidad"))
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla {
+		return dto.ProductosVendidosTabla("productos_mas_vendidos", "Unidades vendidas", "Ingresos", items)
+	}) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Productos más vendidos",
@@ -61,11 +98,16 @@ func (h *ReportesHandler) GetProductosMasVendidos(c *gin.Context) {
 
 func (h *ReportesHandler) GetProductosMasIngresados(c *gin.Context) {
 	limite, _ := strconv.Atoi(c.DefaultQuery("limite", "10"))
-	items, err := h.service.GetProductosMasIngresados(limite)
+	items, err := h.service.GetProductosMasIngresados(limite, c.Query("unidad"))
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla {
+		return dto.ProductosVendidosTabla("productos_mas_ingresados", "Unidades ingresadas", "Monto ingresado", items)
+	}) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Productos más ingresados",
@@ -73,15 +115,201 @@ func (h *ReportesHandler) GetProductosMasIngresados(c *gin.Context) {
 	})
 }
 
+// GetValoracionInventario valoriza el stock por categoría; ?nivel=N consolida las
+// subcategorías en su ancestro del nivel N (1 = categorías raíz)
 func (h *ReportesHandler) GetValoracionInventario(c *gin.Context) {
-	items, err := h.service.GetValoracionInventario()
+	nivel, _ := strconv.Atoi(c.DefaultQuery("nivel", "0"))
+	items, err := h.service.GetValoracionInventario(nivel)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.ValoracionTabla(items) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Valoración del inventario por categoría",
 		Data:    dto.ReportesValoracionToResponse(items),
 	})
 }
+
+func (h *ReportesHandler) GetCobertura(c *gin.Context) {
+	var params domain.ParametrosCobertura
+	if v := c.Query("ventanas"); v != "" {
+		for _, parte := range strings.Split(v, ",") {
+			dias, err := strconv.Atoi(strings.TrimSpace(parte))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Ventanas inválidas", Error: "Use una lista de días separada por comas, p. ej. 7,30,90"})
+				return
+			}
+			params.Ventanas = append(params.Ventanas, dias)
+		}
+	}
+	params.VentanaBase, _ = strconv.Atoi(c.DefaultQuery("ventana_base", "30"))
+	params.DiasObjetivo, _ = strconv.Atoi(c.DefaultQuery("dias_objetivo", "30"))
+	items, err := h.service.GetCobertura(params)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.CoberturaTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Cobertura de stock por producto",
+		Data:    dto.ReportesCoberturaToResponse(items),
+	})
+}
+
+// umbralesABC lee umbral_a y umbral_b (80 y 95 por defecto). Si alguno no es un
+// número ya respondió al cliente y retorna false.
+func umbralesABC(c *gin.Context) (float64, float64, bool) {
+	umbralA, errA := strconv.ParseFloat(c.DefaultQuery("umbral_a", "80"), 64)
+	umbralB, errB := strconv.ParseFloat(c.DefaultQuery("umbral_b", "95"), 64)
+	// NaN se lee sin error pero no cumple ninguna comparación de la validación
+	if errA != nil || errB != nil || math.IsNaN(umbralA) || math.IsNaN(umbralB) {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Umbrales inválidos", Error: "umbral_a y umbral_b deben ser porcentajes, p. ej. 80 y 95"})
+		return 0, 0, false
+	}
+	return umbralA, umbralB, true
+}
+
+func (h *ReportesHandler) GetClasificacionABC(c *gin.Context) {
+	umbralA, umbralB, ok := umbralesABC(c)
+	if !ok {
+		return
+	}
+	items, err := h.service.GetClasificacionABC(c.Query("inicio"), c.Query("fin"), umbralA, umbralB)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.ABCTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Clasificación ABC de productos",
+		Data:    dto.ReportesABCToResponse(items),
+	})
+}
+
+func (h *ReportesHandler) AplicarClasificacionABC(c *gin.Context) {
+	umbralA, umbralB, ok := umbralesABC(c)
+	if !ok {
+		return
+	}
+	items, err := h.service.AplicarClasificacionABC(c.Query("inicio"), c.Query("fin"), umbralA, umbralB)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Clasificación ABC guardada en los productos",
+		Data:    dto.ReportesABCToResponse(items),
+	})
+}
+
+func (h *ReportesHandler) GetStockInmovilizado(c *gin.Context) {
+	dias, _ := strconv.Atoi(c.DefaultQuery("dias", "90"))
+	items, err := h.service.GetStockInmovilizado(dias)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.StockInmovilizadoTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Stock inmovilizado sin ventas recientes",
+		Data:    dto.ReportesStockInmovilizadoToResponse(items),
+	})
+}
+
+// GetDescuentosPorPromocion muestra cuánto dejó de cobrarse por cada promoción en
+// el rango ?desde&hasta (sin rango, desde siempre)
+func (h *ReportesHandler) GetDescuentosPorPromocion(c *gin.Context) {
+	rango := domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")}
+	items, err := h.service.GetDescuentosPorPromocion(rango)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.PromocionesTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Descuentos por promoción obtenidos",
+		Data:    dto.ReportesPromocionToResponse(items),
+	})
+}
+
+// GetComprasPorProveedor muestra lo comprado y lo devuelto a cada proveedor en ?desde&hasta
+func (h *ReportesHandler) GetComprasPorProveedor(c *gin.Context) {
+	rango := domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")}
+	items, err := h.service.GetComprasPorProveedor(rango)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.ComprasProveedorTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Compras por proveedor obtenidas",
+		Data:    dto.ReportesCompraProveedorToResponse(items),
+	})
+}
+
+// GetVentasPorCategoria totaliza las ventas de ?desde&hasta por categoría, consolidadas
+// en el nivel ?nivel del árbol
+func (h *ReportesHandler) GetVentasPorCategoria(c *gin.Context) {
+	rango := domain.RangoFechas{Desde: c.Query("desde"), Hasta: c.Query("hasta")}
+	nivel, _ := strconv.Atoi(c.DefaultQuery("nivel", "0"))
+	items, err := h.service.GetVentasPorCategoria(rango, nivel)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.VentasCategoriaTabla(items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Ventas por categoría obtenidas",
+		Data:    dto.ReportesVentasCategoriaToResponse(items),
+	})
+}
+
+// GetReporteIGV compara el IGV cobrado en las ventas del mes con el pagado en sus entradas
+func (h *ReportesHandler) GetReporteIGV(c *gin.Context) {
+	mes, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Mes inválido"})
+		return
+	}
+	anio, err := strconv.Atoi(c.Param("anio"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año inválido"})
+		return
+	}
+	reporte, err := h.service.GetReporteIGV(mes, anio)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.IGVTabla(reporte) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Reporte de IGV obtenido",
+		Data:    dto.ReporteIGVToResponse(reporte),
+	})
+}
@@ -1,21 +1,26 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/Mishka-GDI-Back/infrastructure/pdf"
 	"github.com/gin-gonic/gin"
 )
 
 type ResumenMensualHandler struct {
 	service application.ResumenMensualService
+	pdf     *pdf.Generador
 }
 
-func NewResumenMensualHandler(service application.ResumenMensualService) *ResumenMensualHandler {
-	return &ResumenMensualHandler{service: service}
+func NewResumenMensualHandler(service application.ResumenMensualService, generador *pdf.Generador) *ResumenMensualHandler {
+	return &ResumenMensualHandler{service: service, pdf: generador}
 }
 
 func (h *ResumenMensualHandler) GetByMesAnio(c *gin.Context) {
@@ -34,6 +39,11 @@ func (h *ResumenMensualHandler) GetByMesAnio(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla {
+		return dto.ResumenesMensualesTabla("resumen_mensual", []domain.ResumenMensual{*resumen})
+	}) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Resumen mensual obtenido",
@@ -41,12 +51,41 @@ func (h *ResumenMensualHandler) GetByMesAnio(c *gin.Context) {
 	})
 }
 
+func (h *ResumenMensualHandler) GetByMesAnioPDF(c *gin.Context) {
+	mes, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Mes inválido"})
+		return
+	}
+	anio, err := strconv.Atoi(c.Param("anio"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año inválido"})
+		return
+	}
+	resumen, err := h.service.GetByMesAnio(mes, anio)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	items, err := h.service.GetMatrizMovimientos(mes, anio, nil)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	enviarPDF(c, h.pdf, dto.ResumenMensualPDF(resumen, items))
+}
+
 func (h *ResumenMensualHandler) GetActual(c *gin.Context) {
 	resumen, err := h.service.GetActual()
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla {
+		return dto.ResumenesMensualesTabla("resumen_mensual", []domain.ResumenMensual{*resumen})
+	}) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Resumen mensual actual obtenido",
@@ -68,6 +107,9 @@ func (h *ResumenMensualHandler) GetByProductoID(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.ResumenProductoTabla(resumenProducto) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
 		Message: "Resumen del producto obtenido",
@@ -110,3 +152,112 @@ func (h *ResumenMensualHandler) Generar(c *gin.Context) {
 		Data:    dto.ResumenMensualToResponse(resumen),
 	})
 }
+
+func (h *ResumenMensualHandler) GetResumenAnual(c *gin.Context) {
+	anio, err := strconv.Atoi(c.Param("anio"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año inválido"})
+		return
+	}
+	resumen, err := h.service.GetResumenAnual(anio)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla {
+		return dto.ResumenesMensualesTabla(fmt.Sprintf("resumen_anual_%d", anio), resumen.Meses)
+	}) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Resumen anual obtenido",
+		Data:    dto.ResumenAnualToResponse(resumen),
+	})
+}
+
+func (h *ResumenMensualHandler) CompararMes(c *gin.Context) {
+	mes, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Mes inválido"})
+		return
+	}
+	anioActual := time.Now().Year()
+	hasta, err := strconv.Atoi(c.DefaultQuery("hasta", strconv.Itoa(anioActual)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año 'hasta' inválido"})
+		return
+	}
+	desde, err := strconv.Atoi(c.DefaultQuery("desde", strconv.Itoa(hasta-2)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año 'desde' inválido"})
+		return
+	}
+	comparativo, err := h.service.CompararMes(mes, desde, hasta)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.ComparativoMensualTabla(comparativo) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Comparativo del mes entre años",
+		Data:    dto.ComparativoMensualToResponse(comparativo),
+	})
+}
+
+func (h *ResumenMensualHandler) CompararPeriodos(c *gin.Context) {
+	comparativo, err := h.service.CompararPeriodos(
+		c.Query("inicio"), c.Query("fin"),
+		c.Query("inicio_anterior"), c.Query("fin_anterior"),
+	)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.ComparativoPeriodoTabla(comparativo) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Comparativo entre periodos",
+		Data:    dto.ComparativoPeriodoToResponse(comparativo),
+	})
+}
+
+func (h *ResumenMensualHandler) GetMatrizMovimientos(c *gin.Context) {
+	mes, err := strconv.Atoi(c.Param("mes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Mes inválido"})
+		return
+	}
+	anio, err := strconv.Atoi(c.Param("anio"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "Año inválido"})
+		return
+	}
+	var idCategoria *int
+	if v := c.Query("id_categoria"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Error: "id_categoria inválido"})
+			return
+		}
+		idCategoria = &id
+	}
+	items, err := h.service.GetMatrizMovimientos(mes, anio, idCategoria)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	if exportar(c, func() export.Tabla { return dto.MatrizMovimientosTabla(mes, anio, items) }) {
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Matriz de movimientos del mes",
+		Data:    dto.MatrizMovimientosToResponse(mes, anio, items),
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Mishka-GDI-Back/application"
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,16 +21,37 @@ func NewSalidaHandler(service application.SalidaProductoService) *SalidaHandler
 }
 
 func (h *SalidaHandler) GetAll(c *gin.Context) {
-	salidas, err := h.service.GetAll()
+	var q dto.ListadoSalidasQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		parametrosInvalidos(c, err)
+		return
+	}
+	filtro := domain.FiltroSalidas{
+		RangoFechas: domain.RangoFechas{Desde: q.Desde, Hasta: q.Hasta},
+		IDProducto:  q.IDProducto,
+		IDCategoria: q.IDCategoria,
+		Lugar:       q.Lugar,
+		Usuario:     q.Usuario,
+		TipoPago:    q.TipoPago,
+		IDCliente:   q.IDCliente,
+	}
+	filtro.Paginacion, filtro.Orden = listado(c, q.ListadoQuery)
+	salidas, total, err := h.service.GetAll(filtro)
 	if err != nil {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.SalidasTabla(salidas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.SalidasResponse{
-		Success:    true,
-		Message:    "Salidas obtenidas exitosamente",
-		Data:       dto.SalidasConProductoToResponse(salidas),
-		TotalCount: len(salidas),
+		Success:      true,
+		Message:      "Salidas obtenidas exitosamente",
+		Data:         dto.SalidasConProductoToResponse(salidas),
+		TotalCount:   total,
+		Pagina:       filtro.Pagina,
+		Limite:       filtro.Limite,
+		TotalPaginas: filtro.TotalPaginas(total),
 	})
 }
 
@@ -62,6 +84,9 @@ func (h *SalidaHandler) GetByProductoID(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.SalidasTabla(salidas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.SalidasResponse{
 		Success:    true,
 		Message:    "Salidas del producto obtenidas",
@@ -77,6 +102,9 @@ func (h *SalidaHandler) GetByFecha(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.SalidasTabla(salidas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.SalidasResponse{
 		Success:    true,
 		Message:    "Salidas de la fecha obtenidas",
@@ -92,6 +120,9 @@ func (h *SalidaHandler) GetByLugar(c *gin.Context) {
 		handleDomainError(c, err)
 		return
 	}
+	if exportar(c, func() export.Tabla { return dto.SalidasTabla(salidas) }) {
+		return
+	}
 	c.JSON(http.StatusOK, dto.SalidasResponse{
 		Success:    true,
 		Message:    "Salidas del lugar obtenidas",
@@ -100,25 +131,37 @@ func (h *SalidaHandler) GetByLugar(c *gin.Context) {
 	})
 }
 
+// leerFechaSalida acepta la fecha sola o con la hora de la venta, que se usa para las
+// promociones con horario
+func leerFechaSalida(valor string) (time.Time, error) {
+	if fecha, err := time.ParseInLocation("2006-01-02T15:04", valor, time.Local); err == nil {
+		return fecha, nil
+	}
+	return time.Parse("2006-01-02", valor)
+}
+
 func (h *SalidaHandler) Create(c *gin.Context) {
 	var req dto.CreateSalidaProductoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
 		return
 	}
-	fechaSalida, err := time.Parse("2006-01-02", req.FechaSalida)
+	fechaSalida, err := leerFechaSalida(req.FechaSalida)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD"})
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD o YYYY-MM-DDTHH:MM"})
 		return
 	}
 	salida := &domain.SalidaProducto{
 		IDProducto:      req.IDProducto,
+		CodigoBarras:    req.CodigoBarras,
+		Unidad:          req.Unidad,
 		FechaSalida:     fechaSalida,
 		Cantidad:        req.Cantidad,
 		PrecioVenta:     req.PrecioVenta,
 		Descuento:       req.Descuento,
 		LugarVenta:      req.LugarVenta,
 		TipoPago:        req.TipoPago,
+		IDCliente:       req.IDCliente,
 		Observaciones:   req.Observaciones,
 		UsuarioRegistro: req.UsuarioRegistro,
 	}
@@ -133,3 +176,46 @@ func (h *SalidaHandler) Create(c *gin.Context) {
 		Data:    result,
 	})
 }
+
+// CreateVenta registra una venta de varias líneas con un solo comprobante numerado
+func (h *SalidaHandler) CreateVenta(c *gin.Context) {
+	var req dto.CreateVentaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	fechaSalida, err := leerFechaSalida(req.FechaSalida)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Fecha inválida", Error: "Use el formato YYYY-MM-DD o YYYY-MM-DDTHH:MM"})
+		return
+	}
+	salidas := make([]*domain.SalidaProducto, len(req.Items))
+	for i, item := range req.Items {
+		salidas[i] = &domain.SalidaProducto{
+			IDProducto:    item.IDProducto,
+			CodigoBarras:  item.CodigoBarras,
+			Unidad:        item.Unidad,
+			FechaSalida:   fechaSalida,
+			Cantidad:      item.Cantidad,
+			PrecioVenta:   item.PrecioVenta,
+			Descuento:     item.Descuento,
+			Observaciones: item.Observaciones,
+		}
+	}
+	comprobante := &domain.Comprobante{
+		IDCliente:       req.IDCliente,
+		LugarVenta:      req.LugarVenta,
+		TipoPago:        req.TipoPago,
+		UsuarioRegistro: req.UsuarioRegistro,
+	}
+	detalle, err := h.service.CreateVenta(comprobante, salidas)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Venta registrada exitosamente",
+		Data:    dto.ComprobanteToResponse(detalle),
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Mishka-GDI-Back/application"
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type UnidadMedidaHandler struct {
+	service application.UnidadMedidaService
+}
+
+func NewUnidadMedidaHandler(service application.UnidadMedidaService) *UnidadMedidaHandler {
+	return &UnidadMedidaHandler{service: service}
+}
+
+func (h *UnidadMedidaHandler) GetAll(c *gin.Context) {
+	unidades, err := h.service.GetAll()
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Unidades de medida obtenidas",
+		Data:    dto.UnidadesMedidaToResponse(unidades),
+	})
+}
+
+func (h *UnidadMedidaHandler) Create(c *gin.Context) {
+	var req dto.CreateUnidadMedidaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	unidad, err := h.service.Create(&domain.UnidadMedida{Codigo: req.Codigo, Nombre: req.Nombre})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Unidad de medida creada exitosamente",
+		Data:    dto.UnidadesMedidaToResponse([]domain.UnidadMedida{*unidad})[0],
+	})
+}
+
+func (h *UnidadMedidaHandler) GetConversiones(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	producto, conversiones, err := h.service.GetConversiones(id)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ConversionesResponse{
+		Success:    true,
+		Message:    "Unidades del producto obtenidas",
+		IDProducto: producto.ID,
+		UnidadBase: producto.UnidadMedida,
+		Data:       dto.ConversionesUnidadToResponse(conversiones),
+	})
+}
+
+func (h *UnidadMedidaHandler) SetConversion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	var req dto.SetConversionUnidadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "Datos inválidos", Error: err.Error()})
+		return
+	}
+	conversion, err := h.service.SetConversion(id, c.Param("unidad"), req.Factor)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Conversión guardada exitosamente",
+		Data:    dto.ConversionesUnidadToResponse([]domain.ConversionUnidad{*conversion})[0],
+	})
+}
+
+func (h *UnidadMedidaHandler) DeleteConversion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: "ID inválido", Error: "El ID debe ser un número entero"})
+		return
+	}
+	if err := h.service.DeleteConversion(id, c.Param("unidad")); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Conversión eliminada exitosamente"})
+}
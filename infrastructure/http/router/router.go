@@ -7,15 +7,24 @@ import (
 )
 
 type Router struct {
-	categoriaHandler *handler.CategoriaHandler
-	productoHandler  *handler.ProductoHandler
-	entradaHandler   *handler.EntradaHandler
-	salidaHandler    *handler.SalidaHandler
-	controlHandler   *handler.ControlDiarioHandler
-	resumenHandler   *handler.ResumenMensualHandler
-	authHandler      *handler.AuthHandler
-	reportesHandler  *handler.ReportesHandler
-	alertasHandler   *handler.AlertasHandler
+	categoriaHandler   *handler.CategoriaHandler
+	productoHandler    *handler.ProductoHandler
+	entradaHandler     *handler.EntradaHandler
+	salidaHandler      *handler.SalidaHandler
+	controlHandler     *handler.ControlDiarioHandler
+	resumenHandler     *handler.ResumenMensualHandler
+	authHandler        *handler.AuthHandler
+	reportesHandler    *handler.ReportesHandler
+	alertasHandler     *handler.AlertasHandler
+	etiquetasHandler   *handler.EtiquetasHandler
+	unidadHandler      *handler.UnidadMedidaHandler
+	precioHandler      *handler.PrecioHandler
+	promocionHandler   *handler.PromocionHandler
+	comprobanteHandler *handler.ComprobanteHandler
+	clienteHandler     *handler.ClienteHandler
+	devolucionHandler  *handler.DevolucionHandler
+	devProvHandler     *handler.DevolucionProveedorHandler
+	archivoHandler     *handler.ArchivoHandler
 }
 
 func NewRouter(
@@ -28,17 +37,35 @@ func NewRouter(
 	authHandler *handler.AuthHandler,
 	reportesHandler *handler.ReportesHandler,
 	alertasHandler *handler.AlertasHandler,
+	etiquetasHandler *handler.EtiquetasHandler,
+	unidadHandler *handler.UnidadMedidaHandler,
+	precioHandler *handler.PrecioHandler,
+	promocionHandler *handler.PromocionHandler,
+	comprobanteHandler *handler.ComprobanteHandler,
+	clienteHandler *handler.ClienteHandler,
+	devolucionHandler *handler.DevolucionHandler,
+	devProvHandler *handler.DevolucionProveedorHandler,
+	archivoHandler *handler.ArchivoHandler,
 ) *Router {
 	return &Router{
-		categoriaHandler: categoriaHandler,
-		productoHandler:  productoHandler,
-		entradaHandler:   entradaHandler,
-		salidaHandler:    salidaHandler,
-		controlHandler:   controlHandler,
-		resumenHandler:   resumenHandler,
-		authHandler:      authHandler,
-		reportesHandler:  reportesHandler,
-		alertasHandler:   alertasHandler,
+		categoriaHandler:   categoriaHandler,
+		productoHandler:    productoHandler,
+		entradaHandler:     entradaHandler,
+		salidaHandler:      salidaHandler,
+		controlHandler:     controlHandler,
+		resumenHandler:     resumenHandler,
+		authHandler:        authHandler,
+		reportesHandler:    reportesHandler,
+		alertasHandler:     alertasHandler,
+		etiquetasHandler:   etiquetasHandler,
+		unidadHandler:      unidadHandler,
+		precioHandler:      precioHandler,
+		promocionHandler:   promocionHandler,
+		comprobanteHandler: comprobanteHandler,
+		clienteHandler:     clienteHandler,
+		devolucionHandler:  devolucionHandler,
+		devProvHandler:     devProvHandler,
+		archivoHandler:     archivoHandler,
 	}
 }
 
@@ -75,10 +102,12 @@ func (r *Router) SetupRoutes() *gin.Engine {
 			categorias := protected.Group("categorias")
 			{
 				categorias.GET("", r.categoriaHandler.GetAll)
+				categorias.GET("/arbol", r.categoriaHandler.GetArbol)
 				categorias.GET("/:id", r.categoriaHandler.GetByID)
 				categorias.POST("", r.categoriaHandler.Create)
 				categorias.PUT("/:id", r.categoriaHandler.Update)
 				categorias.DELETE("/:id", r.categoriaHandler.Delete)
+				categorias.POST("/:id/restaurar", r.categoriaHandler.Restaurar)
 			}
 
 			// Productos
@@ -87,10 +116,63 @@ func (r *Router) SetupRoutes() *gin.Engine {
 				productos.GET("", r.productoHandler.GetAll)
 				productos.GET("/stock-bajo", r.productoHandler.GetStockBajo)
 				productos.GET("/buscar", r.productoHandler.Search)
+				productos.GET("/barcode/:code", r.productoHandler.GetByCodigoBarras)
+				productos.POST("/importar", r.productoHandler.Importar)
+				productos.POST("/actualizar-precios", r.precioHandler.ActualizarMasivo)
 				productos.GET("/:id", r.productoHandler.GetByID)
 				productos.POST("", r.productoHandler.Create)
 				productos.PUT("/:id", r.productoHandler.Update)
 				productos.DELETE("/:id", r.productoHandler.Delete)
+				productos.POST("/:id/restaurar", r.productoHandler.Restaurar)
+				productos.GET("/:id/codigos-barras", r.productoHandler.GetCodigosBarras)
+				productos.POST("/:id/codigos-barras", r.productoHandler.AgregarCodigoBarras)
+				productos.DELETE("/:id/codigos-barras/:idCodigo", r.productoHandler.EliminarCodigoBarras)
+				productos.GET("/:id/variantes", r.productoHandler.GetVariantes)
+				productos.POST("/:id/variantes", r.productoHandler.CreateVariante)
+				productos.GET("/:id/componentes", r.productoHandler.GetComponentes)
+				productos.PUT("/:id/componentes", r.productoHandler.SetComponentes)
+				productos.GET("/:id/precio", r.precioHandler.GetPrecioEnFecha)
+				productos.GET("/:id/historial-precios", r.precioHandler.GetHistorial)
+				productos.GET("/:id/precios-programados", r.precioHandler.GetProgramados)
+				productos.POST("/:id/precios-programados", r.precioHandler.Programar)
+				productos.DELETE("/:id/precios-programados/:idProgramado", r.precioHandler.Cancelar)
+				productos.GET("/:id/unidades", r.unidadHandler.GetConversiones)
+				productos.PUT("/:id/unidades/:unidad", r.unidadHandler.SetConversion)
+				productos.DELETE("/:id/unidades/:unidad", r.unidadHandler.DeleteConversion)
+				productos.GET("/:id/etiqueta", r.etiquetasHandler.GetEtiqueta)
+				productos.GET("/:id/imagenes", r.archivoHandler.GetImagenes)
+				productos.POST("/:id/imagenes", r.archivoHandler.SubirImagen)
+				productos.DELETE("/:id/imagenes/:idArchivo", r.archivoHandler.EliminarImagen)
+			}
+
+			// Archivos (imágenes y adjuntos)
+			archivos := protected.Group("archivos")
+			{
+				archivos.GET("/:id", r.archivoHandler.Descargar)
+				archivos.GET("/:id/miniatura", r.archivoHandler.Miniatura)
+			}
+
+			// Promociones
+			promociones := protected.Group("promociones")
+			{
+				promociones.GET("", r.promocionHandler.GetAll)
+				promociones.GET("/:id", r.promocionHandler.GetByID)
+				promociones.POST("", r.promocionHandler.Create)
+				promociones.PUT("/:id", r.promocionHandler.Update)
+				promociones.DELETE("/:id", r.promocionHandler.Delete)
+			}
+
+			// Unidades de medida
+			unidades := protected.Group("unidades-medida")
+			{
+				unidades.GET("", r.unidadHandler.GetAll)
+				unidades.POST("", r.unidadHandler.Create)
+			}
+
+			// Etiquetas
+			etiquetas := protected.Group("etiquetas")
+			{
+				etiquetas.POST("/hoja", r.etiquetasHandler.GetHoja)
 			}
 
 			// Entradas
@@ -100,9 +182,22 @@ func (r *Router) SetupRoutes() *gin.Engine {
 				entradas.GET("/producto/:id", r.entradaHandler.GetByProductoID)
 				entradas.GET("/fecha/:fecha", r.entradaHandler.GetByFecha)
 				entradas.GET("/:id", r.entradaHandler.GetByID)
+				entradas.GET("/:id/devoluciones", r.devProvHandler.GetByEntrada)
+				entradas.GET("/:id/adjuntos", r.archivoHandler.GetAdjuntosEntrada)
+				entradas.POST("/:id/adjuntos", r.archivoHandler.SubirAdjuntoEntrada)
+				entradas.DELETE("/:id/adjuntos/:idArchivo", r.archivoHandler.EliminarAdjuntoEntrada)
 				entradas.POST("", r.entradaHandler.Create)
 			}
 
+			// Devoluciones a proveedores
+			devolucionesProveedor := protected.Group("devoluciones-proveedor")
+			{
+				devolucionesProveedor.GET("", r.devProvHandler.GetAll)
+				devolucionesProveedor.GET("/:id", r.devProvHandler.GetByID)
+				devolucionesProveedor.POST("", r.devProvHandler.Create)
+				devolucionesProveedor.POST("/:id/liquidar", r.devProvHandler.Liquidar)
+			}
+
 			// Salidas
 			salidas := protected.Group("salidas")
 			{
@@ -111,9 +206,53 @@ func (r *Router) SetupRoutes() *gin.Engine {
 				salidas.GET("/fecha/:fecha", r.salidaHandler.GetByFecha)
 				salidas.GET("/lugar/:lugar", r.salidaHandler.GetByLugar)
 				salidas.GET("/:id", r.salidaHandler.GetByID)
+				salidas.GET("/:id/devoluciones", r.devolucionHandler.GetBySalida)
 				salidas.POST("", r.salidaHandler.Create)
 			}
 
+			// Devoluciones de clientes
+			devoluciones := protected.Group("devoluciones")
+			{
+				devoluciones.GET("", r.devolucionHandler.GetAll)
+				devoluciones.GET("/:id", r.devolucionHandler.GetByID)
+				devoluciones.POST("", r.devolucionHandler.Create)
+			}
+
+			// Clientes y cuentas por cobrar
+			clientes := protected.Group("clientes")
+			{
+				clientes.GET("", r.clienteHandler.GetAll)
+				clientes.GET("/:id", r.clienteHandler.GetByID)
+				clientes.POST("", r.clienteHandler.Create)
+				clientes.PUT("/:id", r.clienteHandler.Update)
+				clientes.GET("/:id/cuentas", r.clienteHandler.GetCuentas)
+				clientes.POST("/:id/pagos", r.clienteHandler.RegistrarPago)
+				clientes.GET("/:id/estado-cuenta", r.clienteHandler.GetEstadoCuenta)
+			}
+
+			// Ventas con comprobante
+			ventas := protected.Group("ventas")
+			{
+				ventas.POST("", r.salidaHandler.CreateVenta)
+			}
+			comprobantes := protected.Group("comprobantes")
+			{
+				comprobantes.GET("/:id", r.comprobanteHandler.GetByID)
+				comprobantes.GET("/:id/pdf", r.comprobanteHandler.GetPDF)
+				comprobantes.GET("/:id/escpos", r.comprobanteHandler.GetESCPOS)
+			}
+			series := protected.Group("series-comprobante")
+			{
+				series.GET("", r.comprobanteHandler.GetSeries)
+				series.POST("", r.comprobanteHandler.CreateSerie)
+				series.PUT("/:id", r.comprobanteHandler.UpdateSerie)
+			}
+			configuracion := protected.Group("configuracion")
+			{
+				configuracion.GET("", r.comprobanteHandler.GetConfiguracion)
+				configuracion.PUT("", r.comprobanteHandler.UpdateConfiguracion)
+			}
+
 			// Control Diario
 			control := protected.Group("control-diario")
 			{
@@ -121,8 +260,12 @@ func (r *Router) SetupRoutes() *gin.Engine {
 				control.GET("/hoy", r.controlHandler.GetHoy)
 				control.GET("/verbena", r.controlHandler.GetVerbena)
 				control.GET("/fecha/:fecha", r.controlHandler.GetByFecha)
+				control.GET("/fecha/:fecha/pdf", r.controlHandler.GetByFechaPDF)
 				control.POST("", r.controlHandler.Create)
 				control.POST("/generar/:fecha", r.controlHandler.GenerarDesdeVentas)
+				control.GET("/:id/adjuntos", r.archivoHandler.GetAdjuntosControl)
+				control.POST("/:id/adjuntos", r.archivoHandler.SubirAdjuntoControl)
+				control.DELETE("/:id/adjuntos/:idArchivo", r.archivoHandler.EliminarAdjuntoControl)
 			}
 
 			// Resumen Mensual
@@ -130,18 +273,39 @@ func (r *Router) SetupRoutes() *gin.Engine {
 			{
 				resumen.GET("/actual", r.resumenHandler.GetActual)
 				resumen.GET("/producto/:id", r.resumenHandler.GetByProductoID)
+				resumen.GET("/productos/:mes/:anio", r.resumenHandler.GetMatrizMovimientos)
 				resumen.GET("/:mes/:anio", r.resumenHandler.GetByMesAnio)
+				resumen.GET("/:mes/:anio/pdf", r.resumenHandler.GetByMesAnioPDF)
 				resumen.POST("/generar", r.resumenHandler.Generar)
 			}
 
+			// Resumen Anual y Comparativos
+			resumenAnual := protected.Group("resumen-anual")
+			{
+				resumenAnual.GET("/comparativo/mes/:mes", r.resumenHandler.CompararMes)
+				resumenAnual.GET("/comparativo/periodo", r.resumenHandler.CompararPeriodos)
+				resumenAnual.GET("/:anio", r.resumenHandler.GetResumenAnual)
+			}
+
 			// Reportes
 			reportes := protected.Group("reportes")
 			{
 				reportes.GET("/inventario-actual", r.reportesHandler.GetInventarioActual)
+				reportes.GET("/inventario-actual/pdf", r.reportesHandler.GetInventarioActualPDF)
 				reportes.GET("/movimientos/:inicio/:fin", r.reportesHandler.GetMovimientos)
+				reportes.GET("/movimientos/:inicio/:fin/pdf", r.reportesHandler.GetMovimientosPDF)
 				reportes.GET("/productos-mas-vendidos", r.reportesHandler.GetProductosMasVendidos)
 				reportes.GET("/productos-mas-ingresados", r.reportesHandler.GetProductosMasIngresados)
 				reportes.GET("/valoracion-inventario", r.reportesHandler.GetValoracionInventario)
+				reportes.GET("/cobertura", r.reportesHandler.GetCobertura)
+				reportes.GET("/abc", r.reportesHandler.GetClasificacionABC)
+				reportes.POST("/abc/aplicar", r.reportesHandler.AplicarClasificacionABC)
+				reportes.GET("/stock-inmovilizado", r.reportesHandler.GetStockInmovilizado)
+				reportes.GET("/promociones", r.reportesHandler.GetDescuentosPorPromocion)
+				reportes.GET("/compras-proveedor", r.reportesHandler.GetComprasPorProveedor)
+				reportes.GET("/ventas-categoria", r.reportesHandler.GetVentasPorCategoria)
+				reportes.GET("/antiguedad-cuentas", r.clienteHandler.GetAntiguedad)
+				reportes.GET("/igv/:mes/:anio", r.reportesHandler.GetReporteIGV)
 			}
 
 			// Alertas
@@ -149,6 +313,7 @@ func (r *Router) SetupRoutes() *gin.Engine {
 			{
 				alertas.GET("", r.alertasHandler.GetAlertasActivas)
 				alertas.GET("/stock-bajo", r.alertasHandler.GetStockBajo)
+				alertas.GET("/quiebre-stock", r.alertasHandler.GetQuiebrePrevisto)
 				alertas.POST("/configuracion", r.alertasHandler.ConfigurarAlerta)
 			}
 		}
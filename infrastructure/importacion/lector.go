@@ -0,0 +1,180 @@
+// Package importacion lee las hojas CSV o XLSX que se suben para importar datos
+package importacion
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// alias son otros títulos aceptados para las columnas
+var alias = map[string]string{
+	"precio":     domain.ColumnaPrecioUnitario,
+	"stock":      domain.ColumnaStockActual,
+	"unidad":     domain.ColumnaUnidadMedida,
+	"afectacion": domain.ColumnaAfectacionIGV,
+	"tasa":       domain.ColumnaTasaIGV,
+}
+
+// bom es la marca que Excel agrega al inicio de los CSV en UTF-8
+const bom = "\ufeff"
+
+const (
+	// limiteDescompresion es el tamaño máximo del contenido descomprimido de un XLSX
+	limiteDescompresion = 100 << 20
+	// limiteHojaEnMemoria es el tamaño de hoja desde el que excelize usa un archivo
+	// temporal en lugar de memoria
+	limiteHojaEnMemoria = 16 << 20
+)
+
+var sinTildes = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ñ", "n")
+
+// LeerFilas lee la primera hoja del archivo. La primera fila son los títulos de las
+// columnas (se aceptan con tildes, mayúsculas o espacios, p. ej. "Categoría" o
+// "Precio unitario"); las filas vacías se omiten. Deja de leer al pasar de
+// MaximoFilasImportacion filas con datos: el servicio rechaza el archivo igual.
+func LeerFilas(nombre string, contenido []byte) ([]domain.FilaImportacion, error) {
+	var columnas []string
+	var filas []domain.FilaImportacion
+	var errEncabezado error
+	numero := 0
+	agregar := func(registro []string) bool {
+		numero++
+		if columnas == nil {
+			columnas, errEncabezado = leerEncabezado(registro)
+			return errEncabezado == nil
+		}
+		valores := make(map[string]string, len(columnas))
+		for j, celda := range registro {
+			celda = strings.TrimSpace(celda)
+			if j < len(columnas) && columnas[j] != "" && celda != "" {
+				valores[columnas[j]] = celda
+			}
+		}
+		if len(valores) > 0 {
+			filas = append(filas, domain.FilaImportacion{Numero: numero, Valores: valores})
+		}
+		return len(filas) <= domain.MaximoFilasImportacion
+	}
+
+	var err error
+	switch {
+	case bytes.HasPrefix(contenido, []byte("PK\x03\x04")):
+		err = leerXLSX(contenido, agregar)
+	case strings.EqualFold(filepath.Ext(nombre), ".xls"):
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "el formato XLS no está soportado; guarde la hoja como XLSX o CSV"}
+	default:
+		err = leerCSV(contenido, agregar)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if errEncabezado != nil {
+		return nil, errEncabezado
+	}
+	if columnas == nil {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "está vacío"}
+	}
+	return filas, nil
+}
+
+func leerEncabezado(titulos []string) ([]string, error) {
+	columnas := make([]string, len(titulos))
+	var desconocidas []string
+	for i, titulo := range titulos {
+		titulo = strings.TrimPrefix(strings.TrimSpace(titulo), bom)
+		if titulo == "" {
+			continue
+		}
+		columna := sinTildes.Replace(strings.ToLower(titulo))
+		columna = strings.Join(strings.FieldsFunc(columna, func(r rune) bool { return r == ' ' || r == '-' || r == '_' }), "_")
+		if a, ok := alias[columna]; ok {
+			columna = a
+		}
+		if !slices.Contains(domain.ColumnasImportacion, columna) {
+			desconocidas = append(desconocidas, titulo)
+			continue
+		}
+		if slices.Contains(columnas, columna) {
+			return nil, &domain.ErrValidation{Field: "archivo", Message: fmt.Sprintf("la columna %q está repetida", titulo)}
+		}
+		columnas[i] = columna
+	}
+	if len(desconocidas) > 0 {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: fmt.Sprintf("columnas desconocidas: %s; use %s", strings.Join(desconocidas, ", "), strings.Join(domain.ColumnasImportacion, ", "))}
+	}
+	if !slices.Contains(columnas, domain.ColumnaCodigo) {
+		return nil, &domain.ErrValidation{Field: "archivo", Message: "falta la columna codigo"}
+	}
+	return columnas, nil
+}
+
+// leerCSV detecta el separador (coma, punto y coma o tabulación) en la primera línea;
+// Excel en español guarda los CSV con punto y coma. Entrega cada registro a 'agregar'
+// hasta que retorne false.
+func leerCSV(contenido []byte, agregar func(registro []string) bool) error {
+	contenido = bytes.TrimPrefix(contenido, []byte(bom))
+	primera, _, _ := bytes.Cut(contenido, []byte("\n"))
+	separador := ','
+	for _, s := range []rune{';', '\t'} {
+		if bytes.Count(primera, []byte(string(s))) > bytes.Count(primera, []byte(string(separador))) {
+			separador = s
+		}
+	}
+	lector := csv.NewReader(bytes.NewReader(contenido))
+	lector.Comma = separador
+	lector.FieldsPerRecord = -1
+	lector.LazyQuotes = true
+	for {
+		registro, err := lector.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &domain.ErrValidation{Field: "archivo", Message: "CSV inválido: " + err.Error()}
+		}
+		if !agregar(registro) {
+			return nil
+		}
+	}
+}
+
+// leerXLSX recorre las filas sin cargar la hoja completa y lee los valores sin el
+// formato de la celda (p. ej. 12.5 y no "S/ 12.50"). Los límites de descompresión
+// evitan que un XLSX pequeño se expanda hasta agotar la memoria.
+func leerXLSX(contenido []byte, agregar func(registro []string) bool) error {
+	libro, err := excelize.OpenReader(bytes.NewReader(contenido), excelize.Options{
+		UnzipSizeLimit:    limiteDescompresion,
+		UnzipXMLSizeLimit: limiteHojaEnMemoria,
+	})
+	if err != nil {
+		return &domain.ErrValidation{Field: "archivo", Message: "no se pudo leer el XLSX: " + err.Error()}
+	}
+	defer libro.Close()
+	hojas := libro.GetSheetList()
+	if len(hojas) == 0 {
+		return &domain.ErrValidation{Field: "archivo", Message: "el XLSX no tiene hojas"}
+	}
+	filas, err := libro.Rows(hojas[0])
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+	for filas.Next() {
+		registro, err := filas.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		if !agregar(registro) {
+			return nil
+		}
+	}
+	return filas.Error()
+}
@@ -0,0 +1,376 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/http/export"
+	"github.com/go-pdf/fpdf"
+)
+
+// FuenteNegocio entrega los datos impresos en la cabecera de cada página; son los
+// mismos de la configuración usada en los comprobantes
+type FuenteNegocio interface {
+	GetNegocio() (*domain.DatosNegocio, error)
+}
+
+// Dato es una línea etiqueta/valor de un bloque de resumen
+type Dato struct {
+	Etiqueta string
+	Valor    string
+}
+
+// Seccion agrupa un bloque de datos y/o una tabla con una fila opcional de totales.
+// Totales debe tener un valor por columna; nil deja la celda vacía.
+type Seccion struct {
+	Titulo  string
+	Datos   []Dato
+	Tabla   *export.Tabla
+	Totales []any
+}
+
+// Documento es un reporte imprimible
+type Documento struct {
+	Nombre     string
+	Titulo     string
+	Subtitulo  string
+	Horizontal bool
+	Secciones  []Seccion
+}
+
+const (
+	margen    = 10.0
+	altoLinea = 5.0
+	altoFila  = 6.0
+	fuente    = "Helvetica"
+)
+
+// Generador arma los PDF con la cabecera del negocio. Usa solo fuentes estándar,
+// sin binarios externos.
+type Generador struct {
+	negocio FuenteNegocio
+}
+
+func NewGenerador(negocio FuenteNegocio) *Generador {
+	return &Generador{negocio: negocio}
+}
+
+// NombreArchivo retorna el nombre de descarga del documento
+func NombreArchivo(doc Documento) string {
+	return fmt.Sprintf("%s_%s.pdf", doc.Nombre, time.Now().Format("20060102"))
+}
+
+// Generar construye el documento completo en memoria para poder reportar errores
+// antes de escribir la respuesta
+func (g *Generador) Generar(doc Documento) ([]byte, error) {
+	negocio, err := g.negocio.GetNegocio()
+	if err != nil {
+		return nil, err
+	}
+	if negocio.Nombre == "" {
+		negocio.Nombre = "Mishka"
+	}
+	orientacion := "P"
+	if doc.Horizontal {
+		orientacion = "L"
+	}
+	p := fpdf.New(orientacion, "mm", "A4", "")
+	p.SetMargins(margen, margen, margen)
+	p.SetAutoPageBreak(true, 15)
+	p.AliasNbPages("")
+	r := &render{pdf: p, tr: p.UnicodeTranslatorFromDescriptor(""), generado: time.Now()}
+
+	p.SetHeaderFunc(func() { r.cabecera(negocio, doc) })
+	p.SetFooterFunc(r.pie)
+	p.AddPage()
+
+	for _, s := range doc.Secciones {
+		r.seccion(s)
+	}
+	var buf bytes.Buffer
+	if err := p.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type render struct {
+	pdf      *fpdf.Fpdf
+	tr       func(string) string
+	generado time.Time
+}
+
+func (r *render) ancho() float64 {
+	w, _ := r.pdf.GetPageSize()
+	return w - 2*margen
+}
+
+func (r *render) cabecera(n *domain.DatosNegocio, doc Documento) {
+	p := r.pdf
+	p.SetFont(fuente, "B", 12)
+	p.CellFormat(0, 6, r.tr(n.Nombre), "", 1, "L", false, 0, "")
+	var lineas []string
+	if n.RUC != "" {
+		lineas = append(lineas, "RUC: "+n.RUC)
+	}
+	if n.Direccion != "" {
+		lineas = append(lineas, n.Direccion)
+	}
+	if n.Telefono != "" {
+		lineas = append(lineas, "Tel.: "+n.Telefono)
+	}
+	p.SetFont(fuente, "", 8)
+	if len(lineas) > 0 {
+		p.CellFormat(0, 4, r.tr(strings.Join(lineas, "  ·  ")), "", 1, "L", false, 0, "")
+	}
+	p.Ln(2)
+	p.SetFont(fuente, "B", 13)
+	p.CellFormat(0, 7, r.tr(doc.Titulo), "", 1, "C", false, 0, "")
+	if doc.Subtitulo != "" {
+		p.SetFont(fuente, "", 9)
+		p.CellFormat(0, 5, r.tr(doc.Subtitulo), "", 1, "C", false, 0, "")
+	}
+	y := p.GetY() + 1
+	p.Line(margen, y, margen+r.ancho(), y)
+	p.SetY(y + 3)
+}
+
+func (r *render) pie() {
+	p := r.pdf
+	p.SetY(-12)
+	p.SetFont(fuente, "I", 7)
+	p.CellFormat(r.ancho()/2, 5, r.tr("Generado el "+r.generado.Format("02/01/2006 15:04")), "", 0, "L", false, 0, "")
+	p.CellFormat(r.ancho()/2, 5, r.tr(fmt.Sprintf("Página %d de {nb}", p.PageNo())), "", 0, "R", false, 0, "")
+}
+
+func (r *render) seccion(s Seccion) {
+	p := r.pdf
+	if s.Titulo != "" {
+		r.saltoSiNoCabe(altoFila * 3)
+		p.SetFont(fuente, "B", 10)
+		p.CellFormat(0, 6, r.tr(s.Titulo), "", 1, "L", false, 0, "")
+	}
+	if len(s.Datos) > 0 {
+		r.datos(s.Datos)
+	}
+	if s.Tabla != nil {
+		r.tabla(*s.Tabla, s.Totales)
+	}
+	p.Ln(4)
+}
+
+func (r *render) datos(datos []Dato) {
+	p := r.pdf
+	anchoEtiqueta := 0.0
+	p.SetFont(fuente, "B", 9)
+	for _, d := range datos {
+		anchoEtiqueta = math.Max(anchoEtiqueta, p.GetStringWidth(r.tr(d.Etiqueta))+4)
+	}
+	for _, d := range datos {
+		r.saltoSiNoCabe(altoLinea)
+		p.SetFont(fuente, "B", 9)
+		p.CellFormat(anchoEtiqueta, altoLinea, r.tr(d.Etiqueta), "", 0, "L", false, 0, "")
+		p.SetFont(fuente, "", 9)
+		p.CellFormat(0, altoLinea, r.tr(d.Valor), "", 1, "L", false, 0, "")
+	}
+	p.Ln(2)
+}
+
+func (r *render) tabla(t export.Tabla, totales []any) {
+	p := r.pdf
+	tamano := 8.0
+	if len(t.Columnas) > 10 {
+		tamano = 7
+	}
+	anchos := r.anchosColumnas(t, totales, tamano)
+
+	r.encabezadoTabla(t.Columnas, anchos, tamano)
+	p.SetFont(fuente, "", tamano)
+	for n, fila := range t.Filas {
+		if r.saltoSiNoCabe(altoFila) {
+			r.encabezadoTabla(t.Columnas, anchos, tamano)
+			p.SetFont(fuente, "", tamano)
+		}
+		// Filas alternadas para facilitar la lectura impresa
+		p.SetFillColor(245, 245, 245)
+		for i, col := range t.Columnas {
+			r.celda(export.ValorCelda(fila, i), col.Tipo, anchos[i], n%2 == 1)
+		}
+		p.Ln(-1)
+	}
+	if len(t.Filas) == 0 {
+		p.SetFont(fuente, "I", tamano)
+		p.CellFormat(sumar(anchos), altoFila, r.tr("Sin registros"), "1", 1, "C", false, 0, "")
+	}
+	if totales != nil {
+		if r.saltoSiNoCabe(altoFila) {
+			r.encabezadoTabla(t.Columnas, anchos, tamano)
+		}
+		p.SetFont(fuente, "B", tamano)
+		p.SetFillColor(217, 225, 242)
+		for i, col := range t.Columnas {
+			r.celda(export.ValorCelda(totales, i), col.Tipo, anchos[i], true)
+		}
+		p.Ln(-1)
+	}
+}
+
+func (r *render) encabezadoTabla(cols []export.Columna, anchos []float64, tamano float64) {
+	p := r.pdf
+	p.SetFont(fuente, "B", tamano)
+	p.SetFillColor(217, 225, 242)
+	lineas := 1
+	partidos := make([][]string, len(cols))
+	for i, col := range cols {
+		partidos[i] = r.partir(col.Titulo, anchos[i]-2)
+		lineas = max(lineas, len(partidos[i]))
+	}
+	alto := float64(lineas) * 4
+	r.saltoSiNoCabe(alto + altoFila)
+	x, y := p.GetXY()
+	for i := range cols {
+		p.Rect(x, y, anchos[i], alto, "FD")
+		for j, linea := range partidos[i] {
+			p.SetXY(x, y+float64(j)*4)
+			p.CellFormat(anchos[i], 4, r.tr(linea), "", 0, "C", false, 0, "")
+		}
+		x += anchos[i]
+	}
+	p.SetXY(margen, y+alto)
+}
+
+func (r *render) celda(v any, tipo export.TipoColumna, ancho float64, relleno bool) {
+	alineacion := "L"
+	switch v.(type) {
+	case int, float64:
+		alineacion = "R"
+	}
+	texto := r.recortar(formatear(v, tipo), ancho-2)
+	r.pdf.CellFormat(ancho, altoFila, r.tr(texto), "1", 0, alineacion, relleno, 0, "")
+}
+
+// anchosColumnas reparte el ancho de la página según el contenido de cada columna
+func (r *render) anchosColumnas(t export.Tabla, totales []any, tamano float64) []float64 {
+	p := r.pdf
+	p.SetFont(fuente, "", tamano)
+	anchos := make([]float64, len(t.Columnas))
+	for i, col := range t.Columnas {
+		anchos[i] = math.Min(p.GetStringWidth(r.tr(col.Titulo)), 22)
+		medir := func(fila []any) {
+			w := p.GetStringWidth(r.tr(formatear(export.ValorCelda(fila, i), col.Tipo)))
+			anchos[i] = math.Max(anchos[i], math.Min(w, 70))
+		}
+		for _, fila := range t.Filas {
+			medir(fila)
+		}
+		if totales != nil {
+			medir(totales)
+		}
+		anchos[i] += 3
+	}
+	factor := r.ancho() / sumar(anchos)
+	for i := range anchos {
+		anchos[i] *= factor
+	}
+	return anchos
+}
+
+// saltoSiNoCabe agrega una página si el alto pedido no entra antes del margen inferior
+func (r *render) saltoSiNoCabe(alto float64) bool {
+	_, h := r.pdf.GetPageSize()
+	if r.pdf.GetY()+alto > h-15 {
+		r.pdf.AddPage()
+		return true
+	}
+	return false
+}
+
+// partir divide un texto en líneas que entran en el ancho indicado
+func (r *render) partir(texto string, ancho float64) []string {
+	var lineas []string
+	actual := ""
+	for _, palabra := range strings.Fields(texto) {
+		candidata := strings.TrimSpace(actual + " " + palabra)
+		if actual != "" && r.pdf.GetStringWidth(r.tr(candidata)) > ancho {
+			lineas = append(lineas, actual)
+			candidata = palabra
+		}
+		actual = candidata
+	}
+	return append(lineas, actual)
+}
+
+func (r *render) recortar(texto string, ancho float64) string {
+	if r.pdf.GetStringWidth(r.tr(texto)) <= ancho {
+		return texto
+	}
+	runas := []rune(texto)
+	for len(runas) > 0 && r.pdf.GetStringWidth(r.tr(string(runas)+"…")) > ancho {
+		runas = runas[:len(runas)-1]
+	}
+	return string(runas) + "…"
+}
+
+// formatear presenta el valor como se lee en un documento impreso
+func formatear(v any, tipo export.TipoColumna) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		if x {
+			return "Sí"
+		}
+		return "No"
+	case int:
+		return Numero(float64(x), 0)
+	case float64:
+		switch tipo {
+		case export.Entero:
+			return Numero(x, 0)
+		case export.Porcentaje:
+			return Numero(x, 2) + " %"
+		default:
+			return Numero(x, 2)
+		}
+	case time.Time:
+		return x.Format("02/01/2006")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// Numero formatea con separador de miles y los decimales indicados
+func Numero(f float64, decimales int) string {
+	s := strconv.FormatFloat(math.Abs(f), 'f', decimales, 64)
+	entero, fraccion, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, d := range entero {
+		if i > 0 && (len(entero)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if fraccion != "" {
+		b.WriteByte('.')
+		b.WriteString(fraccion)
+	}
+	return b.String()
+}
+
+func sumar(v []float64) float64 {
+	total := 0.0
+	for _, x := range v {
+		total += x
+	}
+	return total
+}
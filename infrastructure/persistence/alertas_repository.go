@@ -16,7 +16,7 @@ func NewAlertasRepository(db *database.Database) domain.AlertasRepository {
 }
 
 func (r *alertasRepository) GetStockBajo(limite int) ([]domain.AlertaStockBajo, error) {
-	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre, 'SIN CATEGORIA'), p.stock_actual, p.stock_inicial, p.precio_unitario FROM productos p LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE p.stock_actual <= $1 ORDER BY p.stock_actual ASC, c.nombre, p.nombre`
+	query := `SELECT p.id_producto, p.codigo, p.nombre, p.id_categoria, COALESCE(c.nombre, 'SIN CATEGORIA'), p.stock_actual, p.stock_inicial, p.precio_unitario, p.id_producto_padre, p.atributos FROM productos p LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE p.activo AND p.stock_actual <= $1 AND ` + conStockPropio + ` ORDER BY p.stock_actual ASC, c.nombre, p.nombre`
 	rows, err := r.db.Pool.Query(context.Background(), query, limite)
 	if err != nil {
 		return nil, err
@@ -25,7 +25,7 @@ func (r *alertasRepository) GetStockBajo(limite int) ([]domain.AlertaStockBajo,
 	var items []domain.AlertaStockBajo
 	for rows.Next() {
 		var item domain.AlertaStockBajo
-		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.Categoria, &item.StockActual, &item.StockInicial, &item.PrecioUnitario); err != nil {
+		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.IDCategoria, &item.Categoria, &item.StockActual, &item.StockInicial, &item.PrecioUnitario, &item.IDProductoPadre, &item.Atributos); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
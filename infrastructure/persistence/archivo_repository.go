@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type archivoRepository struct {
+	db *database.Database
+}
+
+func NewArchivoRepository(db *database.Database) domain.ArchivoRepository {
+	return &archivoRepository{db: db}
+}
+
+const archivoSelect = `SELECT id_archivo, entidad, id_entidad, tipo, nombre_original, content_type, tamano, ruta, COALESCE(ruta_miniatura, ''), orden, usuario_registro, fecha_creacion FROM archivos`
+
+func scanArchivo(row interface{ Scan(dest ...any) error }) (domain.Archivo, error) {
+	var a domain.Archivo
+	err := row.Scan(&a.ID, &a.Entidad, &a.IDEntidad, &a.Tipo, &a.NombreOriginal, &a.ContentType, &a.Tamano, &a.Ruta, &a.RutaMiniatura, &a.Orden, &a.UsuarioRegistro, &a.FechaCreacion)
+	return a, err
+}
+
+func (r *archivoRepository) GetByEntidad(entidad string, idEntidad int) ([]domain.Archivo, error) {
+	rows, err := r.db.Pool.Query(context.Background(), archivoSelect+" WHERE entidad = $1 AND id_entidad = $2 ORDER BY orden, id_archivo", entidad, idEntidad)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var archivos []domain.Archivo
+	for rows.Next() {
+		a, err := scanArchivo(rows)
+		if err != nil {
+			return nil, err
+		}
+		archivos = append(archivos, a)
+	}
+	return archivos, rows.Err()
+}
+
+func (r *archivoRepository) GetByEntidades(entidad string, ids []int) (map[int][]domain.Archivo, error) {
+	archivos := make(map[int][]domain.Archivo)
+	if len(ids) == 0 {
+		return archivos, nil
+	}
+	rows, err := r.db.Pool.Query(context.Background(), archivoSelect+" WHERE entidad = $1 AND id_entidad = ANY($2) ORDER BY id_entidad, orden, id_archivo", entidad, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a, err := scanArchivo(rows)
+		if err != nil {
+			return nil, err
+		}
+		archivos[a.IDEntidad] = append(archivos[a.IDEntidad], a)
+	}
+	return archivos, rows.Err()
+}
+
+func (r *archivoRepository) GetByID(id int) (*domain.Archivo, error) {
+	a, err := scanArchivo(r.db.Pool.QueryRow(context.Background(), archivoSelect+" WHERE id_archivo = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "archivo", ID: id}
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *archivoRepository) Contar(entidad string, idEntidad int) (int, error) {
+	var total int
+	err := r.db.Pool.QueryRow(context.Background(), `SELECT COUNT(*) FROM archivos WHERE entidad = $1 AND id_entidad = $2`, entidad, idEntidad).Scan(&total)
+	return total, err
+}
+
+// Create agrega el archivo al final de los de su entidad
+func (r *archivoRepository) Create(a *domain.Archivo) error {
+	query := `INSERT INTO archivos (entidad, id_entidad, tipo, nombre_original, content_type, tamano, ruta, ruta_miniatura, orden, usuario_registro)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''),
+		(SELECT COALESCE(MAX(orden), 0) + 1 FROM archivos WHERE entidad = $1 AND id_entidad = $2), $9)
+	RETURNING id_archivo, orden, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, a.Entidad, a.IDEntidad, a.Tipo, a.NombreOriginal, a.ContentType, a.Tamano, a.Ruta, a.RutaMiniatura, a.UsuarioRegistro).
+		Scan(&a.ID, &a.Orden, &a.FechaCreacion)
+}
+
+func (r *archivoRepository) Delete(id int) error {
+	result, err := r.db.Pool.Exec(context.Background(), `DELETE FROM archivos WHERE id_archivo = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "archivo", ID: id}
+	}
+	return nil
+}
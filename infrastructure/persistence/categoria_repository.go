@@ -17,17 +17,25 @@ func NewCategoriaRepository(db *database.Database) domain.CategoriaRepository {
 	return &categoriaRepository{db: db}
 }
 
-func (r *categoriaRepository) GetAll() ([]domain.Categoria, error) {
-	query := `SELECT id_categoria, nombre, fecha_creacion, fecha_actualizacion FROM categorias ORDER BY nombre`
-	rows, err := r.db.Pool.Query(context.Background(), query)
+const categoriaColumnas = `id_categoria, nombre, id_categoria_padre, activo, fecha_baja, fecha_creacion, fecha_actualizacion`
+
+func scanCategoria(row interface{ Scan(dest ...any) error }) (domain.Categoria, error) {
+	var c domain.Categoria
+	err := row.Scan(&c.ID, &c.Nombre, &c.IDPadre, &c.Activo, &c.FechaBaja, &c.FechaCreacion, &c.FechaActualizacion)
+	return c, err
+}
+
+func (r *categoriaRepository) GetAll(incluirInactivas bool) ([]domain.Categoria, error) {
+	query := `SELECT ` + categoriaColumnas + ` FROM categorias WHERE activo OR $1 ORDER BY nombre`
+	rows, err := r.db.Pool.Query(context.Background(), query, incluirInactivas)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var categorias []domain.Categoria
 	for rows.Next() {
-		var c domain.Categoria
-		if err := rows.Scan(&c.ID, &c.Nombre, &c.FechaCreacion, &c.FechaActualizacion); err != nil {
+		c, err := scanCategoria(rows)
+		if err != nil {
 			return nil, err
 		}
 		categorias = append(categorias, c)
@@ -36,9 +44,8 @@ func (r *categoriaRepository) GetAll() ([]domain.Categoria, error) {
 }
 
 func (r *categoriaRepository) GetByID(id int) (*domain.Categoria, error) {
-	query := `SELECT id_categoria, nombre, fecha_creacion, fecha_actualizacion FROM categorias WHERE id_categoria = $1`
-	var c domain.Categoria
-	err := r.db.Pool.QueryRow(context.Background(), query, id).Scan(&c.ID, &c.Nombre, &c.FechaCreacion, &c.FechaActualizacion)
+	query := `SELECT ` + categoriaColumnas + ` FROM categorias WHERE id_categoria = $1`
+	c, err := scanCategoria(r.db.Pool.QueryRow(context.Background(), query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, &domain.ErrNotFound{Entity: "categoria", ID: id}
@@ -49,13 +56,13 @@ func (r *categoriaRepository) GetByID(id int) (*domain.Categoria, error) {
 }
 
 func (r *categoriaRepository) Create(categoria *domain.Categoria) error {
-	query := `INSERT INTO categorias (nombre) VALUES ($1) RETURNING id_categoria, fecha_creacion, fecha_actualizacion`
-	return r.db.Pool.QueryRow(context.Background(), query, categoria.Nombre).Scan(&categoria.ID, &categoria.FechaCreacion, &categoria.FechaActualizacion)
+	query := `INSERT INTO categorias (nombre, id_categoria_padre) VALUES ($1, $2) RETURNING id_categoria, activo, fecha_creacion, fecha_actualizacion`
+	return r.db.Pool.QueryRow(context.Background(), query, categoria.Nombre, categoria.IDPadre).Scan(&categoria.ID, &categoria.Activo, &categoria.FechaCreacion, &categoria.FechaActualizacion)
 }
 
 func (r *categoriaRepository) Update(categoria *domain.Categoria) error {
-	query := `UPDATE categorias SET nombre = $2 WHERE id_categoria = $1 RETURNING fecha_actualizacion`
-	err := r.db.Pool.QueryRow(context.Background(), query, categoria.ID, categoria.Nombre).Scan(&categoria.FechaActualizacion)
+	query := `UPDATE categorias SET nombre = $2, id_categoria_padre = $3 WHERE id_categoria = $1 RETURNING fecha_actualizacion`
+	err := r.db.Pool.QueryRow(context.Background(), query, categoria.ID, categoria.Nombre, categoria.IDPadre).Scan(&categoria.FechaActualizacion)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &domain.ErrNotFound{Entity: "categoria", ID: categoria.ID}
@@ -65,9 +72,18 @@ func (r *categoriaRepository) Update(categoria *domain.Categoria) error {
 	return nil
 }
 
+// Delete da de baja la categoría; sus productos la conservan
 func (r *categoriaRepository) Delete(id int) error {
-	query := `DELETE FROM categorias WHERE id_categoria = $1`
-	result, err := r.db.Pool.Exec(context.Background(), query, id)
+	return r.setActivo(id, false)
+}
+
+func (r *categoriaRepository) Restaurar(id int) error {
+	return r.setActivo(id, true)
+}
+
+func (r *categoriaRepository) setActivo(id int, activo bool) error {
+	query := `UPDATE categorias SET activo = $2, fecha_baja = CASE WHEN $2 THEN NULL ELSE CURRENT_TIMESTAMP END WHERE id_categoria = $1`
+	result, err := r.db.Pool.Exec(context.Background(), query, id, activo)
 	if err != nil {
 		return err
 	}
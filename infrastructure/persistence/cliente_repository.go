@@ -0,0 +1,249 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type clienteRepository struct {
+	db *database.Database
+}
+
+func NewClienteRepository(db *database.Database) domain.ClienteRepository {
+	return &clienteRepository{db: db}
+}
+
+const clienteColumnas = `cl.id_cliente, cl.nombre, cl.tipo_documento, cl.numero_documento, cl.telefono, cl.direccion, cl.email, cl.limite_credito, cl.dias_credito, cl.activo, cl.fecha_creacion, cl.fecha_actualizacion`
+
+// clienteConSaldoSelect agrega la deuda pendiente de cada cliente
+const clienteConSaldoSelect = `
+	SELECT ` + clienteColumnas + `, COALESCE(cc.saldo, 0) AS saldo
+	FROM clientes cl
+	LEFT JOIN (
+		SELECT id_cliente, SUM(saldo) AS saldo FROM cuentas_por_cobrar WHERE estado = 'PENDIENTE' GROUP BY id_cliente
+	) cc ON cc.id_cliente = cl.id_cliente`
+
+const cuentaSelect = `
+	SELECT cu.id_cuenta, cu.id_cliente, cu.id_comprobante, co.serie || '-' || LPAD(co.numero::text, 8, '0'),
+	       cu.fecha_emision, cu.fecha_vencimiento, cu.monto, cu.saldo, cu.estado, cu.fecha_creacion
+	FROM cuentas_por_cobrar cu
+	JOIN comprobantes co ON co.id_comprobante = cu.id_comprobante`
+
+func scanCliente(row interface{ Scan(dest ...any) error }, extra ...any) (domain.Cliente, error) {
+	var c domain.Cliente
+	dest := append([]any{&c.ID, &c.Nombre, &c.TipoDocumento, &c.NumeroDocumento, &c.Telefono, &c.Direccion, &c.Email, &c.LimiteCredito, &c.DiasCredito, &c.Activo, &c.FechaCreacion, &c.FechaActualizacion}, extra...)
+	err := row.Scan(dest...)
+	return c, err
+}
+
+func scanClienteConSaldo(row interface{ Scan(dest ...any) error }) (domain.ClienteConSaldo, error) {
+	var saldo float64
+	c, err := scanCliente(row, &saldo)
+	return domain.ClienteConSaldo{Cliente: c, Saldo: saldo}, err
+}
+
+func scanCuenta(row interface{ Scan(dest ...any) error }) (domain.CuentaPorCobrar, error) {
+	var c domain.CuentaPorCobrar
+	err := row.Scan(&c.ID, &c.IDCliente, &c.IDComprobante, &c.Comprobante, &c.FechaEmision, &c.FechaVencimiento, &c.Monto, &c.Saldo, &c.Estado, &c.FechaCreacion)
+	return c, err
+}
+
+// GetAll busca por nombre (sin distinguir tildes), documento o teléfono; vacío lista todos
+func (r *clienteRepository) GetAll(busqueda string) ([]domain.ClienteConSaldo, error) {
+	query := clienteConSaldoSelect
+	var args []any
+	if busqueda != "" {
+		query += ` WHERE f_unaccent(lower(cl.nombre)) LIKE f_unaccent($1) OR cl.numero_documento LIKE $2 OR cl.telefono LIKE $2`
+		escapado := escaparLike(strings.ToLower(busqueda))
+		args = append(args, "%"+escapado+"%", escapado+"%")
+	}
+	rows, err := r.db.Pool.Query(context.Background(), query+" ORDER BY cl.nombre", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var clientes []domain.ClienteConSaldo
+	for rows.Next() {
+		c, err := scanClienteConSaldo(rows)
+		if err != nil {
+			return nil, err
+		}
+		clientes = append(clientes, c)
+	}
+	return clientes, nil
+}
+
+func (r *clienteRepository) GetByID(id int) (*domain.ClienteConSaldo, error) {
+	c, err := scanClienteConSaldo(r.db.Pool.QueryRow(context.Background(), clienteConSaldoSelect+" WHERE cl.id_cliente = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "cliente", ID: id}
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *clienteRepository) GetByDocumento(tipo, numero string) (*domain.Cliente, error) {
+	c, err := scanCliente(r.db.Pool.QueryRow(context.Background(), `SELECT `+clienteColumnas+` FROM clientes cl WHERE cl.tipo_documento = $1 AND cl.numero_documento = $2`, tipo, numero))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "cliente", ID: numero}
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *clienteRepository) Create(c *domain.Cliente) error {
+	query := `INSERT INTO clientes (nombre, tipo_documento, numero_documento, telefono, direccion, email, limite_credito, dias_credito, activo) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id_cliente, fecha_creacion, fecha_actualizacion`
+	return r.db.Pool.QueryRow(context.Background(), query, c.Nombre, c.TipoDocumento, c.NumeroDocumento, c.Telefono, c.Direccion, c.Email, c.LimiteCredito, c.DiasCredito, c.Activo).Scan(&c.ID, &c.FechaCreacion, &c.FechaActualizacion)
+}
+
+func (r *clienteRepository) Update(c *domain.Cliente) error {
+	query := `UPDATE clientes SET nombre = $2, tipo_documento = $3, numero_documento = $4, telefono = $5, direccion = $6, email = $7, limite_credito = $8, dias_credito = $9, activo = $10, fecha_actualizacion = CURRENT_TIMESTAMP WHERE id_cliente = $1 RETURNING fecha_actualizacion`
+	err := r.db.Pool.QueryRow(context.Background(), query, c.ID, c.Nombre, c.TipoDocumento, c.NumeroDocumento, c.Telefono, c.Direccion, c.Email, c.LimiteCredito, c.DiasCredito, c.Activo).Scan(&c.FechaActualizacion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrNotFound{Entity: "cliente", ID: c.ID}
+	}
+	return err
+}
+
+func (r *clienteRepository) queryCuentas(query string, args ...any) ([]domain.CuentaPorCobrar, error) {
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cuentas []domain.CuentaPorCobrar
+	for rows.Next() {
+		c, err := scanCuenta(rows)
+		if err != nil {
+			return nil, err
+		}
+		cuentas = append(cuentas, c)
+	}
+	return cuentas, nil
+}
+
+// GetCuentas retorna las cuentas del cliente, las más antiguas primero
+func (r *clienteRepository) GetCuentas(clienteID int, soloPendientes bool) ([]domain.CuentaPorCobrar, error) {
+	query := cuentaSelect + " WHERE cu.id_cliente = $1"
+	if soloPendientes {
+		query += " AND cu.estado = 'PENDIENTE'"
+	}
+	return r.queryCuentas(query+" ORDER BY cu.fecha_emision, cu.id_cuenta", clienteID)
+}
+
+func (r *clienteRepository) GetCuentaByComprobante(comprobanteID int) (*domain.CuentaPorCobrar, error) {
+	c, err := scanCuenta(r.db.Pool.QueryRow(context.Background(), cuentaSelect+" WHERE cu.id_comprobante = $1", comprobanteID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "cuenta por cobrar del comprobante", ID: comprobanteID}
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *clienteRepository) GetCuentasPendientes() ([]domain.CuentaPorCobrar, error) {
+	return r.queryCuentas(cuentaSelect + " WHERE cu.estado = 'PENDIENTE' ORDER BY cu.id_cliente, cu.fecha_emision, cu.id_cuenta")
+}
+
+// RegistrarPago aplica el pago solo si cada cuenta todavía tiene el saldo que se
+// calculó; si otro pago se adelantó, nada se registra
+func (r *clienteRepository) RegistrarPago(pago *domain.PagoCliente, ingreso *domain.ControlDiario) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, a := range pago.Aplicaciones {
+		result, err := tx.Exec(ctx, `
+			UPDATE cuentas_por_cobrar
+			SET saldo = saldo - $2,
+			    estado = CASE WHEN saldo - $2 = 0 THEN 'PAGADA' ELSE 'PENDIENTE' END
+			WHERE id_cuenta = $1 AND id_cliente = $3 AND estado = 'PENDIENTE' AND saldo >= $2`, a.IDCuenta, a.Monto, pago.IDCliente)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return &domain.ErrValidation{Field: "monto", Message: fmt.Sprintf("el saldo de la cuenta %d cambió; vuelva a intentar", a.IDCuenta)}
+		}
+	}
+	if err := crearControl(ctx, tx, ingreso); err != nil {
+		return err
+	}
+	pago.IDControl = ingreso.ID
+	query := `INSERT INTO pagos_clientes (id_cliente, fecha, monto, tipo_pago, observaciones, usuario_registro, id_control) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id_pago, fecha_creacion`
+	err = tx.QueryRow(ctx, query, pago.IDCliente, pago.Fecha, pago.Monto, pago.TipoPago, pago.Observaciones, pago.UsuarioRegistro, pago.IDControl).Scan(&pago.ID, &pago.FechaCreacion)
+	if err != nil {
+		return err
+	}
+	for _, a := range pago.Aplicaciones {
+		if _, err := tx.Exec(ctx, `INSERT INTO aplicaciones_pago (id_pago, id_cuenta, monto) VALUES ($1, $2, $3)`, pago.ID, a.IDCuenta, a.Monto); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// GetMovimientos une las ventas al crédito (cargos) con los pagos y las devoluciones
+// descontadas de la cuenta (abonos) del cliente
+func (r *clienteRepository) GetMovimientos(clienteID int, rango domain.RangoFechas) (float64, []domain.MovimientoCuenta, error) {
+	ctx := context.Background()
+	var saldoInicial float64
+	if rango.Desde != "" {
+		err := r.db.Pool.QueryRow(ctx, `
+			SELECT COALESCE((SELECT SUM(monto) FROM cuentas_por_cobrar WHERE id_cliente = $1 AND fecha_emision < $2), 0)
+			     - COALESCE((SELECT SUM(monto) FROM pagos_clientes WHERE id_cliente = $1 AND fecha < $2), 0)
+			     - COALESCE((SELECT SUM(d.monto_reembolso) FROM devoluciones_clientes d
+			                 JOIN cuentas_por_cobrar cu ON cu.id_cuenta = d.id_cuenta
+			                 WHERE cu.id_cliente = $1 AND d.fecha < $2), 0)`, clienteID, rango.Desde).Scan(&saldoInicial)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	var cond condiciones
+	cond.agregar("id_cliente = ?", clienteID)
+	cond.rango("fecha", rango)
+	query := `
+		SELECT fecha, tipo, referencia, cargo, abono FROM (
+			SELECT cu.id_cliente, cu.fecha_emision AS fecha, 'CARGO' AS tipo,
+			       co.serie || '-' || LPAD(co.numero::text, 8, '0') AS referencia,
+			       cu.monto AS cargo, 0::DECIMAL AS abono, cu.fecha_creacion AS creado
+			FROM cuentas_por_cobrar cu
+			JOIN comprobantes co ON co.id_comprobante = cu.id_comprobante
+			UNION ALL
+			SELECT id_cliente, fecha, 'ABONO', 'Pago #' || id_pago || CASE WHEN tipo_pago <> '' THEN ' (' || tipo_pago || ')' ELSE '' END,
+			       0, monto, fecha_creacion
+			FROM pagos_clientes
+			UNION ALL
+			SELECT cu.id_cliente, d.fecha, 'ABONO', 'Devolución #' || d.id_devolucion, 0, d.monto_reembolso, d.fecha_creacion
+			FROM devoluciones_clientes d
+			JOIN cuentas_por_cobrar cu ON cu.id_cuenta = d.id_cuenta
+			WHERE d.monto_reembolso > 0
+		) m` + cond.where() + ` ORDER BY fecha, creado`
+	rows, err := r.db.Pool.Query(ctx, query, cond.args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+	var movimientos []domain.MovimientoCuenta
+	for rows.Next() {
+		var m domain.MovimientoCuenta
+		if err := rows.Scan(&m.Fecha, &m.Tipo, &m.Referencia, &m.Cargo, &m.Abono); err != nil {
+			return 0, nil, err
+		}
+		movimientos = append(movimientos, m)
+	}
+	return saldoInicial, movimientos, nil
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type codigoBarrasRepository struct {
+	db *database.Database
+}
+
+func NewCodigoBarrasRepository(db *database.Database) domain.CodigoBarrasRepository {
+	return &codigoBarrasRepository{db: db}
+}
+
+const codigoBarrasSelect = `SELECT id_codigo_barras, id_producto, codigo, tipo, fecha_creacion FROM codigos_barras`
+
+func scanCodigoBarras(row interface{ Scan(dest ...any) error }) (domain.CodigoBarras, error) {
+	var cb domain.CodigoBarras
+	err := row.Scan(&cb.ID, &cb.IDProducto, &cb.Codigo, &cb.Tipo, &cb.FechaCreacion)
+	return cb, err
+}
+
+func (r *codigoBarrasRepository) GetByProductoID(productoID int) ([]domain.CodigoBarras, error) {
+	rows, err := r.db.Pool.Query(context.Background(), codigoBarrasSelect+" WHERE id_producto = $1 ORDER BY id_codigo_barras", productoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var codigos []domain.CodigoBarras
+	for rows.Next() {
+		cb, err := scanCodigoBarras(rows)
+		if err != nil {
+			return nil, err
+		}
+		codigos = append(codigos, cb)
+	}
+	return codigos, nil
+}
+
+func (r *codigoBarrasRepository) GetByCodigo(codigo string) (*domain.CodigoBarras, error) {
+	row := r.db.Pool.QueryRow(context.Background(), codigoBarrasSelect+" WHERE codigo = ANY($1) LIMIT 1", domain.VariantesCodigoBarras(codigo))
+	cb, err := scanCodigoBarras(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "código de barras", ID: codigo}
+		}
+		return nil, err
+	}
+	return &cb, nil
+}
+
+func (r *codigoBarrasRepository) Create(cb *domain.CodigoBarras) error {
+	query := `INSERT INTO codigos_barras (id_producto, codigo, tipo) VALUES ($1, $2, $3) RETURNING id_codigo_barras, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, cb.IDProducto, cb.Codigo, cb.Tipo).Scan(&cb.ID, &cb.FechaCreacion)
+}
+
+func (r *codigoBarrasRepository) Delete(productoID, id int) error {
+	query := `DELETE FROM codigos_barras WHERE id_codigo_barras = $1 AND id_producto = $2`
+	result, err := r.db.Pool.Exec(context.Background(), query, id, productoID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "código de barras", ID: id}
+	}
+	return nil
+}
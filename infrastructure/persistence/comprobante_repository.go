@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type comprobanteRepository struct {
+	db *database.Database
+}
+
+func NewComprobanteRepository(db *database.Database) domain.ComprobanteRepository {
+	return &comprobanteRepository{db: db}
+}
+
+const serieSelect = `SELECT id_serie, tipo, serie, lugar_venta, correlativo, activa, fecha_creacion FROM series_comprobante`
+
+func scanSerie(row interface{ Scan(dest ...any) error }) (domain.SerieComprobante, error) {
+	var s domain.SerieComprobante
+	err := row.Scan(&s.ID, &s.Tipo, &s.Serie, &s.LugarVenta, &s.Correlativo, &s.Activa, &s.FechaCreacion)
+	return s, err
+}
+
+func (r *comprobanteRepository) GetByID(id int) (*domain.Comprobante, error) {
+	var c domain.Comprobante
+	query := `SELECT id_comprobante, id_serie, tipo, serie, numero, id_cliente, fecha_emision, lugar_venta, tipo_pago, usuario_registro, subtotal, descuento, op_gravada, op_exonerada, op_inafecta, igv, total, fecha_creacion FROM comprobantes WHERE id_comprobante = $1`
+	err := r.db.Pool.QueryRow(context.Background(), query, id).Scan(&c.ID, &c.IDSerie, &c.Tipo, &c.Serie, &c.Numero, &c.IDCliente, &c.FechaEmision, &c.LugarVenta, &c.TipoPago, &c.UsuarioRegistro, &c.Subtotal, &c.Descuento, &c.OpGravada, &c.OpExonerada, &c.OpInafecta, &c.IGV, &c.Total, &c.FechaCreacion)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "comprobante", ID: id}
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *comprobanteRepository) GetSeries() ([]domain.SerieComprobante, error) {
+	rows, err := r.db.Pool.Query(context.Background(), serieSelect+" ORDER BY serie")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var series []domain.SerieComprobante
+	for rows.Next() {
+		s, err := scanSerie(rows)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+func (r *comprobanteRepository) GetSerieByID(id int) (*domain.SerieComprobante, error) {
+	s, err := scanSerie(r.db.Pool.QueryRow(context.Background(), serieSelect+" WHERE id_serie = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "serie de comprobante", ID: id}
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *comprobanteRepository) CreateSerie(s *domain.SerieComprobante) error {
+	query := `INSERT INTO series_comprobante (tipo, serie, lugar_venta, correlativo, activa) VALUES ($1, $2, $3, $4, $5) RETURNING id_serie, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, s.Tipo, s.Serie, s.LugarVenta, s.Correlativo, s.Activa).Scan(&s.ID, &s.FechaCreacion)
+}
+
+// UpdateSerie no modifica el correlativo: solo avanza al emitir comprobantes
+func (r *comprobanteRepository) UpdateSerie(s *domain.SerieComprobante) error {
+	query := `UPDATE series_comprobante SET tipo = $2, lugar_venta = $3, activa = $4 WHERE id_serie = $1`
+	result, err := r.db.Pool.Exec(context.Background(), query, s.ID, s.Tipo, s.LugarVenta, s.Activa)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "serie de comprobante", ID: s.ID}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+)
+
+type configuracionRepository struct {
+	db *database.Database
+}
+
+func NewConfiguracionRepository(db *database.Database) domain.ConfiguracionRepository {
+	return &configuracionRepository{db: db}
+}
+
+func (r *configuracionRepository) GetAll() (map[string]string, error) {
+	rows, err := r.db.Pool.Query(context.Background(), `SELECT clave, valor FROM configuracion`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	valores := make(map[string]string)
+	for rows.Next() {
+		var clave, valor string
+		if err := rows.Scan(&clave, &valor); err != nil {
+			return nil, err
+		}
+		valores[clave] = valor
+	}
+	return valores, nil
+}
+
+// Set guarda todas las claves en una transacción
+func (r *configuracionRepository) Set(valores map[string]string) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for clave, valor := range valores {
+		_, err := tx.Exec(ctx, `INSERT INTO configuracion (clave, valor) VALUES ($1, $2) ON CONFLICT (clave) DO UPDATE SET valor = EXCLUDED.valor, fecha_actualizacion = CURRENT_TIMESTAMP`, clave, valor)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -2,11 +2,13 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
 )
 
 type controlDiarioRepository struct {
@@ -25,21 +27,44 @@ func scanControl(row interface{ Scan(dest ...any) error }) (domain.ControlDiario
 	return c, err
 }
 
-func (r *controlDiarioRepository) GetAll() ([]domain.ControlDiario, error) {
-	rows, err := r.db.Pool.Query(context.Background(), controlSelect+" ORDER BY fecha DESC, fecha_creacion DESC")
+var columnasOrdenControlDiario = map[string]string{
+	"fecha":         "fecha",
+	"monto_entrada": "monto_entrada",
+	"monto_salida":  "monto_salida",
+	"usuario":       "usuario_registro",
+}
+
+func (r *controlDiarioRepository) GetAll(filtro domain.FiltroControlDiario) ([]domain.ControlDiario, *domain.TotalesControlDiario, error) {
+	var cond condiciones
+	cond.rango("fecha", filtro.RangoFechas)
+	if filtro.Usuario != "" {
+		cond.agregar("UPPER(usuario_registro) = UPPER(?)", filtro.Usuario)
+	}
+	if filtro.EsVerbena != nil {
+		cond.agregar("es_verbena = ?", *filtro.EsVerbena)
+	}
+	totales := &domain.TotalesControlDiario{}
+	err := r.db.Pool.QueryRow(context.Background(), "SELECT COUNT(*), COALESCE(SUM(monto_entrada), 0), COALESCE(SUM(monto_salida), 0) FROM control_diario"+cond.where(), cond.args...).
+		Scan(&totales.Registros, &totales.MontoEntrada, &totales.MontoSalida)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	limite, args := cond.paginar(filtro.Paginacion)
+	query := controlSelect + cond.where() + ordenarPor(filtro.Orden, columnasOrdenControlDiario, "fecha DESC, fecha_creacion DESC, id_control DESC") + limite
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, nil, err
 	}
 	defer rows.Close()
 	var controles []domain.ControlDiario
 	for rows.Next() {
 		c, err := scanControl(rows)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		controles = append(controles, c)
 	}
-	return controles, nil
+	return controles, totales, nil
 }
 
 func (r *controlDiarioRepository) GetByFecha(fecha string) ([]domain.ControlDiario, error) {
@@ -80,15 +105,34 @@ func (r *controlDiarioRepository) GetVerbena() ([]domain.ControlDiario, error) {
 	return controles, nil
 }
 
+func (r *controlDiarioRepository) GetByID(id int) (*domain.ControlDiario, error) {
+	c, err := scanControl(r.db.Pool.QueryRow(context.Background(), controlSelect+" WHERE id_control = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "control diario", ID: id}
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
 func (r *controlDiarioRepository) Create(control *domain.ControlDiario) error {
+	return crearControl(context.Background(), r.db.Pool, control)
+}
+
+// crearControl inserta el registro con el pool o dentro de una transacción de otro
+// repositorio (cobranzas, devoluciones)
+func crearControl(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, control *domain.ControlDiario) error {
 	query := `INSERT INTO control_diario (fecha, descripcion, monto_entrada, monto_salida, observaciones, es_verbena, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id_control, fecha_creacion, fecha_actualizacion`
-	return r.db.Pool.QueryRow(context.Background(), query, control.Fecha, control.Descripcion, control.MontoEntrada, control.MontoSalida, control.Observaciones, control.EsVerbena, control.UsuarioRegistro).Scan(&control.ID, &control.FechaCreacion, &control.FechaActualizacion)
+	return db.QueryRow(ctx, query, control.Fecha, control.Descripcion, control.MontoEntrada, control.MontoSalida, control.Observaciones, control.EsVerbena, control.UsuarioRegistro).Scan(&control.ID, &control.FechaCreacion, &control.FechaActualizacion)
 }
 
 func (r *controlDiarioRepository) GenerarDesdeVentas(fecha string) (*domain.ControlDiario, error) {
 	var totalVentas float64
 	var cantidadVentas int
-	err := r.db.Pool.QueryRow(context.Background(), `SELECT COALESCE(SUM(total), 0), COUNT(*) FROM salidas_productos WHERE fecha_salida = $1`, fecha).Scan(&totalVentas, &cantidadVentas)
+	err := r.db.Pool.QueryRow(context.Background(), `SELECT COALESCE(SUM(total), 0), COUNT(*) FROM salidas_productos WHERE fecha_salida = $1 AND UPPER(tipo_pago) <> 'CREDITO'`, fecha).Scan(&totalVentas, &cantidadVentas)
 	if err != nil {
 		return nil, err
 	}
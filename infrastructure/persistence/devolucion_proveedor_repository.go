@@ -0,0 +1,166 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type devolucionProveedorRepository struct {
+	db *database.Database
+}
+
+func NewDevolucionProveedorRepository(db *database.Database) domain.DevolucionProveedorRepository {
+	return &devolucionProveedorRepository{db: db}
+}
+
+const devolucionProveedorSelect = `
+	SELECT d.id_devolucion, d.id_entrada, d.id_producto, d.proveedor, d.fecha, d.cantidad, d.tipo_credito,
+	       d.monto_esperado, d.base_imponible, d.igv, d.motivo, d.usuario_registro, d.estado, d.numero_nota, d.monto_liquidado,
+	       d.fecha_liquidacion, d.id_control, d.fecha_creacion, p.nombre, p.codigo
+	FROM devoluciones_proveedores d
+	JOIN productos p ON d.id_producto = p.id_producto`
+
+func scanDevolucionProveedor(row interface{ Scan(dest ...any) error }) (domain.DevolucionProveedorConProducto, error) {
+	var d domain.DevolucionProveedorConProducto
+	err := row.Scan(&d.ID, &d.IDEntrada, &d.IDProducto, &d.Proveedor, &d.Fecha, &d.Cantidad, &d.TipoCredito,
+		&d.MontoEsperado, &d.BaseImponible, &d.IGV, &d.Motivo, &d.UsuarioRegistro, &d.Estado, &d.NumeroNota, &d.MontoLiquidado,
+		&d.FechaLiquidacion, &d.IDControl, &d.FechaCreacion, &d.NombreProducto, &d.CodigoProducto)
+	return d, err
+}
+
+func (r *devolucionProveedorRepository) query(query string, args ...any) ([]domain.DevolucionProveedorConProducto, error) {
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var devoluciones []domain.DevolucionProveedorConProducto
+	for rows.Next() {
+		d, err := scanDevolucionProveedor(rows)
+		if err != nil {
+			return nil, err
+		}
+		devoluciones = append(devoluciones, d)
+	}
+	return devoluciones, rows.Err()
+}
+
+func (r *devolucionProveedorRepository) GetAll(filtro domain.FiltroDevolucionesProveedor) ([]domain.DevolucionProveedorConProducto, error) {
+	var cond condiciones
+	cond.rango("d.fecha", filtro.RangoFechas)
+	if filtro.Proveedor != "" {
+		cond.agregar("UPPER(d.proveedor) = UPPER(?)", filtro.Proveedor)
+	}
+	if filtro.Estado != "" {
+		cond.agregar("d.estado = ?", filtro.Estado)
+	}
+	return r.query(devolucionProveedorSelect+cond.where()+" ORDER BY d.fecha DESC, d.id_devolucion DESC", cond.args...)
+}
+
+func (r *devolucionProveedorRepository) GetByID(id int) (*domain.DevolucionProveedorConProducto, error) {
+	d, err := scanDevolucionProveedor(r.db.Pool.QueryRow(context.Background(), devolucionProveedorSelect+" WHERE d.id_devolucion = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "devolución a proveedor", ID: id}
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *devolucionProveedorRepository) GetByEntrada(entradaID int) ([]domain.DevolucionProveedorConProducto, error) {
+	return r.query(devolucionProveedorSelect+" WHERE d.id_entrada = $1 ORDER BY d.fecha, d.id_devolucion", entradaID)
+}
+
+func (r *devolucionProveedorRepository) GetDevuelto(entradaID int) (int, error) {
+	var cantidad int
+	err := r.db.Pool.QueryRow(context.Background(), `SELECT COALESCE(SUM(cantidad), 0) FROM devoluciones_proveedores WHERE id_entrada = $1`, entradaID).Scan(&cantidad)
+	return cantidad, err
+}
+
+// Create bloquea la entrada para que dos devoluciones simultáneas no superen juntas lo
+// recibido y descuenta el stock sin dejarlo negativo
+func (r *devolucionProveedorRepository) Create(devolucion *domain.DevolucionProveedor) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var recibido, devuelto int
+	err = tx.QueryRow(ctx, `SELECT cantidad FROM entradas_productos WHERE id_entrada = $1 FOR UPDATE`, devolucion.IDEntrada).Scan(&recibido)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrNotFound{Entity: "entrada", ID: devolucion.IDEntrada}
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(cantidad), 0) FROM devoluciones_proveedores WHERE id_entrada = $1`, devolucion.IDEntrada).Scan(&devuelto); err != nil {
+		return err
+	}
+	if devuelto+devolucion.Cantidad > recibido {
+		return &domain.ErrValidation{Field: "cantidad", Message: fmt.Sprintf("supera lo recibido pendiente de devolver (%d de %d)", recibido-devuelto, recibido)}
+	}
+
+	query := `INSERT INTO devoluciones_proveedores (id_entrada, id_producto, proveedor, fecha, cantidad, tipo_credito, monto_esperado, base_imponible, igv, motivo, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id_devolucion, estado, fecha_creacion`
+	err = tx.QueryRow(ctx, query, devolucion.IDEntrada, devolucion.IDProducto, devolucion.Proveedor, devolucion.Fecha, devolucion.Cantidad, devolucion.TipoCredito, devolucion.MontoEsperado, devolucion.BaseImponible, devolucion.IGV, devolucion.Motivo, devolucion.UsuarioRegistro).Scan(&devolucion.ID, &devolucion.Estado, &devolucion.FechaCreacion)
+	if err != nil {
+		return err
+	}
+
+	// La salida del stock queda en ajustes_stock para que la matriz mensual cuadre
+	var stockNuevo int
+	err = tx.QueryRow(ctx, `UPDATE productos SET stock_actual = stock_actual - $2 WHERE id_producto = $1 AND stock_actual >= $2 RETURNING stock_actual`, devolucion.IDProducto, devolucion.Cantidad).Scan(&stockNuevo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var disponible int
+		if err := tx.QueryRow(ctx, `SELECT stock_actual FROM productos WHERE id_producto = $1`, devolucion.IDProducto).Scan(&disponible); err != nil {
+			return err
+		}
+		return &domain.ErrInsufficientStock{ProductoID: devolucion.IDProducto, StockActual: disponible, CantidadReq: devolucion.Cantidad}
+	}
+	if err != nil {
+		return err
+	}
+	motivo := fmt.Sprintf("Devolución a proveedor (devolución #%d)", devolucion.ID)
+	_, err = tx.Exec(ctx, `INSERT INTO ajustes_stock (id_producto, fecha, cantidad, stock_anterior, stock_nuevo, motivo) VALUES ($1, $2, $3, $4, $5, $6)`, devolucion.IDProducto, devolucion.Fecha, -devolucion.Cantidad, stockNuevo+devolucion.Cantidad, stockNuevo, motivo)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Liquidar solo cierra devoluciones pendientes: si otra liquidación se adelantó no se
+// registra el ingreso
+func (r *devolucionProveedorRepository) Liquidar(id int, liquidacion *domain.LiquidacionProveedor, ingreso *domain.ControlDiario) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var idControl *int
+	if ingreso != nil {
+		if err := crearControl(ctx, tx, ingreso); err != nil {
+			return err
+		}
+		idControl = &ingreso.ID
+	}
+	result, err := tx.Exec(ctx, `
+		UPDATE devoluciones_proveedores
+		SET estado = 'LIQUIDADA', numero_nota = $2, monto_liquidado = $3, fecha_liquidacion = $4, id_control = $5
+		WHERE id_devolucion = $1 AND estado = 'PENDIENTE'`, id, liquidacion.NumeroNota, liquidacion.Monto, liquidacion.Fecha, idControl)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrValidation{Field: "estado", Message: "la devolución ya fue liquidada"}
+	}
+	return tx.Commit(ctx)
+}
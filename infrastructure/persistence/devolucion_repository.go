@@ -0,0 +1,170 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type devolucionRepository struct {
+	db *database.Database
+}
+
+func NewDevolucionRepository(db *database.Database) domain.DevolucionRepository {
+	return &devolucionRepository{db: db}
+}
+
+const devolucionSelect = `
+	SELECT d.id_devolucion, d.id_salida, d.id_producto, d.fecha, d.cantidad, d.destino, d.metodo_reembolso,
+	       d.monto_reembolso, d.base_imponible, d.igv, d.motivo, d.usuario_registro, d.id_control, d.id_cuenta, d.fecha_creacion,
+	       p.nombre, p.codigo, COALESCE(co.serie || '-' || LPAD(co.numero::text, 8, '0'), '') AS comprobante
+	FROM devoluciones_clientes d
+	JOIN productos p ON d.id_producto = p.id_producto
+	JOIN salidas_productos sp ON d.id_salida = sp.id_salida
+	LEFT JOIN comprobantes co ON sp.id_comprobante = co.id_comprobante`
+
+func scanDevolucion(row interface{ Scan(dest ...any) error }) (domain.DevolucionConProducto, error) {
+	var d domain.DevolucionConProducto
+	err := row.Scan(&d.ID, &d.IDSalida, &d.IDProducto, &d.Fecha, &d.Cantidad, &d.Destino, &d.MetodoReembolso,
+		&d.MontoReembolso, &d.BaseImponible, &d.IGV, &d.Motivo, &d.UsuarioRegistro, &d.IDControl, &d.IDCuenta, &d.FechaCreacion,
+		&d.NombreProducto, &d.CodigoProducto, &d.Comprobante)
+	return d, err
+}
+
+func (r *devolucionRepository) query(query string, args ...any) ([]domain.DevolucionConProducto, error) {
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var devoluciones []domain.DevolucionConProducto
+	for rows.Next() {
+		d, err := scanDevolucion(rows)
+		if err != nil {
+			return nil, err
+		}
+		devoluciones = append(devoluciones, d)
+	}
+	return devoluciones, rows.Err()
+}
+
+func (r *devolucionRepository) GetAll(rango domain.RangoFechas) ([]domain.DevolucionConProducto, error) {
+	var cond condiciones
+	cond.rango("d.fecha", rango)
+	return r.query(devolucionSelect+cond.where()+" ORDER BY d.fecha DESC, d.id_devolucion DESC", cond.args...)
+}
+
+func (r *devolucionRepository) GetByID(id int) (*domain.DevolucionConProducto, error) {
+	d, err := scanDevolucion(r.db.Pool.QueryRow(context.Background(), devolucionSelect+" WHERE d.id_devolucion = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "devolución", ID: id}
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *devolucionRepository) GetBySalida(salidaID int) ([]domain.DevolucionConProducto, error) {
+	return r.query(devolucionSelect+" WHERE d.id_salida = $1 ORDER BY d.fecha, d.id_devolucion", salidaID)
+}
+
+func (r *devolucionRepository) GetDevuelto(salidaID int) (domain.Devuelto, error) {
+	return devuelto(context.Background(), r.db.Pool, salidaID)
+}
+
+func devuelto(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, salidaID int) (domain.Devuelto, error) {
+	var d domain.Devuelto
+	err := db.QueryRow(ctx, `SELECT COALESCE(SUM(cantidad), 0), COALESCE(SUM(monto_reembolso), 0) FROM devoluciones_clientes WHERE id_salida = $1`, salidaID).Scan(&d.Cantidad, &d.Monto)
+	return d, err
+}
+
+// Create bloquea la salida para que dos devoluciones simultáneas no superen juntas lo
+// vendido; lo calculado por el servicio se vuelve a comprobar dentro de la transacción
+func (r *devolucionRepository) Create(devolucion *domain.DevolucionCliente, componentes []domain.ComponenteKit, reembolso *domain.ControlDiario) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var vendido int
+	var total float64
+	err = tx.QueryRow(ctx, `SELECT cantidad, total FROM salidas_productos WHERE id_salida = $1 FOR UPDATE`, devolucion.IDSalida).Scan(&vendido, &total)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrNotFound{Entity: "salida", ID: devolucion.IDSalida}
+	}
+	if err != nil {
+		return err
+	}
+	previo, err := devuelto(ctx, tx, devolucion.IDSalida)
+	if err != nil {
+		return err
+	}
+	if previo.Cantidad+devolucion.Cantidad > vendido {
+		return &domain.ErrValidation{Field: "cantidad", Message: fmt.Sprintf("supera lo vendido pendiente de devolver (%d de %d)", vendido-previo.Cantidad, vendido)}
+	}
+	if previo.Monto+devolucion.MontoReembolso > total+0.005 {
+		return &domain.ErrValidation{Field: "monto_reembolso", Message: fmt.Sprintf("supera lo que queda por reembolsar de la venta (%.2f)", total-previo.Monto)}
+	}
+
+	if reembolso != nil {
+		if err := crearControl(ctx, tx, reembolso); err != nil {
+			return err
+		}
+		devolucion.IDControl = &reembolso.ID
+	}
+	if devolucion.IDCuenta != nil && devolucion.MontoReembolso > 0 {
+		result, err := tx.Exec(ctx, `
+			UPDATE cuentas_por_cobrar
+			SET saldo = saldo - $2,
+			    estado = CASE WHEN saldo - $2 = 0 THEN 'PAGADA' ELSE 'PENDIENTE' END
+			WHERE id_cuenta = $1 AND saldo >= $2`, *devolucion.IDCuenta, devolucion.MontoReembolso)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return &domain.ErrValidation{Field: "monto_reembolso", Message: "el saldo de la cuenta cambió; vuelva a intentar"}
+		}
+	}
+	query := `INSERT INTO devoluciones_clientes (id_salida, id_producto, fecha, cantidad, destino, metodo_reembolso, monto_reembolso, base_imponible, igv, motivo, usuario_registro, id_control, id_cuenta) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id_devolucion, fecha_creacion`
+	err = tx.QueryRow(ctx, query, devolucion.IDSalida, devolucion.IDProducto, devolucion.Fecha, devolucion.Cantidad, devolucion.Destino, devolucion.MetodoReembolso, devolucion.MontoReembolso, devolucion.BaseImponible, devolucion.IGV, devolucion.Motivo, devolucion.UsuarioRegistro, devolucion.IDControl, devolucion.IDCuenta).Scan(&devolucion.ID, &devolucion.FechaCreacion)
+	if err != nil {
+		return err
+	}
+
+	if devolucion.Destino != domain.DestinoDevolucionStock {
+		return tx.Commit(ctx)
+	}
+	// El reingreso queda en ajustes_stock: la salida original no cambia y la matriz
+	// mensual sigue cuadrando
+	reingresos := []descuentoStock{{idProducto: devolucion.IDProducto, cantidad: devolucion.Cantidad}}
+	if len(componentes) > 0 {
+		reingresos = reingresos[:0]
+		for _, c := range componentes {
+			reingresos = append(reingresos, descuentoStock{idProducto: c.IDComponente, cantidad: c.Cantidad * devolucion.Cantidad})
+		}
+	}
+	sort.SliceStable(reingresos, func(i, j int) bool { return reingresos[i].idProducto < reingresos[j].idProducto })
+	motivo := fmt.Sprintf("Devolución de cliente (devolución #%d)", devolucion.ID)
+	for _, d := range reingresos {
+		var stockNuevo int
+		err := tx.QueryRow(ctx, `UPDATE productos SET stock_actual = stock_actual + $2 WHERE id_producto = $1 RETURNING stock_actual`, d.idProducto, d.cantidad).Scan(&stockNuevo)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO ajustes_stock (id_producto, fecha, cantidad, stock_anterior, stock_nuevo, motivo) VALUES ($1, $2, $3, $4, $5, $6)`, d.idProducto, devolucion.Fecha, d.cantidad, stockNuevo-d.cantidad, stockNuevo, motivo)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -17,41 +17,74 @@ func NewEntradaProductoRepository(db *database.Database) domain.EntradaProductoR
 	return &entradaProductoRepository{db: db}
 }
 
-const entradaSelectJoin = `
-	SELECT ep.id_entrada, ep.id_producto, ep.fecha_entrada, ep.cantidad,
-	       ep.precio_unitario, ep.observaciones, ep.usuario_registro,
-	       ep.fecha_creacion, ep.fecha_actualizacion,
-	       p.nombre, p.codigo, COALESCE(c.nombre, '') AS nombre_categoria
+const entradaFromJoin = `
 	FROM entradas_productos ep
 	JOIN productos p ON ep.id_producto = p.id_producto
 	LEFT JOIN categorias c ON p.id_categoria = c.id_categoria`
 
+const entradaSelectJoin = `
+	SELECT ep.id_entrada, ep.id_producto, ep.fecha_entrada, ep.cantidad,
+	       ep.precio_unitario, ep.afectacion_igv, ep.tasa_igv, ep.base_imponible, ep.igv,
+	       ep.proveedor, ep.observaciones, ep.usuario_registro,
+	       ep.fecha_creacion, ep.fecha_actualizacion,
+	       p.nombre, p.codigo, COALESCE(c.nombre, '') AS nombre_categoria` + entradaFromJoin
+
+var columnasOrdenEntradas = map[string]string{
+	"fecha":     "ep.fecha_entrada",
+	"cantidad":  "ep.cantidad",
+	"precio":    "ep.precio_unitario",
+	"producto":  "p.nombre",
+	"usuario":   "ep.usuario_registro",
+	"proveedor": "ep.proveedor",
+}
+
 func scanEntradaConProducto(rows pgx.Rows) (domain.EntradaConProducto, error) {
 	var e domain.EntradaConProducto
 	err := rows.Scan(
 		&e.ID, &e.IDProducto, &e.FechaEntrada, &e.Cantidad,
-		&e.PrecioUnitario, &e.Observaciones, &e.UsuarioRegistro,
+		&e.PrecioUnitario, &e.Afectacion, &e.Tasa, &e.BaseImponible, &e.IGV,
+		&e.Proveedor, &e.Observaciones, &e.UsuarioRegistro,
 		&e.FechaCreacion, &e.FechaActualizacion,
 		&e.NombreProducto, &e.CodigoProducto, &e.NombreCategoria,
 	)
 	return e, err
 }
 
-func (r *entradaProductoRepository) GetAll() ([]domain.EntradaConProducto, error) {
-	rows, err := r.db.Pool.Query(context.Background(), entradaSelectJoin+" ORDER BY ep.fecha_entrada DESC")
+func (r *entradaProductoRepository) GetAll(filtro domain.FiltroEntradas) ([]domain.EntradaConProducto, int, error) {
+	var cond condiciones
+	cond.rango("ep.fecha_entrada", filtro.RangoFechas)
+	if filtro.IDProducto != nil {
+		cond.agregar("ep.id_producto = ?", *filtro.IDProducto)
+	}
+	if filtro.IDCategoria != nil {
+		cond.agregar("p.id_categoria IN "+subcategorias, *filtro.IDCategoria)
+	}
+	if filtro.Usuario != "" {
+		cond.agregar("UPPER(ep.usuario_registro) = UPPER(?)", filtro.Usuario)
+	}
+	if filtro.Proveedor != "" {
+		cond.agregar("UPPER(ep.proveedor) = UPPER(?)", filtro.Proveedor)
+	}
+	var total int
+	if err := r.db.Pool.QueryRow(context.Background(), "SELECT COUNT(*)"+entradaFromJoin+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	limite, args := cond.paginar(filtro.Paginacion)
+	query := entradaSelectJoin + cond.where() + ordenarPor(filtro.Orden, columnasOrdenEntradas, "ep.fecha_entrada DESC, ep.id_entrada DESC") + limite
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 	var entradas []domain.EntradaConProducto
 	for rows.Next() {
 		e, err := scanEntradaConProducto(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		entradas = append(entradas, e)
 	}
-	return entradas, nil
+	return entradas, total, nil
 }
 
 func (r *entradaProductoRepository) GetByID(id int) (*domain.EntradaConProducto, error) {
@@ -109,8 +142,8 @@ func (r *entradaProductoRepository) Create(entrada *domain.EntradaProducto) erro
 	if err != nil {
 		return err
 	}
-	query := `INSERT INTO entradas_productos (id_producto, fecha_entrada, cantidad, precio_unitario, observaciones, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id_entrada, fecha_creacion, fecha_actualizacion`
-	err = r.db.Pool.QueryRow(context.Background(), query, entrada.IDProducto, fechaEntrada, entrada.Cantidad, entrada.PrecioUnitario, entrada.Observaciones, entrada.UsuarioRegistro).Scan(&entrada.ID, &entrada.FechaCreacion, &entrada.FechaActualizacion)
+	query := `INSERT INTO entradas_productos (id_producto, fecha_entrada, cantidad, precio_unitario, afectacion_igv, tasa_igv, base_imponible, igv, proveedor, observaciones, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id_entrada, fecha_creacion, fecha_actualizacion`
+	err = r.db.Pool.QueryRow(context.Background(), query, entrada.IDProducto, fechaEntrada, entrada.Cantidad, entrada.PrecioUnitario, entrada.Afectacion, entrada.Tasa, entrada.BaseImponible, entrada.IGV, entrada.Proveedor, entrada.Observaciones, entrada.UsuarioRegistro).Scan(&entrada.ID, &entrada.FechaCreacion, &entrada.FechaActualizacion)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mishka-GDI-Back/domain"
+)
+
+// condiciones acumula cláusulas WHERE numerando los parámetros. Cada cláusula usa
+// '?' como marcador del valor.
+type condiciones struct {
+	partes []string
+	args   []any
+}
+
+func (c *condiciones) agregar(clausula string, valor any) {
+	c.args = append(c.args, valor)
+	c.partes = append(c.partes, strings.ReplaceAll(clausula, "?", fmt.Sprintf("$%d", len(c.args))))
+}
+
+func (c *condiciones) where() string {
+	if len(c.partes) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.partes, " AND ")
+}
+
+// rango agrega el filtro inclusivo de fechas sobre la columna indicada
+func (c *condiciones) rango(columna string, r domain.RangoFechas) {
+	if r.Desde != "" {
+		c.agregar(columna+" >= ?::date", r.Desde)
+	}
+	if r.Hasta != "" {
+		c.agregar(columna+" <= ?::date", r.Hasta)
+	}
+}
+
+// paginar retorna LIMIT/OFFSET con sus parámetros a continuación de los del WHERE
+func (c *condiciones) paginar(p domain.Paginacion) (string, []any) {
+	if p.Todos {
+		return "", c.args
+	}
+	n := len(c.args)
+	args := append(append([]any{}, c.args...), p.Limite, p.Offset())
+	return fmt.Sprintf(" LIMIT $%d OFFSET $%d", n+1, n+2), args
+}
+
+// ordenarPor traduce el campo pedido a su columna; el orden por defecto se agrega
+// siempre al final para que la paginación sea estable
+func ordenarPor(o domain.Orden, columnas map[string]string, defecto string) string {
+	columna, ok := columnas[o.Campo]
+	if !ok {
+		return " ORDER BY " + defecto
+	}
+	direccion := "ASC"
+	if o.Desc {
+		direccion = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s", columna, direccion, defecto)
+}
+
+// subcategorias es la categoría del marcador '?' y todas sus descendientes, para que
+// filtrar por una categoría incluya lo de sus subcategorías
+const subcategorias = `(WITH RECURSIVE sub AS (
+	SELECT ?::int AS id
+	UNION ALL
+	SELECT ch.id_categoria FROM categorias ch JOIN sub ON ch.id_categoria_padre = sub.id
+) SELECT id FROM sub)`
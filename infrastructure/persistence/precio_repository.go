@@ -0,0 +1,240 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type precioRepository struct {
+	db *database.Database
+}
+
+func NewPrecioRepository(db *database.Database) domain.PrecioRepository {
+	return &precioRepository{db: db}
+}
+
+const (
+	historialPrecioSelect  = `SELECT id_historial, id_producto, precio_anterior, precio_nuevo, usuario, motivo, id_programado, fecha_cambio FROM historial_precios`
+	precioProgramadoSelect = `SELECT id_programado, id_producto, precio_nuevo, fecha_vigencia, usuario, estado, fecha_aplicacion, fecha_creacion FROM precios_programados`
+)
+
+func scanPrecioProgramado(row interface{ Scan(dest ...any) error }) (domain.PrecioProgramado, error) {
+	var p domain.PrecioProgramado
+	err := row.Scan(&p.ID, &p.IDProducto, &p.PrecioNuevo, &p.FechaVigencia, &p.Usuario, &p.Estado, &p.FechaAplicacion, &p.FechaCreacion)
+	return p, err
+}
+
+func (r *precioRepository) queryProgramados(query string, args ...any) ([]domain.PrecioProgramado, error) {
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var programados []domain.PrecioProgramado
+	for rows.Next() {
+		p, err := scanPrecioProgramado(rows)
+		if err != nil {
+			return nil, err
+		}
+		programados = append(programados, p)
+	}
+	return programados, rows.Err()
+}
+
+func (r *precioRepository) GetHistorial(productoID int) ([]domain.CambioPrecio, error) {
+	rows, err := r.db.Pool.Query(context.Background(), historialPrecioSelect+" WHERE id_producto = $1 ORDER BY fecha_cambio, id_historial", productoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var historial []domain.CambioPrecio
+	for rows.Next() {
+		var c domain.CambioPrecio
+		if err := rows.Scan(&c.ID, &c.IDProducto, &c.PrecioAnterior, &c.PrecioNuevo, &c.Usuario, &c.Motivo, &c.IDProgramado, &c.FechaCambio); err != nil {
+			return nil, err
+		}
+		historial = append(historial, c)
+	}
+	return historial, rows.Err()
+}
+
+func (r *precioRepository) GetProgramados(productoID int) ([]domain.PrecioProgramado, error) {
+	return r.queryProgramados(precioProgramadoSelect+" WHERE id_producto = $1 ORDER BY fecha_vigencia, id_programado", productoID)
+}
+
+func (r *precioRepository) GetProgramadoByID(id int) (*domain.PrecioProgramado, error) {
+	p, err := scanPrecioProgramado(r.db.Pool.QueryRow(context.Background(), precioProgramadoSelect+" WHERE id_programado = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "cambio de precio programado", ID: id}
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPendientesHasta retorna los cambios pendientes con vigencia hasta 'fecha' inclusive
+func (r *precioRepository) GetPendientesHasta(fecha time.Time) ([]domain.PrecioProgramado, error) {
+	return r.queryProgramados(precioProgramadoSelect+" WHERE estado = 'PENDIENTE' AND fecha_vigencia <= $1 ORDER BY fecha_vigencia, id_programado", fecha.Format("2006-01-02"))
+}
+
+func (r *precioRepository) CreateProgramado(p *domain.PrecioProgramado) error {
+	query := `INSERT INTO precios_programados (id_producto, precio_nuevo, fecha_vigencia, usuario) VALUES ($1, $2, $3, $4) RETURNING id_programado, estado, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, p.IDProducto, p.PrecioNuevo, p.FechaVigencia.Format("2006-01-02"), p.Usuario).Scan(&p.ID, &p.Estado, &p.FechaCreacion)
+}
+
+func (r *precioRepository) CancelarProgramado(id int) error {
+	result, err := r.db.Pool.Exec(context.Background(), `UPDATE precios_programados SET estado = 'CANCELADO' WHERE id_programado = $1 AND estado = 'PENDIENTE'`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "cambio de precio pendiente", ID: id}
+	}
+	return nil
+}
+
+// AplicarProgramado actualiza el precio del producto, registra el cambio en el
+// historial y marca el programado como aplicado en una sola transacción. Retorna nil
+// sin error si el cambio ya no estaba pendiente (p. ej. se canceló mientras tanto).
+func (r *precioRepository) AplicarProgramado(p *domain.PrecioProgramado) (*domain.CambioPrecio, error) {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `UPDATE precios_programados SET estado = 'APLICADO', fecha_aplicacion = CURRENT_TIMESTAMP WHERE id_programado = $1 AND estado = 'PENDIENTE'`, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, nil
+	}
+	cambio := &domain.CambioPrecio{
+		IDProducto:   p.IDProducto,
+		PrecioNuevo:  p.PrecioNuevo,
+		Usuario:      p.Usuario,
+		Motivo:       fmt.Sprintf("Cambio programado #%d", p.ID),
+		IDProgramado: &p.ID,
+	}
+	err = tx.QueryRow(ctx, `SELECT precio_unitario FROM productos WHERE id_producto = $1 FOR UPDATE`, p.IDProducto).Scan(&cambio.PrecioAnterior)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "producto", ID: p.IDProducto}
+		}
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE productos SET precio_unitario = $2 WHERE id_producto = $1`, p.IDProducto, p.PrecioNuevo); err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO historial_precios (id_producto, precio_anterior, precio_nuevo, usuario, motivo, id_programado) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id_historial, fecha_cambio`
+	err = tx.QueryRow(ctx, query, cambio.IDProducto, cambio.PrecioAnterior, cambio.PrecioNuevo, cambio.Usuario, cambio.Motivo, cambio.IDProgramado).Scan(&cambio.ID, &cambio.FechaCambio)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return cambio, nil
+}
+
+// condicionesActualizacionMasiva filtra los productos activos que cumplen todos los
+// criterios de la actualización
+func condicionesActualizacionMasiva(a *domain.ActualizacionMasivaPrecios) *condiciones {
+	cond := &condiciones{}
+	cond.agregar("p.activo = ?", true)
+	if a.IDCategoria != nil {
+		cond.agregar("p.id_categoria IN "+subcategorias, *a.IDCategoria)
+	}
+	if a.Proveedor != "" {
+		cond.agregar("EXISTS (SELECT 1 FROM entradas_productos ep WHERE ep.id_producto = p.id_producto AND UPPER(ep.proveedor) = UPPER(?))", a.Proveedor)
+	}
+	if len(a.IDsProductos) > 0 {
+		cond.agregar("p.id_producto = ANY(?)", a.IDsProductos)
+	}
+	return cond
+}
+
+// productosActualizacionMasiva retorna los productos alcanzados con su precio actual,
+// ordenados por código; con bloquear, los bloquea hasta el fin de la transacción. Se
+// bloquean en orden de ID, como las demás actualizaciones, para no cruzarse con otra
+// transacción que bloquee los mismos productos.
+func productosActualizacionMasiva(ctx context.Context, db interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, a *domain.ActualizacionMasivaPrecios, bloquear bool) ([]domain.CambioMasivoPrecio, error) {
+	cond := condicionesActualizacionMasiva(a)
+	query := `SELECT p.id_producto, p.codigo, p.nombre, p.precio_unitario FROM productos p` + cond.where() + ` ORDER BY p.id_producto`
+	if bloquear {
+		query += " FOR UPDATE"
+	}
+	rows, err := db.Query(ctx, query, cond.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var productos []domain.CambioMasivoPrecio
+	for rows.Next() {
+		var c domain.CambioMasivoPrecio
+		if err := rows.Scan(&c.IDProducto, &c.Codigo, &c.Nombre, &c.PrecioAnterior); err != nil {
+			return nil, err
+		}
+		productos = append(productos, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(productos, func(i, j int) bool { return productos[i].Codigo < productos[j].Codigo })
+	return productos, nil
+}
+
+func (r *precioRepository) GetParaActualizacionMasiva(a *domain.ActualizacionMasivaPrecios) ([]domain.CambioMasivoPrecio, error) {
+	return productosActualizacionMasiva(context.Background(), r.db.Pool, a, false)
+}
+
+// AplicarActualizacionMasiva bloquea los productos alcanzados, recalcula el precio
+// nuevo desde el precio bloqueado y registra cada cambio en el historial, todo en una
+// sola transacción. Los productos cuyo precio no cambia se omiten.
+func (r *precioRepository) AplicarActualizacionMasiva(a *domain.ActualizacionMasivaPrecios) ([]domain.CambioMasivoPrecio, error) {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	productos, err := productosActualizacionMasiva(ctx, tx, a, true)
+	if err != nil {
+		return nil, err
+	}
+	var cambios []domain.CambioMasivoPrecio
+	for _, c := range productos {
+		c.PrecioNuevo = a.PrecioNuevo(c.PrecioAnterior)
+		if c.PrecioNuevo == c.PrecioAnterior {
+			continue
+		}
+		if c.PrecioNuevo < 0 {
+			return nil, &domain.ErrValidation{Field: "valor", Message: fmt.Sprintf("el precio de %s quedaría negativo", c.Codigo)}
+		}
+		if _, err := tx.Exec(ctx, `UPDATE productos SET precio_unitario = $2 WHERE id_producto = $1`, c.IDProducto, c.PrecioNuevo); err != nil {
+			return nil, err
+		}
+		query := `INSERT INTO historial_precios (id_producto, precio_anterior, precio_nuevo, usuario, motivo) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.Exec(ctx, query, c.IDProducto, c.PrecioAnterior, c.PrecioNuevo, a.Usuario, a.Motivo); err != nil {
+			return nil, err
+		}
+		cambios = append(cambios, c)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return cambios, nil
+}
@@ -3,8 +3,8 @@ package persistence
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/database"
@@ -19,19 +19,30 @@ func NewProductoRepository(db *database.Database) domain.ProductoRepository {
 	return &productoRepository{db: db}
 }
 
-const productoSelect = `SELECT id_producto, codigo, nombre, id_categoria, unidad_medida, precio_unitario, stock_actual, stock_inicial, fecha_creacion, fecha_actualizacion FROM productos`
+const productoColumnas = `p.id_producto, p.codigo, p.nombre, p.id_categoria, p.unidad_medida, p.precio_unitario, ` + stockProducto + `, p.stock_inicial, COALESCE(p.clase_abc, ''), p.tipo, p.id_producto_padre, p.atributos, p.afectacion_igv, p.tasa_igv, p.activo, p.fecha_baja, p.fecha_creacion, p.fecha_actualizacion`
+
+// stockProducto calcula el stock de un kit como los kits completos que alcanzan con sus componentes
+const stockProducto = `CASE WHEN p.tipo = 'KIT' THEN (
+	SELECT COALESCE(MIN(GREATEST(pc.stock_actual, 0) / kc.cantidad), 0)
+	FROM kit_componentes kc JOIN productos pc ON pc.id_producto = kc.id_componente
+	WHERE kc.id_kit = p.id_producto) ELSE p.stock_actual END`
+
+const productoSelect = `SELECT ` + productoColumnas + ` FROM productos p`
+
+// sinVariantes excluye a los padres con variantes: su stock está repartido en ellas
+const sinVariantes = `NOT EXISTS (SELECT 1 FROM productos v WHERE v.id_producto_padre = p.id_producto)`
+
+// conStockPropio deja solo los productos que llevan stock en su fila: excluye los
+// padres con variantes y los kits, cuyo stock sale de los componentes
+const conStockPropio = `p.tipo <> 'KIT' AND ` + sinVariantes
 
 func scanProducto(row interface{ Scan(dest ...any) error }) (domain.Producto, error) {
 	var p domain.Producto
-	err := row.Scan(&p.ID, &p.Codigo, &p.Nombre, &p.IDCategoria, &p.UnidadMedida, &p.PrecioUnitario, &p.StockActual, &p.StockInicial, &p.FechaCreacion, &p.FechaActualizacion)
+	err := row.Scan(&p.ID, &p.Codigo, &p.Nombre, &p.IDCategoria, &p.UnidadMedida, &p.PrecioUnitario, &p.StockActual, &p.StockInicial, &p.ClaseABC, &p.Tipo, &p.IDProductoPadre, &p.Atributos, &p.AfectacionIGV, &p.TasaIGV, &p.Activo, &p.FechaBaja, &p.FechaCreacion, &p.FechaActualizacion)
 	return p, err
 }
 
-func (r *productoRepository) GetAll() ([]domain.Producto, error) {
-	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" ORDER BY nombre")
-	if err != nil {
-		return nil, err
-	}
+func scanProductos(rows pgx.Rows) ([]domain.Producto, error) {
 	defer rows.Close()
 	var productos []domain.Producto
 	for rows.Next() {
@@ -41,11 +52,47 @@ func (r *productoRepository) GetAll() ([]domain.Producto, error) {
 		}
 		productos = append(productos, p)
 	}
-	return productos, nil
+	return productos, rows.Err()
+}
+
+var columnasOrdenProductos = map[string]string{
+	"nombre":         "nombre",
+	"codigo":         "codigo",
+	"precio":         "precio_unitario",
+	"stock":          "stock_actual",
+	"fecha_creacion": "fecha_creacion",
+}
+
+func (r *productoRepository) GetAll(filtro domain.FiltroProductos) ([]domain.Producto, int, error) {
+	var cond condiciones
+	if !filtro.IncluirInactivos {
+		cond.agregar("activo = ?", true)
+	}
+	if filtro.IDCategoria != nil {
+		cond.agregar("id_categoria IN "+subcategorias, *filtro.IDCategoria)
+	}
+	if filtro.ClaseABC != "" {
+		cond.agregar("clase_abc = ?", filtro.ClaseABC)
+	}
+	if filtro.IDProductoPadre != nil {
+		cond.agregar("id_producto_padre = ?", *filtro.IDProductoPadre)
+	}
+	var total int
+	if err := r.db.Pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM productos p"+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	limite, args := cond.paginar(filtro.Paginacion)
+	query := productoSelect + cond.where() + ordenarPor(filtro.Orden, columnasOrdenProductos, "nombre, id_producto") + limite
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	productos, err := scanProductos(rows)
+	return productos, total, err
 }
 
 func (r *productoRepository) GetByID(id int) (*domain.Producto, error) {
-	row := r.db.Pool.QueryRow(context.Background(), productoSelect+" WHERE id_producto = $1", id)
+	row := r.db.Pool.QueryRow(context.Background(), productoSelect+" WHERE p.id_producto = $1", id)
 	p, err := scanProducto(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -57,7 +104,7 @@ func (r *productoRepository) GetByID(id int) (*domain.Producto, error) {
 }
 
 func (r *productoRepository) GetByCodigo(codigo string) (*domain.Producto, error) {
-	row := r.db.Pool.QueryRow(context.Background(), productoSelect+" WHERE codigo = $1", codigo)
+	row := r.db.Pool.QueryRow(context.Background(), productoSelect+" WHERE p.codigo = $1", codigo)
 	p, err := scanProducto(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -68,69 +115,438 @@ func (r *productoRepository) GetByCodigo(codigo string) (*domain.Producto, error
 	return &p, nil
 }
 
+// GetByCodigos retorna los productos con esos códigos, activos o no, indexados por código
+func (r *productoRepository) GetByCodigos(codigos []string) (map[string]domain.Producto, error) {
+	productos := make(map[string]domain.Producto)
+	if len(codigos) == 0 {
+		return productos, nil
+	}
+	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" WHERE p.codigo = ANY($1)", codigos)
+	if err != nil {
+		return nil, err
+	}
+	encontrados, err := scanProductos(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range encontrados {
+		productos[p.Codigo] = p
+	}
+	return productos, nil
+}
+
+// GetByCodigoBarras busca por cualquiera de los códigos de barras del producto,
+// aceptando un UPC-A leído como EAN-13 y viceversa
+func (r *productoRepository) GetByCodigoBarras(codigo string) (*domain.Producto, error) {
+	query := `SELECT ` + productoColumnas + `
+	FROM codigos_barras cb JOIN productos p ON p.id_producto = cb.id_producto
+	WHERE cb.codigo = ANY($1) LIMIT 1`
+	row := r.db.Pool.QueryRow(context.Background(), query, domain.VariantesCodigoBarras(codigo))
+	p, err := scanProducto(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "producto con código de barras", ID: codigo}
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
 func (r *productoRepository) Create(producto *domain.Producto) error {
-	query := `INSERT INTO productos (codigo, nombre, id_categoria, unidad_medida, precio_unitario, stock_actual, stock_inicial) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id_producto, fecha_creacion, fecha_actualizacion`
-	return r.db.Pool.QueryRow(context.Background(), query, producto.Codigo, producto.Nombre, producto.IDCategoria, producto.UnidadMedida, producto.PrecioUnitario, producto.StockActual, producto.StockInicial).Scan(&producto.ID, &producto.FechaCreacion, &producto.FechaActualizacion)
+	query := `INSERT INTO productos (codigo, nombre, id_categoria, unidad_medida, precio_unitario, stock_actual, stock_inicial, tipo, id_producto_padre, atributos, afectacion_igv, tasa_igv) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id_producto, activo, fecha_creacion, fecha_actualizacion`
+	return r.db.Pool.QueryRow(context.Background(), query, producto.Codigo, producto.Nombre, producto.IDCategoria, producto.UnidadMedida, producto.PrecioUnitario, producto.StockActual, producto.StockInicial, producto.Tipo, producto.IDProductoPadre, atributosJSON(producto.Atributos), producto.AfectacionIGV, producto.TasaIGV).Scan(&producto.ID, &producto.Activo, &producto.FechaCreacion, &producto.FechaActualizacion)
 }
 
 func (r *productoRepository) Update(producto *domain.Producto) error {
-	query := `UPDATE productos SET codigo = $2, nombre = $3, id_categoria = $4, unidad_medida = $5, precio_unitario = $6, stock_actual = $7, stock_inicial = $8 WHERE id_producto = $1 RETURNING fecha_actualizacion`
-	err := r.db.Pool.QueryRow(context.Background(), query, producto.ID, producto.Codigo, producto.Nombre, producto.IDCategoria, producto.UnidadMedida, producto.PrecioUnitario, producto.StockActual, producto.StockInicial).Scan(&producto.FechaActualizacion)
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE productos SET codigo = $2, nombre = $3, id_categoria = $4, unidad_medida = $5, precio_unitario = $6, stock_actual = $7, stock_inicial = $8, atributos = $9, afectacion_igv = $10, tasa_igv = $11 WHERE id_producto = $1 RETURNING fecha_actualizacion`
+	err = tx.QueryRow(ctx, query, producto.ID, producto.Codigo, producto.Nombre, producto.IDCategoria, producto.UnidadMedida, producto.PrecioUnitario, producto.StockActual, producto.StockInicial, atributosJSON(producto.Atributos), producto.AfectacionIGV, producto.TasaIGV).Scan(&producto.FechaActualizacion)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &domain.ErrNotFound{Entity: "producto", ID: producto.ID}
 		}
 		return err
 	}
-	return nil
+	if err := moverVariantes(ctx, tx, producto); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *productoRepository) Delete(id int) error {
-	result, err := r.db.Pool.Exec(context.Background(), `DELETE FROM productos WHERE id_producto = $1`, id)
+// moverVariantes deja las variantes en la categoría de su padre: la heredan al crearse
+// y deben seguirlo cuando el padre cambia de categoría
+func moverVariantes(ctx context.Context, tx pgx.Tx, padre *domain.Producto) error {
+	_, err := tx.Exec(ctx, `UPDATE productos SET id_categoria = $2 WHERE id_producto_padre = $1 AND id_categoria IS DISTINCT FROM $2`, padre.ID, padre.IDCategoria)
+	return err
+}
+
+// atributosJSON evita guardar NULL en la columna jsonb cuando no hay atributos
+func atributosJSON(atributos map[string]string) map[string]string {
+	if atributos == nil {
+		return map[string]string{}
+	}
+	return atributos
+}
+
+const motivoEdicion = "Actualización manual del producto"
+
+// Editar compara con el stock y el precio leídos con la fila bloqueada, no con los que
+// se leyeron antes de la transacción
+func (r *productoRepository) Editar(producto *domain.Producto, usuario string) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if result.RowsAffected() == 0 {
-		return &domain.ErrNotFound{Entity: "producto", ID: id}
+	defer tx.Rollback(ctx)
+
+	var stockAnterior int
+	var precioAnterior float64
+	err = tx.QueryRow(ctx, `SELECT stock_actual, precio_unitario FROM productos WHERE id_producto = $1 FOR UPDATE`, producto.ID).Scan(&stockAnterior, &precioAnterior)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &domain.ErrNotFound{Entity: "producto", ID: producto.ID}
+		}
+		return err
 	}
-	return nil
+	query := `UPDATE productos SET codigo = $2, nombre = $3, id_categoria = $4, unidad_medida = $5, precio_unitario = $6, stock_actual = $7, stock_inicial = $8, atributos = $9, afectacion_igv = $10, tasa_igv = $11 WHERE id_producto = $1 RETURNING fecha_actualizacion`
+	err = tx.QueryRow(ctx, query, producto.ID, producto.Codigo, producto.Nombre, producto.IDCategoria, producto.UnidadMedida, producto.PrecioUnitario, producto.StockActual, producto.StockInicial, atributosJSON(producto.Atributos), producto.AfectacionIGV, producto.TasaIGV).Scan(&producto.FechaActualizacion)
+	if err != nil {
+		return err
+	}
+	if err := moverVariantes(ctx, tx, producto); err != nil {
+		return err
+	}
+	// Los cambios manuales de stock quedan registrados para la matriz mensual
+	if producto.StockActual != stockAnterior {
+		_, err = tx.Exec(ctx, `INSERT INTO ajustes_stock (id_producto, fecha, cantidad, stock_anterior, stock_nuevo, motivo) VALUES ($1, $2, $3, $4, $5, $6)`, producto.ID, time.Now(), producto.StockActual-stockAnterior, stockAnterior, producto.StockActual, motivoEdicion)
+		if err != nil {
+			return err
+		}
+	}
+	if producto.PrecioUnitario != precioAnterior {
+		_, err = tx.Exec(ctx, `INSERT INTO historial_precios (id_producto, precio_anterior, precio_nuevo, usuario, motivo) VALUES ($1, $2, $3, $4, $5)`, producto.ID, precioAnterior, producto.PrecioUnitario, usuario, motivoEdicion)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *productoRepository) GetStockBajo(limite int) ([]domain.Producto, error) {
-	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" WHERE stock_actual <= $1 ORDER BY stock_actual ASC", limite)
+func (r *productoRepository) GetVariantes(padreID int) ([]domain.Producto, error) {
+	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" WHERE p.id_producto_padre = $1 ORDER BY p.codigo", padreID)
+	if err != nil {
+		return nil, err
+	}
+	return scanProductos(rows)
+}
+
+// GetPadresConVariantes retorna cuáles de los productos 'ids' tienen variantes
+func (r *productoRepository) GetPadresConVariantes(ids []int) (map[int]bool, error) {
+	padres := make(map[int]bool)
+	if len(ids) == 0 {
+		return padres, nil
+	}
+	rows, err := r.db.Pool.Query(context.Background(), `SELECT DISTINCT id_producto_padre FROM productos WHERE id_producto_padre = ANY($1)`, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var productos []domain.Producto
 	for rows.Next() {
-		p, err := scanProducto(rows)
-		if err != nil {
+		var id int
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		productos = append(productos, p)
+		padres[id] = true
 	}
-	return productos, nil
+	return padres, rows.Err()
 }
 
-func (r *productoRepository) Search(termino string) ([]domain.Producto, error) {
+// Delete marca la baja del padre y de sus variantes activas con la misma fecha, que es
+// lo que usa Restaurar para reactivarlas juntas
+func (r *productoRepository) Delete(id int) error {
+	query := `UPDATE productos SET activo = FALSE, fecha_baja = CURRENT_TIMESTAMP WHERE (id_producto = $1 OR id_producto_padre = $1) AND activo`
+	result, err := r.db.Pool.Exec(context.Background(), query, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "producto", ID: id}
+	}
+	return nil
+}
+
+func (r *productoRepository) Restaurar(id int) error {
+	query := `
+		UPDATE productos p SET activo = TRUE, fecha_baja = NULL
+		FROM productos b
+		WHERE b.id_producto = $1 AND NOT b.activo
+		  AND (p.id_producto = b.id_producto OR (p.id_producto_padre = b.id_producto AND p.fecha_baja = b.fecha_baja))`
+	result, err := r.db.Pool.Exec(context.Background(), query, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "producto", ID: id}
+	}
+	return nil
+}
+
+func (r *productoRepository) GetStockBajo(limite int) ([]domain.Producto, error) {
+	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" WHERE p.activo AND stock_actual <= $1 AND "+conStockPropio+" ORDER BY stock_actual ASC", limite)
+	if err != nil {
+		return nil, err
+	}
+	return scanProductos(rows)
+}
+
+// busquedaQuery ordena primero el código exacto, luego el prefijo de código y después
+// la similitud por trigramas del nombre y la categoría, sin distinguir tildes. Las
+// variantes también se encuentran por el nombre del padre y sus atributos ("polo rojo").
+// $1 es el término ya normalizado en minúsculas; $5 incluye los productos inactivos.
+//
+// Los candidatos salen de una unión de consultas sobre una sola tabla cada una, para que
+// usen los índices de trigramas (un OR entre tablas unidas obliga a recorrer todo el
+// catálogo); el filtro y el orden completos se aplican solo a esos candidatos.
+const busquedaQuery = `
+	WITH candidatos AS (
+		SELECT id_producto FROM productos WHERE lower(codigo) LIKE $2
+		UNION
+		SELECT id_producto FROM productos
+		WHERE f_unaccent(lower(nombre)) LIKE f_unaccent($3) OR f_unaccent(lower(nombre)) %> f_unaccent($1)
+		UNION
+		SELECT id_producto FROM productos
+		WHERE f_unaccent(lower(atributos::text)) LIKE f_unaccent($3) OR f_unaccent(lower(atributos::text)) %> f_unaccent($1)
+		UNION
+		-- Padres cuyo nombre contiene el término o está contenido en él ("polo" en "polo rojo")
+		SELECT v.id_producto FROM productos pp JOIN productos v ON v.id_producto_padre = pp.id_producto
+		WHERE f_unaccent(lower(pp.nombre)) LIKE f_unaccent($3)
+		   OR f_unaccent(lower(pp.nombre)) %> f_unaccent($1)
+		   OR f_unaccent(lower(pp.nombre)) <% f_unaccent($1)
+		UNION
+		SELECT p.id_producto FROM categorias c JOIN productos p ON p.id_categoria = c.id_categoria
+		WHERE f_unaccent(lower(c.nombre)) %> f_unaccent($1)
+	)
+	SELECT ` + productoColumnas + `
+	FROM candidatos k
+	JOIN productos p ON p.id_producto = k.id_producto
+	LEFT JOIN categorias c ON p.id_categoria = c.id_categoria
+	LEFT JOIN productos pp ON pp.id_producto = p.id_producto_padre
+	WHERE (p.activo OR $5)
+	  AND (lower(p.codigo) LIKE $2
+	   OR f_unaccent(lower(p.nombre)) LIKE f_unaccent($3)
+	   OR f_unaccent(lower(pp.nombre || ' ' || ` + atributosTexto + `)) LIKE f_unaccent($3)
+	   OR f_unaccent($1) <% f_unaccent(lower(p.nombre))
+	   OR f_unaccent($1) <% f_unaccent(lower(pp.nombre || ' ' || ` + atributosTexto + `))
+	   OR f_unaccent($1) <% f_unaccent(lower(c.nombre)))
+	ORDER BY lower(p.codigo) = $1 DESC,
+	         lower(p.codigo) LIKE $2 DESC,
+	         GREATEST(word_similarity(f_unaccent($1), f_unaccent(lower(p.nombre))),
+	                  word_similarity(f_unaccent($1), f_unaccent(lower(COALESCE(pp.nombre || ' ' || ` + atributosTexto + `, '')))),
+	                  word_similarity(f_unaccent($1), f_unaccent(lower(COALESCE(c.nombre, '')))) * 0.8) DESC,
+	         p.id_producto_padre IS NOT NULL,
+	         p.nombre
+	LIMIT $4`
+
+// atributosTexto une los valores de los atributos de la variante ("M Rojo")
+const atributosTexto = `(SELECT COALESCE(string_agg(value, ' '), '') FROM jsonb_each_text(p.atributos))`
+
+func (r *productoRepository) Search(termino string, limite int, incluirInactivos bool) ([]domain.Producto, error) {
 	termino = strings.ToLower(strings.TrimSpace(termino))
 	if termino == "" {
-		return r.GetAll()
+		productos, _, err := r.GetAll(domain.FiltroProductos{IncluirInactivos: incluirInactivos, Paginacion: domain.Paginacion{Pagina: 1, Limite: limite}})
+		return productos, err
 	}
-	searchTerm := fmt.Sprintf("%%%s%%", termino)
-	rows, err := r.db.Pool.Query(context.Background(), productoSelect+" WHERE LOWER(codigo) LIKE $1 OR LOWER(nombre) LIKE $1 ORDER BY nombre", searchTerm)
+	escapado := escaparLike(termino)
+	rows, err := r.db.Pool.Query(context.Background(), busquedaQuery, termino, escapado+"%", "%"+escapado+"%", limite, incluirInactivos)
+	if err != nil {
+		return nil, err
+	}
+	return scanProductos(rows)
+}
+
+// escaparLike evita que % y _ del término actúen como comodines
+func escaparLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ActualizarClasesABC guarda la clase ABC de cada producto en una sola sentencia
+func (r *productoRepository) ActualizarClasesABC(clases map[int]string) error {
+	ids := make([]int, 0, len(clases))
+	valores := make([]string, 0, len(clases))
+	for id, clase := range clases {
+		ids = append(ids, id)
+		valores = append(valores, clase)
+	}
+	query := `UPDATE productos p SET clase_abc = v.clase FROM unnest($1::int[], $2::text[]) AS v(id, clase) WHERE p.id_producto = v.id`
+	_, err := r.db.Pool.Exec(context.Background(), query, ids, valores)
+	return err
+}
+
+func (r *productoRepository) TieneMovimientos(id int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM entradas_productos WHERE id_producto = $1)
+		OR EXISTS (SELECT 1 FROM salidas_productos WHERE id_producto = $1)
+		OR EXISTS (SELECT 1 FROM ajustes_stock WHERE id_producto = $1)`
+	var tiene bool
+	err := r.db.Pool.QueryRow(context.Background(), query, id).Scan(&tiene)
+	return tiene, err
+}
+
+func (r *productoRepository) GetComponentes(kitID int) ([]domain.ComponenteKit, error) {
+	query := `SELECT kc.id_kit, kc.id_componente, kc.cantidad, p.codigo, p.nombre, p.stock_actual FROM kit_componentes kc JOIN productos p ON p.id_producto = kc.id_componente WHERE kc.id_kit = $1 ORDER BY p.nombre`
+	rows, err := r.db.Pool.Query(context.Background(), query, kitID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var productos []domain.Producto
+	var componentes []domain.ComponenteKit
 	for rows.Next() {
-		p, err := scanProducto(rows)
-		if err != nil {
+		var c domain.ComponenteKit
+		if err := rows.Scan(&c.IDKit, &c.IDComponente, &c.Cantidad, &c.Codigo, &c.Nombre, &c.StockActual); err != nil {
 			return nil, err
 		}
-		productos = append(productos, p)
+		componentes = append(componentes, c)
 	}
-	return productos, nil
+	return componentes, rows.Err()
+}
+
+// SetComponentes reemplaza la lista de materiales del kit en una transacción
+func (r *productoRepository) SetComponentes(kitID int, componentes []domain.ComponenteKit) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `DELETE FROM kit_componentes WHERE id_kit = $1`, kitID); err != nil {
+		return err
+	}
+	for _, c := range componentes {
+		if _, err := tx.Exec(ctx, `INSERT INTO kit_componentes (id_kit, id_componente, cantidad) VALUES ($1, $2, $3)`, kitID, c.IDComponente, c.Cantidad); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+const motivoImportacion = "Importación masiva de productos"
+
+func (r *productoRepository) Importar(importacion *domain.ImportacionProductos) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	idsCategorias := make(map[string]int, len(importacion.Categorias))
+	for _, c := range importacion.Categorias {
+		idPadre := c.IDPadre
+		if c.RutaPadre != "" {
+			id := idsCategorias[c.RutaPadre]
+			idPadre = &id
+		}
+		var id int
+		if err := tx.QueryRow(ctx, `INSERT INTO categorias (nombre, id_categoria_padre) VALUES ($1, $2) RETURNING id_categoria`, c.Nombre, idPadre).Scan(&id); err != nil {
+			return err
+		}
+		idsCategorias[c.Ruta] = id
+	}
+
+	// Los productos a actualizar se bloquean de una vez y en orden de ID, como en las
+	// demás actualizaciones masivas, para no cruzarse con otra transacción
+	var ids []int
+	for _, item := range importacion.Productos {
+		if item.Producto.ID != 0 {
+			ids = append(ids, item.Producto.ID)
+		}
+	}
+	type anterior struct {
+		stock  int
+		precio float64
+	}
+	anteriores := make(map[int]anterior, len(ids))
+	if len(ids) > 0 {
+		rows, err := tx.Query(ctx, `SELECT id_producto, stock_actual, precio_unitario FROM productos WHERE id_producto = ANY($1) ORDER BY id_producto FOR UPDATE`, ids)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id int
+			var a anterior
+			if err := rows.Scan(&id, &a.stock, &a.precio); err != nil {
+				rows.Close()
+				return err
+			}
+			anteriores[id] = a
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	hoy := time.Now()
+	for i := range importacion.Productos {
+		item := &importacion.Productos[i]
+		p := &item.Producto
+		if item.RutaCategoria != "" {
+			id := idsCategorias[item.RutaCategoria]
+			p.IDCategoria = &id
+		}
+		if p.ID == 0 {
+			query := `INSERT INTO productos (codigo, nombre, id_categoria, unidad_medida, precio_unitario, stock_actual, stock_inicial, tipo, afectacion_igv, tasa_igv) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id_producto, activo, fecha_creacion, fecha_actualizacion`
+			err := tx.QueryRow(ctx, query, p.Codigo, p.Nombre, p.IDCategoria, p.UnidadMedida, p.PrecioUnitario, p.StockActual, p.StockInicial, p.Tipo, p.AfectacionIGV, p.TasaIGV).
+				Scan(&p.ID, &p.Activo, &p.FechaCreacion, &p.FechaActualizacion)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// El stock y el precio anteriores se leyeron con la fila bloqueada
+		a, ok := anteriores[p.ID]
+		if !ok {
+			return &domain.ErrNotFound{Entity: "producto", ID: p.ID}
+		}
+		stockAnterior, precioAnterior := a.stock, a.precio
+		if !item.ActualizarStock {
+			p.StockActual = stockAnterior
+		}
+		if !item.ActualizarPrecio {
+			p.PrecioUnitario = precioAnterior
+		}
+		// Una variante toma la categoría de su padre, que otra fila pudo cambiar antes en
+		// esta misma transacción
+		query := `UPDATE productos SET nombre = $2,
+			id_categoria = CASE WHEN id_producto_padre IS NULL THEN $3 ELSE (SELECT pp.id_categoria FROM productos pp WHERE pp.id_producto = productos.id_producto_padre) END,
+			unidad_medida = $4, precio_unitario = $5, stock_actual = $6, stock_inicial = $7, afectacion_igv = $8, tasa_igv = $9
+			WHERE id_producto = $1 RETURNING id_categoria, fecha_actualizacion`
+		err := tx.QueryRow(ctx, query, p.ID, p.Nombre, p.IDCategoria, p.UnidadMedida, p.PrecioUnitario, p.StockActual, p.StockInicial, p.AfectacionIGV, p.TasaIGV).Scan(&p.IDCategoria, &p.FechaActualizacion)
+		if err != nil {
+			return err
+		}
+		if err := moverVariantes(ctx, tx, p); err != nil {
+			return err
+		}
+		if p.StockActual != stockAnterior {
+			_, err = tx.Exec(ctx, `INSERT INTO ajustes_stock (id_producto, fecha, cantidad, stock_anterior, stock_nuevo, motivo) VALUES ($1, $2, $3, $4, $5, $6)`, p.ID, hoy, p.StockActual-stockAnterior, stockAnterior, p.StockActual, motivoImportacion)
+			if err != nil {
+				return err
+			}
+		}
+		if p.PrecioUnitario != precioAnterior {
+			_, err = tx.Exec(ctx, `INSERT INTO historial_precios (id_producto, precio_anterior, precio_nuevo, usuario, motivo) VALUES ($1, $2, $3, $4, $5)`, p.ID, precioAnterior, p.PrecioUnitario, importacion.Usuario, motivoImportacion)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type promocionRepository struct {
+	db *database.Database
+}
+
+func NewPromocionRepository(db *database.Database) domain.PromocionRepository {
+	return &promocionRepository{db: db}
+}
+
+const promocionSelect = `SELECT id_promocion, nombre, tipo, id_producto, id_categoria, valor, COALESCE(cantidad_lleva, 0), COALESCE(cantidad_paga, 0), fecha_inicio, fecha_fin, COALESCE(TO_CHAR(hora_inicio, 'HH24:MI'), ''), COALESCE(TO_CHAR(hora_fin, 'HH24:MI'), ''), dias_semana, lugar_venta, activa, fecha_creacion FROM promociones`
+
+func scanPromocion(row interface{ Scan(dest ...any) error }) (domain.Promocion, error) {
+	var p domain.Promocion
+	var dias []int32
+	err := row.Scan(&p.ID, &p.Nombre, &p.Tipo, &p.IDProducto, &p.IDCategoria, &p.Valor, &p.CantidadLleva, &p.CantidadPaga, &p.FechaInicio, &p.FechaFin, &p.HoraInicio, &p.HoraFin, &dias, &p.LugarVenta, &p.Activa, &p.FechaCreacion)
+	for _, d := range dias {
+		p.DiasSemana = append(p.DiasSemana, int(d))
+	}
+	return p, err
+}
+
+func (r *promocionRepository) query(query string, args ...any) ([]domain.Promocion, error) {
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var promociones []domain.Promocion
+	for rows.Next() {
+		p, err := scanPromocion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promociones = append(promociones, p)
+	}
+	return promociones, nil
+}
+
+func (r *promocionRepository) GetAll(soloActivas bool) ([]domain.Promocion, error) {
+	if soloActivas {
+		return r.query(promocionSelect + " WHERE activa ORDER BY nombre")
+	}
+	return r.query(promocionSelect + " ORDER BY activa DESC, nombre")
+}
+
+func (r *promocionRepository) GetByID(id int) (*domain.Promocion, error) {
+	p, err := scanPromocion(r.db.Pool.QueryRow(context.Background(), promocionSelect+" WHERE id_promocion = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "promoción", ID: id}
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *promocionRepository) GetVigentes(fecha time.Time) ([]domain.Promocion, error) {
+	return r.query(promocionSelect+" WHERE activa AND (fecha_inicio IS NULL OR fecha_inicio <= $1::date) AND (fecha_fin IS NULL OR fecha_fin >= $1::date)", fecha.Format("2006-01-02"))
+}
+
+// horaOpcional convierte "" en NULL para las columnas TIME
+func horaOpcional(h string) *string {
+	if h == "" {
+		return nil
+	}
+	return &h
+}
+
+func (r *promocionRepository) Create(p *domain.Promocion) error {
+	query := `INSERT INTO promociones (nombre, tipo, id_producto, id_categoria, valor, cantidad_lleva, cantidad_paga, fecha_inicio, fecha_fin, hora_inicio, hora_fin, dias_semana, lugar_venta, activa) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, $10::time, $11::time, $12, $13, $14) RETURNING id_promocion, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, p.Nombre, p.Tipo, p.IDProducto, p.IDCategoria, p.Valor, p.CantidadLleva, p.CantidadPaga, p.FechaInicio, p.FechaFin, horaOpcional(p.HoraInicio), horaOpcional(p.HoraFin), diasSemana(p.DiasSemana), p.LugarVenta, p.Activa).Scan(&p.ID, &p.FechaCreacion)
+}
+
+func (r *promocionRepository) Update(p *domain.Promocion) error {
+	query := `UPDATE promociones SET nombre = $2, tipo = $3, id_producto = $4, id_categoria = $5, valor = $6, cantidad_lleva = NULLIF($7, 0), cantidad_paga = $8, fecha_inicio = $9, fecha_fin = $10, hora_inicio = $11::time, hora_fin = $12::time, dias_semana = $13, lugar_venta = $14, activa = $15 WHERE id_promocion = $1`
+	result, err := r.db.Pool.Exec(context.Background(), query, p.ID, p.Nombre, p.Tipo, p.IDProducto, p.IDCategoria, p.Valor, p.CantidadLleva, p.CantidadPaga, p.FechaInicio, p.FechaFin, horaOpcional(p.HoraInicio), horaOpcional(p.HoraFin), diasSemana(p.DiasSemana), p.LugarVenta, p.Activa)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "promoción", ID: p.ID}
+	}
+	return nil
+}
+
+func diasSemana(dias []int) []int32 {
+	resultado := make([]int32, len(dias))
+	for i, d := range dias {
+		resultado[i] = int32(d)
+	}
+	return resultado
+}
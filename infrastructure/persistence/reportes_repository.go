@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/Mishka-GDI-Back/domain"
 	"github.com/Mishka-GDI-Back/infrastructure/database"
@@ -15,8 +16,19 @@ func NewReportesRepository(db *database.Database) domain.ReportesRepository {
 	return &reportesRepository{db: db}
 }
 
+// productosAgrupados une cada producto con su grupo g: el padre en las variantes y el
+// mismo producto en los demás, para que los reportes sumen las variantes en el padre
+const productosAgrupados = `productos p JOIN productos g ON g.id_producto = COALESCE(p.id_producto_padre, p.id_producto)`
+
+// salidasConKits son las unidades que salen de cada producto por venta: las vendidas
+// directamente y, como componente, las de los kits vendidos (cantidad del kit por la
+// del componente)
+const salidasConKits = `(SELECT id_producto, fecha_salida, cantidad FROM salidas_productos
+	UNION ALL
+	SELECT kc.id_componente, s.fecha_salida, s.cantidad * kc.cantidad FROM salidas_productos s JOIN kit_componentes kc ON kc.id_kit = s.id_producto)`
+
 func (r *reportesRepository) GetInventarioActual() ([]domain.ReporteInventarioItem, error) {
-	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre, 'SIN CATEGORIA'), p.unidad_medida, p.precio_unitario, p.stock_actual, p.stock_actual * p.precio_unitario AS valor_total FROM productos p LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE p.stock_actual > 0 ORDER BY c.nombre, p.nombre`
+	query := `SELECT g.id_producto, g.codigo, g.nombre, COALESCE(c.nombre, 'SIN CATEGORIA'), g.unidad_medida, g.precio_unitario, SUM(p.stock_actual), SUM(p.stock_actual * p.precio_unitario) AS valor_total FROM ` + productosAgrupados + ` LEFT JOIN categorias c ON g.id_categoria = c.id_categoria GROUP BY g.id_producto, c.nombre HAVING SUM(p.stock_actual) > 0 ORDER BY c.nombre, g.nombre`
 	rows, err := r.db.Pool.Query(context.Background(), query)
 	if err != nil {
 		return nil, err
@@ -34,7 +46,7 @@ func (r *reportesRepository) GetInventarioActual() ([]domain.ReporteInventarioIt
 }
 
 func (r *reportesRepository) GetMovimientos(inicio, fin string) ([]domain.ReporteMovimiento, error) {
-	query := `SELECT ep.fecha_entrada, 'ENTRADA', p.codigo, p.nombre, COALESCE(c.nombre,''), ep.cantidad, COALESCE(ep.precio_unitario,0), ep.cantidad * COALESCE(ep.precio_unitario,0), '', '' FROM entradas_productos ep JOIN productos p ON ep.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE ep.fecha_entrada BETWEEN $1 AND $2 UNION ALL SELECT sp.fecha_salida, 'SALIDA', p.codigo, p.nombre, COALESCE(c.nombre,''), sp.cantidad, sp.precio_venta, sp.total, sp.lugar_venta, sp.tipo_pago FROM salidas_productos sp JOIN productos p ON sp.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE sp.fecha_salida BETWEEN $1 AND $2 ORDER BY 1 DESC`
+	query := `SELECT ep.fecha_entrada, 'ENTRADA', p.codigo, p.nombre, COALESCE(c.nombre,''), ep.cantidad, COALESCE(ep.precio_unitario,0), ep.base_imponible + ep.igv, '', '' FROM entradas_productos ep JOIN productos p ON ep.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE ep.fecha_entrada BETWEEN $1 AND $2 UNION ALL SELECT sp.fecha_salida, 'SALIDA', p.codigo, p.nombre, COALESCE(c.nombre,''), sp.cantidad, sp.precio_venta, sp.total, sp.lugar_venta, sp.tipo_pago FROM salidas_productos sp JOIN productos p ON sp.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE sp.fecha_salida BETWEEN $1 AND $2 ORDER BY 1 DESC`
 	rows, err := r.db.Pool.Query(context.Background(), query, inicio, fin)
 	if err != nil {
 		return nil, err
@@ -52,7 +64,7 @@ func (r *reportesRepository) GetMovimientos(inicio, fin string) ([]domain.Report
 }
 
 func (r *reportesRepository) GetProductosMasVendidos(limite int) ([]domain.ReporteProductoVendido, error) {
-	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre,''), SUM(sp.cantidad) AS total_vendido, SUM(sp.total) AS total_ingresos FROM salidas_productos sp JOIN productos p ON sp.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria GROUP BY p.id_producto, p.codigo, p.nombre, c.nombre ORDER BY total_vendido DESC LIMIT $1`
+	query := `SELECT g.id_producto, g.codigo, g.nombre, COALESCE(c.nombre,''), SUM(sp.cantidad) AS total_vendido, SUM(sp.total) AS total_ingresos FROM salidas_productos sp JOIN ` + productosAgrupados + ` ON sp.id_producto = p.id_producto LEFT JOIN categorias c ON g.id_categoria = c.id_categoria GROUP BY g.id_producto, c.nombre ORDER BY total_vendido DESC LIMIT $1`
 	rows, err := r.db.Pool.Query(context.Background(), query, limite)
 	if err != nil {
 		return nil, err
@@ -70,7 +82,7 @@ func (r *reportesRepository) GetProductosMasVendidos(limite int) ([]domain.Repor
 }
 
 func (r *reportesRepository) GetProductosMasIngresados(limite int) ([]domain.ReporteProductoVendido, error) {
-	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre,''), SUM(ep.cantidad) AS total_ingresado, SUM(ep.cantidad * COALESCE(ep.precio_unitario,0)) AS total_costo FROM entradas_productos ep JOIN productos p ON ep.id_producto = p.id_producto LEFT JOIN categorias c ON p.id_categoria = c.id_categoria GROUP BY p.id_producto, p.codigo, p.nombre, c.nombre ORDER BY total_ingresado DESC LIMIT $1`
+	query := `SELECT g.id_producto, g.codigo, g.nombre, COALESCE(c.nombre,''), SUM(ep.cantidad) AS total_ingresado, SUM(ep.base_imponible + ep.igv) AS total_costo FROM entradas_productos ep JOIN ` + productosAgrupados + ` ON ep.id_producto = p.id_producto LEFT JOIN categorias c ON g.id_categoria = c.id_categoria GROUP BY g.id_producto, c.nombre ORDER BY total_ingresado DESC LIMIT $1`
 	rows, err := r.db.Pool.Query(context.Background(), query, limite)
 	if err != nil {
 		return nil, err
@@ -88,7 +100,7 @@ func (r *reportesRepository) GetProductosMasIngresados(limite int) ([]domain.Rep
 }
 
 func (r *reportesRepository) GetValoracionInventario() ([]domain.ReporteValoracion, error) {
-	query := `SELECT c.id_categoria, COALESCE(c.nombre, 'SIN CATEGORIA'), COUNT(p.id_producto), COALESCE(SUM(p.stock_actual), 0), COALESCE(SUM(p.stock_actual * p.precio_unitario), 0) FROM categorias c LEFT JOIN productos p ON p.id_categoria = c.id_categoria GROUP BY c.id_categoria, c.nombre ORDER BY SUM(p.stock_actual * p.precio_unitario) DESC NULLS LAST`
+	query := `SELECT c.id_categoria, COALESCE(c.nombre, 'SIN CATEGORIA'), COUNT(DISTINCT COALESCE(p.id_producto_padre, p.id_producto)), COALESCE(SUM(p.stock_actual), 0), COALESCE(SUM(p.stock_actual * p.precio_unitario), 0) FROM categorias c LEFT JOIN (` + productosAgrupados + `) ON g.id_categoria = c.id_categoria GROUP BY c.id_categoria, c.nombre ORDER BY SUM(p.stock_actual * p.precio_unitario) DESC NULLS LAST`
 	rows, err := r.db.Pool.Query(context.Background(), query)
 	if err != nil {
 		return nil, err
@@ -104,3 +116,199 @@ func (r *reportesRepository) GetValoracionInventario() ([]domain.ReporteValoraci
 	}
 	return items, nil
 }
+
+// GetVentasPorVentana suma las unidades vendidas por producto en los últimos N días (hoy incluido) para cada N.
+// Las variantes se proyectan por separado; los padres con variantes y los kits no tienen stock propio.
+// Las ventas de kits cuentan como salida de sus componentes.
+func (r *reportesRepository) GetVentasPorVentana(dias []int) ([]domain.VentasProductoVentana, error) {
+	query := `SELECT p.id_producto, p.codigo, p.nombre, p.id_categoria, COALESCE(c.nombre, 'SIN CATEGORIA'), p.stock_actual, p.precio_unitario, w.dias, COALESCE(SUM(sp.cantidad), 0) FROM productos p CROSS JOIN unnest($1::int[]) AS w(dias) LEFT JOIN categorias c ON p.id_categoria = c.id_categoria LEFT JOIN ` + salidasConKits + ` sp ON sp.id_producto = p.id_producto AND sp.fecha_salida > CURRENT_DATE - w.dias AND sp.fecha_salida <= CURRENT_DATE WHERE p.activo AND ` + conStockPropio + ` GROUP BY p.id_producto, p.codigo, p.nombre, p.id_categoria, c.nombre, p.stock_actual, p.precio_unitario, w.dias ORDER BY p.id_producto, w.dias`
+	rows, err := r.db.Pool.Query(context.Background(), query, dias)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.VentasProductoVentana
+	for rows.Next() {
+		var item domain.VentasProductoVentana
+		var ventana, vendido int
+		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.IDCategoria, &item.Categoria, &item.StockActual, &item.PrecioUnitario, &ventana, &vendido); err != nil {
+			return nil, err
+		}
+		if n := len(items); n > 0 && items[n-1].IDProducto == item.IDProducto {
+			items[n-1].Vendido[ventana] = vendido
+			continue
+		}
+		item.Vendido = map[int]int{ventana: vendido}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetIngresosPorProducto retorna las ventas de todos los productos entre inicio y fin (inclusive),
+// incluidos los que no se vendieron, ordenadas por ingresos descendente
+func (r *reportesRepository) GetIngresosPorProducto(inicio, fin time.Time) ([]domain.ReporteProductoVendido, error) {
+	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre,''), COALESCE(SUM(sp.cantidad), 0) AS total_vendido, COALESCE(SUM(sp.total), 0) AS total_ingresos FROM productos p LEFT JOIN salidas_productos sp ON sp.id_producto = p.id_producto AND sp.fecha_salida >= $1 AND sp.fecha_salida < $2 LEFT JOIN categorias c ON p.id_categoria = c.id_categoria WHERE ` + sinVariantes + ` GROUP BY p.id_producto, p.codigo, p.nombre, c.nombre ORDER BY total_ingresos DESC, p.nombre`
+	rows, err := r.db.Pool.Query(context.Background(), query, inicio, fin.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.ReporteProductoVendido
+	for rows.Next() {
+		var item domain.ReporteProductoVendido
+		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.Categoria, &item.TotalVendido, &item.TotalIngresos); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetStockInmovilizado retorna los productos con stock cuya última venta tiene más de 'dias' días;
+// un componente vendido dentro de un kit cuenta como vendido
+func (r *reportesRepository) GetStockInmovilizado(dias int) ([]domain.ReporteStockInmovilizado, error) {
+	query := `SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre, 'SIN CATEGORIA'), p.stock_actual, p.precio_unitario, p.stock_actual * p.precio_unitario AS valor, u.ultima_venta, CURRENT_DATE - u.ultima_venta FROM productos p LEFT JOIN categorias c ON p.id_categoria = c.id_categoria LEFT JOIN (SELECT id_producto, MAX(fecha_salida) AS ultima_venta FROM ` + salidasConKits + ` v GROUP BY id_producto) u ON u.id_producto = p.id_producto WHERE p.stock_actual > 0 AND (u.ultima_venta IS NULL OR u.ultima_venta <= CURRENT_DATE - $1::int) ORDER BY valor DESC, p.nombre`
+	rows, err := r.db.Pool.Query(context.Background(), query, dias)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.ReporteStockInmovilizado
+	for rows.Next() {
+		var item domain.ReporteStockInmovilizado
+		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.Categoria, &item.StockActual, &item.PrecioUnitario, &item.ValorInmovilizado, &item.UltimaVenta, &item.DiasSinVenta); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetDescuentosPorPromocion agrupa las ventas con promoción del rango; el costo es
+// lo que dejó de cobrarse por cada promoción
+func (r *reportesRepository) GetDescuentosPorPromocion(rango domain.RangoFechas) ([]domain.ReportePromocion, error) {
+	var cond condiciones
+	cond.rango("sp.fecha_salida", rango)
+	query := `SELECT pr.id_promocion, pr.nombre, pr.tipo, COUNT(*), COALESCE(SUM(sp.cantidad), 0), COALESCE(SUM(sp.total), 0), COALESCE(SUM(sp.descuento_promocion), 0)
+		FROM salidas_productos sp JOIN promociones pr ON sp.id_promocion = pr.id_promocion` + cond.where() + `
+		GROUP BY pr.id_promocion, pr.nombre, pr.tipo ORDER BY 7 DESC, pr.nombre`
+	rows, err := r.db.Pool.Query(context.Background(), query, cond.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.ReportePromocion
+	for rows.Next() {
+		var item domain.ReportePromocion
+		if err := rows.Scan(&item.IDPromocion, &item.Nombre, &item.Tipo, &item.Ventas, &item.Unidades, &item.Ingresos, &item.CostoDescuento); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetComprasPorProveedor cruza las entradas y las devoluciones a proveedores del rango;
+// las entradas sin proveedor se agrupan como 'SIN PROVEEDOR'
+func (r *reportesRepository) GetComprasPorProveedor(rango domain.RangoFechas) ([]domain.ReporteCompraProveedor, error) {
+	query := `
+		WITH compras AS (
+			SELECT UPPER(COALESCE(NULLIF(proveedor, ''), 'SIN PROVEEDOR')) AS proveedor, COUNT(*) AS entradas,
+			       SUM(cantidad) AS unidades, SUM(base_imponible + igv) AS monto
+			FROM entradas_productos
+			WHERE ($1 = '' OR fecha_entrada >= $1::date) AND ($2 = '' OR fecha_entrada <= $2::date)
+			GROUP BY 1
+		), devoluciones AS (
+			SELECT UPPER(COALESCE(NULLIF(proveedor, ''), 'SIN PROVEEDOR')) AS proveedor, SUM(cantidad) AS unidades,
+			       SUM(monto_esperado) AS monto, COALESCE(SUM(monto_esperado) FILTER (WHERE estado = 'PENDIENTE'), 0) AS pendiente
+			FROM devoluciones_proveedores
+			WHERE ($1 = '' OR fecha >= $1::date) AND ($2 = '' OR fecha <= $2::date)
+			GROUP BY 1
+		)
+		SELECT COALESCE(c.proveedor, d.proveedor), COALESCE(c.entradas, 0), COALESCE(c.unidades, 0), COALESCE(c.monto, 0),
+		       COALESCE(d.unidades, 0), COALESCE(d.monto, 0), COALESCE(d.pendiente, 0)
+		FROM compras c FULL JOIN devoluciones d ON c.proveedor = d.proveedor
+		ORDER BY COALESCE(c.monto, 0) - COALESCE(d.monto, 0) DESC, 1`
+	rows, err := r.db.Pool.Query(context.Background(), query, rango.Desde, rango.Hasta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.ReporteCompraProveedor
+	for rows.Next() {
+		var item domain.ReporteCompraProveedor
+		if err := rows.Scan(&item.Proveedor, &item.Entradas, &item.Unidades, &item.MontoCompras, &item.UnidadesDevueltas, &item.MontoDevuelto, &item.CreditoPendiente); err != nil {
+			return nil, err
+		}
+		item.MontoNeto = item.MontoCompras - item.MontoDevuelto
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetResumenIGV totaliza por afectación las ventas y las entradas con precio del rango [inicio, fin).
+// Los reembolsos de las devoluciones de clientes del rango se restan de las ventas de su afectación
+// y las devoluciones a proveedores, de las compras.
+func (r *reportesRepository) GetResumenIGV(inicio, fin time.Time) ([]domain.ReporteIGVItem, []domain.ReporteIGVItem, error) {
+	query := `SELECT 'V', afectacion_igv, COALESCE(SUM(operaciones), 0), COALESCE(SUM(base_imponible), 0), COALESCE(SUM(igv), 0), COALESCE(SUM(total), 0)
+		FROM (
+			SELECT afectacion_igv, 1 AS operaciones, base_imponible, igv, total
+			FROM salidas_productos WHERE fecha_salida >= $1 AND fecha_salida < $2
+			UNION ALL
+			SELECT sp.afectacion_igv, 0, -d.base_imponible, -d.igv, -d.monto_reembolso
+			FROM devoluciones_clientes d JOIN salidas_productos sp ON sp.id_salida = d.id_salida
+			WHERE d.fecha >= $1 AND d.fecha < $2
+		) v GROUP BY afectacion_igv
+		UNION ALL
+		SELECT 'C', afectacion_igv, COALESCE(SUM(operaciones), 0), COALESCE(SUM(base_imponible), 0), COALESCE(SUM(igv), 0), COALESCE(SUM(base_imponible + igv), 0)
+		FROM (
+			SELECT afectacion_igv, 1 AS operaciones, base_imponible, igv
+			FROM entradas_productos WHERE fecha_entrada >= $1 AND fecha_entrada < $2 AND precio_unitario IS NOT NULL
+			UNION ALL
+			SELECT e.afectacion_igv, 0, -d.base_imponible, -d.igv
+			FROM devoluciones_proveedores d JOIN entradas_productos e ON e.id_entrada = d.id_entrada
+			WHERE d.fecha >= $1 AND d.fecha < $2 AND e.precio_unitario IS NOT NULL
+		) c GROUP BY afectacion_igv
+		ORDER BY 1 DESC, 2`
+	rows, err := r.db.Pool.Query(context.Background(), query, inicio, fin)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var ventas, compras []domain.ReporteIGVItem
+	for rows.Next() {
+		var origen string
+		var item domain.ReporteIGVItem
+		if err := rows.Scan(&origen, &item.Afectacion, &item.Operaciones, &item.BaseImponible, &item.IGV, &item.Total); err != nil {
+			return nil, nil, err
+		}
+		if origen == "V" {
+			ventas = append(ventas, item)
+		} else {
+			compras = append(compras, item)
+		}
+	}
+	return ventas, compras, rows.Err()
+}
+
+func (r *reportesRepository) GetVentasPorCategoria(rango domain.RangoFechas) ([]domain.ReporteVentasCategoria, error) {
+	var cond condiciones
+	cond.rango("sp.fecha_salida", rango)
+	query := `SELECT g.id_categoria, COUNT(*), COALESCE(SUM(sp.cantidad), 0), COALESCE(SUM(sp.total), 0)
+		FROM salidas_productos sp JOIN ` + productosAgrupados + ` ON sp.id_producto = p.id_producto` + cond.where() + `
+		GROUP BY g.id_categoria`
+	rows, err := r.db.Pool.Query(context.Background(), query, cond.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.ReporteVentasCategoria
+	for rows.Next() {
+		var item domain.ReporteVentasCategoria
+		if err := rows.Scan(&item.IDCategoria, &item.Ventas, &item.Unidades, &item.Ingresos); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -40,37 +40,37 @@ func (r *resumenMensualRepository) GetActual() (*domain.ResumenMensual, error) {
 
 func (r *resumenMensualRepository) GetByProductoID(productoID, mes, anio int) (*domain.ResumenProducto, error) {
 	rp := &domain.ResumenProducto{IDProducto: productoID, Mes: mes, Anio: anio}
+	inicio, fin := rangoMes(mes, anio)
 	err := r.db.Pool.QueryRow(context.Background(),
-		`SELECT COALESCE(SUM(cantidad), 0), COALESCE(SUM(cantidad * COALESCE(precio_unitario, 0)), 0) FROM entradas_productos WHERE id_producto = $1 AND EXTRACT(MONTH FROM fecha_entrada) = $2 AND EXTRACT(YEAR FROM fecha_entrada) = $3`,
-		productoID, mes, anio).Scan(&rp.TotalEntradas, &rp.MontoEntradas)
+		`SELECT COALESCE(SUM(cantidad), 0), COALESCE(SUM(base_imponible + igv), 0) FROM entradas_productos WHERE id_producto = $1 AND fecha_entrada >= $2 AND fecha_entrada < $3`,
+		productoID, inicio, fin).Scan(&rp.TotalEntradas, &rp.MontoEntradas)
 	if err != nil {
 		return nil, err
 	}
 	err = r.db.Pool.QueryRow(context.Background(),
-		`SELECT COALESCE(SUM(cantidad), 0), COALESCE(SUM(total), 0) FROM salidas_productos WHERE id_producto = $1 AND EXTRACT(MONTH FROM fecha_salida) = $2 AND EXTRACT(YEAR FROM fecha_salida) = $3`,
-		productoID, mes, anio).Scan(&rp.TotalSalidas, &rp.MontoSalidas)
+		`SELECT COALESCE(SUM(cantidad), 0), COALESCE(SUM(total), 0) FROM salidas_productos WHERE id_producto = $1 AND fecha_salida >= $2 AND fecha_salida < $3`,
+		productoID, inicio, fin).Scan(&rp.TotalSalidas, &rp.MontoSalidas)
 	if err != nil {
 		return nil, err
 	}
 	return rp, nil
 }
 
+// rangoMes retorna el primer día del mes y el primer día del mes siguiente
+func rangoMes(mes, anio int) (time.Time, time.Time) {
+	inicio := time.Date(anio, time.Month(mes), 1, 0, 0, 0, 0, time.UTC)
+	return inicio, inicio.AddDate(0, 1, 0)
+}
+
 func (r *resumenMensualRepository) Generar(mes, anio int) (*domain.ResumenMensual, error) {
-	var totalVentas float64
-	err := r.db.Pool.QueryRow(context.Background(),
-		`SELECT COALESCE(SUM(total), 0) FROM salidas_productos WHERE EXTRACT(MONTH FROM fecha_salida) = $1 AND EXTRACT(YEAR FROM fecha_salida) = $2`, mes, anio).Scan(&totalVentas)
-	if err != nil {
-		return nil, err
-	}
-	var totalGastos float64
-	err = r.db.Pool.QueryRow(context.Background(),
-		`SELECT COALESCE(SUM(monto_salida), 0) FROM control_diario WHERE EXTRACT(MONTH FROM fecha) = $1 AND EXTRACT(YEAR FROM fecha) = $2`, mes, anio).Scan(&totalGastos)
+	inicio, fin := rangoMes(mes, anio)
+	periodo, err := r.CalcularPeriodo(inicio, fin.AddDate(0, 0, -1))
 	if err != nil {
 		return nil, err
 	}
 	rm := &domain.ResumenMensual{
 		Mes: mes, Anio: anio,
-		TotalIngresos: totalVentas, TotalGastosFijos: 0, TotalGastosVariables: totalGastos,
+		TotalIngresos: periodo.TotalIngresos, TotalGastosFijos: 0, TotalGastosVariables: periodo.TotalGastos,
 		Observaciones: "Generado automaticamente", FechaGeneracion: time.Now(),
 	}
 	rm.Balance = rm.TotalIngresos - rm.TotalGastosFijos - rm.TotalGastosVariables
@@ -82,3 +82,163 @@ func (r *resumenMensualRepository) Upsert(rm *domain.ResumenMensual) error {
 	query := `INSERT INTO resumen_mensual (mes, anio, total_ingresos, total_gastos_fijos, total_gastos_variables, observaciones) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (mes, anio) DO UPDATE SET total_ingresos = EXCLUDED.total_ingresos, total_gastos_fijos = EXCLUDED.total_gastos_fijos, total_gastos_variables = EXCLUDED.total_gastos_variables, observaciones = EXCLUDED.observaciones RETURNING id_resumen, balance, fecha_generacion, fecha_actualizacion`
 	return r.db.Pool.QueryRow(context.Background(), query, rm.Mes, rm.Anio, rm.TotalIngresos, rm.TotalGastosFijos, rm.TotalGastosVariables, rm.Observaciones).Scan(&rm.ID, &rm.Balance, &rm.FechaGeneracion, &rm.FechaActualizacion)
 }
+
+func (r *resumenMensualRepository) GetByAnio(anio int) ([]domain.ResumenMensual, error) {
+	rows, err := r.db.Pool.Query(context.Background(), resumenSelect+" WHERE anio = $1 ORDER BY mes", anio)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var resumenes []domain.ResumenMensual
+	for rows.Next() {
+		var rm domain.ResumenMensual
+		if err := rows.Scan(&rm.ID, &rm.Mes, &rm.Anio, &rm.TotalIngresos, &rm.TotalGastosFijos, &rm.TotalGastosVariables, &rm.Balance, &rm.Observaciones, &rm.FechaGeneracion, &rm.FechaActualizacion); err != nil {
+			return nil, err
+		}
+		resumenes = append(resumenes, rm)
+	}
+	return resumenes, nil
+}
+
+// CalcularAnio calcula los doce meses del año desde ventas, devoluciones y control
+// diario sin persistirlos. Los reembolsos restan de los ingresos del mes en que se hacen.
+func (r *resumenMensualRepository) CalcularAnio(anio int) ([]domain.ResumenMensual, error) {
+	inicio := time.Date(anio, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fin := inicio.AddDate(1, 0, 0)
+	meses := make([]domain.ResumenMensual, 12)
+	for i := range meses {
+		meses[i] = domain.ResumenMensual{Mes: i + 1, Anio: anio, Calculado: true}
+	}
+
+	rows, err := r.db.Pool.Query(context.Background(),
+		`SELECT mes, SUM(monto) FROM (
+			SELECT EXTRACT(MONTH FROM fecha_salida)::int AS mes, total AS monto FROM salidas_productos WHERE fecha_salida >= $1 AND fecha_salida < $2
+			UNION ALL
+			SELECT EXTRACT(MONTH FROM fecha)::int, -monto_reembolso FROM devoluciones_clientes WHERE fecha >= $1 AND fecha < $2
+		) m GROUP BY 1`, inicio, fin)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var mes int
+		var total float64
+		if err := rows.Scan(&mes, &total); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		meses[mes-1].TotalIngresos = total
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Pool.Query(context.Background(),
+		`SELECT EXTRACT(MONTH FROM fecha)::int, COALESCE(SUM(monto_salida), 0) FROM control_diario WHERE fecha >= $1 AND fecha < $2 GROUP BY 1`, inicio, fin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var mes int
+		var total float64
+		if err := rows.Scan(&mes, &total); err != nil {
+			return nil, err
+		}
+		meses[mes-1].TotalGastosVariables = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range meses {
+		meses[i].Balance = meses[i].TotalIngresos - meses[i].TotalGastosFijos - meses[i].TotalGastosVariables
+		meses[i].Observaciones = "Calculado al vuelo"
+	}
+	return meses, nil
+}
+
+// CalcularPeriodo totaliza ventas netas de reembolsos y gastos entre inicio y fin
+// (ambos inclusive)
+func (r *resumenMensualRepository) CalcularPeriodo(inicio, fin time.Time) (*domain.ResumenPeriodo, error) {
+	rp := &domain.ResumenPeriodo{Inicio: inicio, Fin: fin}
+	finExclusivo := fin.AddDate(0, 0, 1)
+	err := r.db.Pool.QueryRow(context.Background(),
+		`SELECT COALESCE((SELECT SUM(total) FROM salidas_productos WHERE fecha_salida >= $1 AND fecha_salida < $2), 0)
+		      - COALESCE((SELECT SUM(monto_reembolso) FROM devoluciones_clientes WHERE fecha >= $1 AND fecha < $2), 0)`, inicio, finExclusivo).Scan(&rp.TotalIngresos)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Pool.QueryRow(context.Background(),
+		`SELECT COALESCE(SUM(monto_salida), 0) FROM control_diario WHERE fecha >= $1 AND fecha < $2`, inicio, finExclusivo).Scan(&rp.TotalGastos)
+	if err != nil {
+		return nil, err
+	}
+	rp.Balance = rp.TotalIngresos - rp.TotalGastos
+	return rp, nil
+}
+
+// matrizMovimientosQuery agrega en una sola pasada los movimientos desde el inicio del mes ($1):
+// los del mes (< $2) y los posteriores (>= $2), necesarios para reconstruir el stock de cierre.
+const matrizMovimientosQuery = `
+	WITH ent AS (
+		SELECT id_producto,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha_entrada < $2), 0) AS cant_mes,
+		       COALESCE(SUM(base_imponible + igv) FILTER (WHERE fecha_entrada < $2), 0) AS monto_mes,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha_entrada >= $2), 0) AS cant_post
+		FROM entradas_productos WHERE fecha_entrada >= $1 GROUP BY id_producto
+	), sal AS (
+		SELECT id_producto,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha_salida < $2), 0) AS cant_mes,
+		       COALESCE(SUM(total) FILTER (WHERE fecha_salida < $2), 0) AS monto_mes,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha_salida >= $2), 0) AS cant_post
+		FROM salidas_productos WHERE fecha_salida >= $1 GROUP BY id_producto
+	), aj AS (
+		SELECT id_producto,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha < $2), 0) AS cant_mes,
+		       COALESCE(SUM(cantidad) FILTER (WHERE fecha >= $2), 0) AS cant_post
+		FROM ajustes_stock WHERE fecha >= $1 GROUP BY id_producto
+	)
+	SELECT p.id_producto, p.codigo, p.nombre, COALESCE(c.nombre, 'SIN CATEGORIA'), p.unidad_medida, p.precio_unitario, p.stock_actual,
+	       COALESCE(ent.cant_mes, 0), COALESCE(ent.monto_mes, 0), COALESCE(ent.cant_post, 0),
+	       COALESCE(sal.cant_mes, 0), COALESCE(sal.monto_mes, 0), COALESCE(sal.cant_post, 0),
+	       COALESCE(aj.cant_mes, 0), COALESCE(aj.cant_post, 0), p.tipo = 'KIT'
+	FROM productos p
+	LEFT JOIN categorias c ON p.id_categoria = c.id_categoria
+	LEFT JOIN ent ON ent.id_producto = p.id_producto
+	LEFT JOIN sal ON sal.id_producto = p.id_producto
+	LEFT JOIN aj ON aj.id_producto = p.id_producto
+	WHERE ($3::int IS NULL OR p.id_categoria = $3)
+	ORDER BY c.nombre, p.nombre`
+
+func (r *resumenMensualRepository) GetMatrizMovimientos(mes, anio int, idCategoria *int) ([]domain.MovimientoMensualProducto, error) {
+	inicio, fin := rangoMes(mes, anio)
+	rows, err := r.db.Pool.Query(context.Background(), matrizMovimientosQuery, inicio, fin, idCategoria)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []domain.MovimientoMensualProducto
+	for rows.Next() {
+		var item domain.MovimientoMensualProducto
+		var stockActual, entradasPost, salidasPost, ajustesPost int
+		var esKit bool
+		if err := rows.Scan(&item.IDProducto, &item.Codigo, &item.Nombre, &item.Categoria, &item.UnidadMedida, &item.PrecioUnitario, &stockActual,
+			&item.Entradas, &item.MontoEntradas, &entradasPost,
+			&item.Salidas, &item.MontoSalidas, &salidasPost,
+			&item.Ajustes, &ajustesPost, &esKit); err != nil {
+			return nil, err
+		}
+		// Los kits aportan sus ventas pero no llevan stock: lo descuentan sus componentes
+		if esKit {
+			items = append(items, item)
+			continue
+		}
+		// El stock se reconstruye hacia atrás desde el stock actual
+		item.StockCierre = stockActual - entradasPost + salidasPost - ajustesPost
+		item.StockApertura = item.StockCierre - item.Entradas + item.Salidas - item.Ajustes
+		item.ValorCierre = float64(item.StockCierre) * item.PrecioUnitario
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
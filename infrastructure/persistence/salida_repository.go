@@ -2,6 +2,9 @@ package persistence
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Mishka-GDI-Back/domain"
@@ -17,41 +20,90 @@ func NewSalidaProductoRepository(db *database.Database) domain.SalidaProductoRep
 	return &salidaProductoRepository{db: db}
 }
 
-const salidaSelectJoin = `
-	SELECT sp.id_salida, sp.id_producto, sp.fecha_salida, sp.cantidad,
-	       sp.precio_venta, sp.descuento, sp.total, sp.lugar_venta, sp.tipo_pago,
-	       sp.observaciones, sp.usuario_registro, sp.fecha_creacion, sp.fecha_actualizacion,
-	       p.nombre, p.codigo, COALESCE(c.nombre, '') AS nombre_categoria
+const salidaFromJoin = `
 	FROM salidas_productos sp
 	JOIN productos p ON sp.id_producto = p.id_producto
-	LEFT JOIN categorias c ON p.id_categoria = c.id_categoria`
+	LEFT JOIN categorias c ON p.id_categoria = c.id_categoria
+	LEFT JOIN promociones pr ON sp.id_promocion = pr.id_promocion
+	LEFT JOIN comprobantes co ON sp.id_comprobante = co.id_comprobante
+	LEFT JOIN clientes cl ON sp.id_cliente = cl.id_cliente`
+
+const salidaSelectJoin = `
+	SELECT sp.id_salida, sp.id_producto, sp.id_comprobante, sp.id_cliente, sp.fecha_salida, sp.cantidad,
+	       sp.precio_venta, sp.descuento, sp.id_promocion, sp.descuento_promocion, sp.total,
+	       sp.afectacion_igv, sp.tasa_igv, sp.base_imponible, sp.igv,
+	       sp.lugar_venta, sp.tipo_pago, sp.observaciones, sp.usuario_registro,
+	       sp.fecha_creacion, sp.fecha_actualizacion, p.nombre, p.codigo,
+	       COALESCE(c.nombre, '') AS nombre_categoria, COALESCE(pr.nombre, '') AS nombre_promocion,
+	       COALESCE(co.serie || '-' || LPAD(co.numero::text, 8, '0'), '') AS comprobante,
+	       COALESCE(cl.nombre, '') AS nombre_cliente` + salidaFromJoin
+
+var columnasOrdenSalidas = map[string]string{
+	"fecha":    "sp.fecha_salida",
+	"cantidad": "sp.cantidad",
+	"total":    "sp.total",
+	"producto": "p.nombre",
+	"lugar":    "sp.lugar_venta",
+	"usuario":  "sp.usuario_registro",
+}
+
+const salidaInsert = `INSERT INTO salidas_productos (id_producto, id_comprobante, id_cliente, fecha_salida, cantidad, precio_venta, descuento, id_promocion, descuento_promocion, total, afectacion_igv, tasa_igv, base_imponible, igv, lugar_venta, tipo_pago, observaciones, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING id_salida, fecha_creacion, fecha_actualizacion`
 
 func scanSalidaConProducto(rows pgx.Rows) (domain.SalidaConProducto, error) {
 	var s domain.SalidaConProducto
 	err := rows.Scan(
-		&s.ID, &s.IDProducto, &s.FechaSalida, &s.Cantidad,
-		&s.PrecioVenta, &s.Descuento, &s.Total, &s.LugarVenta, &s.TipoPago,
-		&s.Observaciones, &s.UsuarioRegistro, &s.FechaCreacion, &s.FechaActualizacion,
-		&s.NombreProducto, &s.CodigoProducto, &s.NombreCategoria,
+		&s.ID, &s.IDProducto, &s.IDComprobante, &s.IDCliente, &s.FechaSalida, &s.Cantidad,
+		&s.PrecioVenta, &s.Descuento, &s.IDPromocion, &s.DescuentoPromocion, &s.Total,
+		&s.Afectacion, &s.Tasa, &s.BaseImponible, &s.IGV,
+		&s.LugarVenta, &s.TipoPago, &s.Observaciones, &s.UsuarioRegistro,
+		&s.FechaCreacion, &s.FechaActualizacion, &s.NombreProducto, &s.CodigoProducto,
+		&s.NombreCategoria, &s.NombrePromocion, &s.Comprobante,
+		&s.NombreCliente,
 	)
 	return s, err
 }
 
-func (r *salidaProductoRepository) GetAll() ([]domain.SalidaConProducto, error) {
-	rows, err := r.db.Pool.Query(context.Background(), salidaSelectJoin+" ORDER BY sp.fecha_salida DESC")
+func (r *salidaProductoRepository) GetAll(filtro domain.FiltroSalidas) ([]domain.SalidaConProducto, int, error) {
+	var cond condiciones
+	cond.rango("sp.fecha_salida", filtro.RangoFechas)
+	if filtro.IDProducto != nil {
+		cond.agregar("sp.id_producto = ?", *filtro.IDProducto)
+	}
+	if filtro.IDCategoria != nil {
+		cond.agregar("p.id_categoria IN "+subcategorias, *filtro.IDCategoria)
+	}
+	if filtro.Lugar != "" {
+		cond.agregar("UPPER(sp.lugar_venta) = UPPER(?)", filtro.Lugar)
+	}
+	if filtro.Usuario != "" {
+		cond.agregar("UPPER(sp.usuario_registro) = UPPER(?)", filtro.Usuario)
+	}
+	if filtro.TipoPago != "" {
+		cond.agregar("UPPER(sp.tipo_pago) = UPPER(?)", filtro.TipoPago)
+	}
+	if filtro.IDCliente != nil {
+		cond.agregar("sp.id_cliente = ?", *filtro.IDCliente)
+	}
+	var total int
+	if err := r.db.Pool.QueryRow(context.Background(), "SELECT COUNT(*)"+salidaFromJoin+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	limite, args := cond.paginar(filtro.Paginacion)
+	query := salidaSelectJoin + cond.where() + ordenarPor(filtro.Orden, columnasOrdenSalidas, "sp.fecha_salida DESC, sp.id_salida DESC") + limite
+	rows, err := r.db.Pool.Query(context.Background(), query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 	var salidas []domain.SalidaConProducto
 	for rows.Next() {
 		s, err := scanSalidaConProducto(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		salidas = append(salidas, s)
 	}
-	return salidas, nil
+	return salidas, total, nil
 }
 
 func (r *salidaProductoRepository) GetByID(id int) (*domain.SalidaConProducto, error) {
@@ -121,16 +173,151 @@ func (r *salidaProductoRepository) GetByLugar(lugar string) ([]domain.SalidaConP
 	return salidas, nil
 }
 
-func (r *salidaProductoRepository) Create(salida *domain.SalidaProducto) error {
-	fechaSalida, err := time.Parse("2006-01-02", salida.FechaSalida.Format("2006-01-02"))
+func (r *salidaProductoRepository) GetByComprobante(comprobanteID int) ([]domain.SalidaConProducto, error) {
+	rows, err := r.db.Pool.Query(context.Background(), salidaSelectJoin+" WHERE sp.id_comprobante = $1 ORDER BY sp.id_salida", comprobanteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var salidas []domain.SalidaConProducto
+	for rows.Next() {
+		s, err := scanSalidaConProducto(rows)
+		if err != nil {
+			return nil, err
+		}
+		salidas = append(salidas, s)
+	}
+	return salidas, nil
+}
+
+// descuentoStock es una resta de stock pendiente dentro de la venta; motivo solo se
+// indica en los componentes de kits, cuyo descuento queda en ajustes_stock para que
+// la matriz mensual cuadre
+type descuentoStock struct {
+	idProducto int
+	cantidad   int
+	fecha      time.Time
+	motivo     string
+}
+
+// CreateVenta toma el siguiente número de la serie, inserta el comprobante y sus
+// salidas y descuenta el stock. La fila de la serie queda bloqueada hasta el commit,
+// así que las ventas concurrentes del mismo lugar se numeran una tras otra; si algo
+// falla, el rollback devuelve el número y no quedan huecos.
+func (r *salidaProductoRepository) CreateVenta(comprobante *domain.Comprobante, lineas []domain.LineaVenta, cuenta *domain.CuentaPorCobrar) error {
+	ctx := context.Background()
+	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	query := `INSERT INTO salidas_productos (id_producto, fecha_salida, cantidad, precio_venta, descuento, total, lugar_venta, tipo_pago, observaciones, usuario_registro) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id_salida, fecha_creacion, fecha_actualizacion`
-	err = r.db.Pool.QueryRow(context.Background(), query, salida.IDProducto, fechaSalida, salida.Cantidad, salida.PrecioVenta, salida.Descuento, salida.Total, salida.LugarVenta, salida.TipoPago, salida.Observaciones, salida.UsuarioRegistro).Scan(&salida.ID, &salida.FechaCreacion, &salida.FechaActualizacion)
+	defer tx.Rollback(ctx)
+
+	// La serie propia del lugar tiene prioridad sobre la predeterminada (lugar vacío)
+	err = tx.QueryRow(ctx, `
+		SELECT id_serie, tipo, serie FROM series_comprobante
+		WHERE activa AND (lugar_venta = '' OR UPPER(lugar_venta) = UPPER($1))
+		ORDER BY lugar_venta = '' LIMIT 1
+		FOR UPDATE`, comprobante.LugarVenta).Scan(&comprobante.IDSerie, &comprobante.Tipo, &comprobante.Serie)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrValidation{Field: "lugar_venta", Message: "no hay una serie de comprobantes activa para el lugar de venta"}
+	}
 	if err != nil {
 		return err
 	}
-	salida.FechaSalida = fechaSalida
-	return nil
+	err = tx.QueryRow(ctx, `UPDATE series_comprobante SET correlativo = correlativo + 1 WHERE id_serie = $1 RETURNING correlativo`, comprobante.IDSerie).Scan(&comprobante.Numero)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO comprobantes (id_serie, tipo, serie, numero, id_cliente, lugar_venta, tipo_pago, usuario_registro, subtotal, descuento, op_gravada, op_exonerada, op_inafecta, igv, total) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id_comprobante, fecha_emision, fecha_creacion`
+	err = tx.QueryRow(ctx, query, comprobante.IDSerie, comprobante.Tipo, comprobante.Serie, comprobante.Numero, comprobante.IDCliente, comprobante.LugarVenta, comprobante.TipoPago, comprobante.UsuarioRegistro, comprobante.Subtotal, comprobante.Descuento, comprobante.OpGravada, comprobante.OpExonerada, comprobante.OpInafecta, comprobante.IGV, comprobante.Total).Scan(&comprobante.ID, &comprobante.FechaEmision, &comprobante.FechaCreacion)
+	if err != nil {
+		return err
+	}
+
+	var descuentos []descuentoStock
+	for _, l := range lineas {
+		salida := l.Salida
+		fechaSalida, err := time.Parse("2006-01-02", salida.FechaSalida.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		salida.IDComprobante = &comprobante.ID
+		err = tx.QueryRow(ctx, salidaInsert, salida.IDProducto, salida.IDComprobante, salida.IDCliente, fechaSalida, salida.Cantidad, salida.PrecioVenta, salida.Descuento, salida.IDPromocion, salida.DescuentoPromocion, salida.Total, salida.Afectacion, salida.Tasa, salida.BaseImponible, salida.IGV, salida.LugarVenta, salida.TipoPago, salida.Observaciones, salida.UsuarioRegistro).Scan(&salida.ID, &salida.FechaCreacion, &salida.FechaActualizacion)
+		if err != nil {
+			return err
+		}
+		salida.FechaSalida = fechaSalida
+		// Los kits registran los ingresos a su nombre pero descuentan sus componentes
+		if len(l.Componentes) == 0 {
+			descuentos = append(descuentos, descuentoStock{idProducto: salida.IDProducto, cantidad: salida.Cantidad})
+			continue
+		}
+		motivo := fmt.Sprintf("Venta de kit (salida #%d)", salida.ID)
+		for _, c := range l.Componentes {
+			descuentos = append(descuentos, descuentoStock{idProducto: c.IDComponente, cantidad: c.Cantidad * salida.Cantidad, fecha: fechaSalida, motivo: motivo})
+		}
+	}
+	// Bloquear los productos siempre en el mismo orden evita interbloqueos entre ventas
+	// de distintos lugares que comparten productos
+	sort.SliceStable(descuentos, func(i, j int) bool { return descuentos[i].idProducto < descuentos[j].idProducto })
+	for _, d := range descuentos {
+		var stockNuevo int
+		// La condición en el UPDATE bloquea la fila y evita vender stock que otra venta ya tomó
+		err := tx.QueryRow(ctx, `UPDATE productos SET stock_actual = stock_actual - $2 WHERE id_producto = $1 AND stock_actual >= $2 RETURNING stock_actual`, d.idProducto, d.cantidad).Scan(&stockNuevo)
+		if errors.Is(err, pgx.ErrNoRows) {
+			var disponible int
+			if err := tx.QueryRow(ctx, `SELECT stock_actual FROM productos WHERE id_producto = $1`, d.idProducto).Scan(&disponible); err != nil {
+				return err
+			}
+			return &domain.ErrInsufficientStock{ProductoID: d.idProducto, StockActual: disponible, CantidadReq: d.cantidad}
+		}
+		if err != nil {
+			return err
+		}
+		if d.motivo == "" {
+			continue
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO ajustes_stock (id_producto, fecha, cantidad, stock_anterior, stock_nuevo, motivo) VALUES ($1, $2, $3, $4, $5, $6)`, d.idProducto, d.fecha, -d.cantidad, stockNuevo+d.cantidad, stockNuevo, d.motivo)
+		if err != nil {
+			return err
+		}
+	}
+	if cuenta != nil {
+		if err := crearCuentaPorCobrar(ctx, tx, comprobante, cuenta); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// crearCuentaPorCobrar registra la deuda de una venta al crédito. La fila del cliente
+// se bloquea para que dos ventas simultáneas no superen juntas su límite de crédito.
+func crearCuentaPorCobrar(ctx context.Context, tx pgx.Tx, comprobante *domain.Comprobante, cuenta *domain.CuentaPorCobrar) error {
+	var limite *float64
+	var deuda float64
+	err := tx.QueryRow(ctx, `SELECT limite_credito FROM clientes WHERE id_cliente = $1 FOR UPDATE`, cuenta.IDCliente).Scan(&limite)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.ErrNotFound{Entity: "cliente", ID: cuenta.IDCliente}
+	}
+	if err != nil {
+		return err
+	}
+	if limite != nil {
+		err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(saldo), 0) FROM cuentas_por_cobrar WHERE id_cliente = $1 AND estado = 'PENDIENTE'`, cuenta.IDCliente).Scan(&deuda)
+		if err != nil {
+			return err
+		}
+		if deuda+comprobante.Total > *limite+0.005 {
+			return &domain.ErrValidation{Field: "id_cliente", Message: fmt.Sprintf("la venta supera el límite de crédito del cliente (deuda %.2f, límite %.2f)", deuda, *limite)}
+		}
+	}
+	cuenta.IDComprobante = comprobante.ID
+	cuenta.Comprobante = comprobante.Numeracion()
+	cuenta.Monto, cuenta.Saldo = comprobante.Total, comprobante.Total
+	query := `INSERT INTO cuentas_por_cobrar (id_cliente, id_comprobante, fecha_emision, fecha_vencimiento, monto, saldo, estado) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id_cuenta, estado, fecha_creacion`
+	estado := domain.EstadoCuentaPendiente
+	if cuenta.Saldo == 0 {
+		estado = domain.EstadoCuentaPagada
+	}
+	return tx.QueryRow(ctx, query, cuenta.IDCliente, cuenta.IDComprobante, cuenta.FechaEmision.Format("2006-01-02"), cuenta.FechaVencimiento.Format("2006-01-02"), cuenta.Monto, cuenta.Saldo, estado).Scan(&cuenta.ID, &cuenta.Estado, &cuenta.FechaCreacion)
 }
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mishka-GDI-Back/domain"
+	"github.com/Mishka-GDI-Back/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type unidadMedidaRepository struct {
+	db *database.Database
+}
+
+func NewUnidadMedidaRepository(db *database.Database) domain.UnidadMedidaRepository {
+	return &unidadMedidaRepository{db: db}
+}
+
+func (r *unidadMedidaRepository) GetAll() ([]domain.UnidadMedida, error) {
+	rows, err := r.db.Pool.Query(context.Background(), `SELECT id_unidad, codigo, nombre, fecha_creacion FROM unidades_medida ORDER BY codigo`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var unidades []domain.UnidadMedida
+	for rows.Next() {
+		var u domain.UnidadMedida
+		if err := rows.Scan(&u.ID, &u.Codigo, &u.Nombre, &u.FechaCreacion); err != nil {
+			return nil, err
+		}
+		unidades = append(unidades, u)
+	}
+	return unidades, nil
+}
+
+func (r *unidadMedidaRepository) GetByCodigo(codigo string) (*domain.UnidadMedida, error) {
+	var u domain.UnidadMedida
+	err := r.db.Pool.QueryRow(context.Background(), `SELECT id_unidad, codigo, nombre, fecha_creacion FROM unidades_medida WHERE codigo = $1`, codigo).Scan(&u.ID, &u.Codigo, &u.Nombre, &u.FechaCreacion)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &domain.ErrNotFound{Entity: "unidad de medida", ID: codigo}
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *unidadMedidaRepository) Create(unidad *domain.UnidadMedida) error {
+	query := `INSERT INTO unidades_medida (codigo, nombre) VALUES ($1, $2) RETURNING id_unidad, fecha_creacion`
+	return r.db.Pool.QueryRow(context.Background(), query, unidad.Codigo, unidad.Nombre).Scan(&unidad.ID, &unidad.FechaCreacion)
+}
+
+func (r *unidadMedidaRepository) GetConversiones(productoID int) ([]domain.ConversionUnidad, error) {
+	rows, err := r.db.Pool.Query(context.Background(), `SELECT id_producto, unidad, factor FROM producto_unidades WHERE id_producto = $1 ORDER BY factor`, productoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var conversiones []domain.ConversionUnidad
+	for rows.Next() {
+		var c domain.ConversionUnidad
+		if err := rows.Scan(&c.IDProducto, &c.Unidad, &c.Factor); err != nil {
+			return nil, err
+		}
+		conversiones = append(conversiones, c)
+	}
+	return conversiones, nil
+}
+
+// GetFactores retorna el factor de 'unidad' de cada producto que la tiene configurada
+func (r *unidadMedidaRepository) GetFactores(unidad string) (map[int]int, error) {
+	rows, err := r.db.Pool.Query(context.Background(), `SELECT id_producto, factor FROM producto_unidades WHERE unidad = $1`, unidad)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	factores := make(map[int]int)
+	for rows.Next() {
+		var id, factor int
+		if err := rows.Scan(&id, &factor); err != nil {
+			return nil, err
+		}
+		factores[id] = factor
+	}
+	return factores, nil
+}
+
+func (r *unidadMedidaRepository) SetConversion(c *domain.ConversionUnidad) error {
+	query := `INSERT INTO producto_unidades (id_producto, unidad, factor) VALUES ($1, $2, $3) ON CONFLICT (id_producto, unidad) DO UPDATE SET factor = EXCLUDED.factor`
+	_, err := r.db.Pool.Exec(context.Background(), query, c.IDProducto, c.Unidad, c.Factor)
+	return err
+}
+
+func (r *unidadMedidaRepository) DeleteConversion(productoID int, unidad string) error {
+	result, err := r.db.Pool.Exec(context.Background(), `DELETE FROM producto_unidades WHERE id_producto = $1 AND unidad = $2`, productoID, unidad)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &domain.ErrNotFound{Entity: "conversión de unidad", ID: unidad}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Ejecutar corre 'tarea' al iniciar y luego cada 'intervalo' hasta que se cancele
+// ctx. Los errores se registran en el log y no detienen las siguientes ejecuciones.
+func Ejecutar(ctx context.Context, nombre string, intervalo time.Duration, tarea func() error) {
+	ejecutar := func() {
+		if err := tarea(); err != nil {
+			log.Printf("⚠️ Error en tarea programada %q: %v", nombre, err)
+		}
+	}
+	ejecutar()
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ejecutar()
+		}
+	}
+}
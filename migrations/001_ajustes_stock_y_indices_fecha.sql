@@ -0,0 +1,17 @@
+-- Registro de ajustes manuales de stock e índices por fecha para consultas mensuales
+
+CREATE TABLE IF NOT EXISTS ajustes_stock (
+    id_ajuste       SERIAL PRIMARY KEY,
+    id_producto     INTEGER NOT NULL REFERENCES productos(id_producto),
+    fecha           DATE NOT NULL DEFAULT CURRENT_DATE,
+    cantidad        INTEGER NOT NULL,
+    stock_anterior  INTEGER NOT NULL,
+    stock_nuevo     INTEGER NOT NULL,
+    motivo          VARCHAR(200) NOT NULL DEFAULT '',
+    fecha_creacion  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ajustes_stock_fecha ON ajustes_stock (fecha, id_producto);
+CREATE INDEX IF NOT EXISTS idx_entradas_productos_fecha ON entradas_productos (fecha_entrada, id_producto);
+CREATE INDEX IF NOT EXISTS idx_salidas_productos_fecha ON salidas_productos (fecha_salida, id_producto);
+CREATE INDEX IF NOT EXISTS idx_control_diario_fecha ON control_diario (fecha);
@@ -0,0 +1,6 @@
+-- Clasificación ABC de productos por participación en ingresos
+
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS clase_abc CHAR(1)
+    CHECK (clase_abc IN ('A', 'B', 'C'));
+
+CREATE INDEX IF NOT EXISTS idx_productos_clase_abc ON productos (clase_abc);
@@ -0,0 +1,16 @@
+-- Búsqueda de productos sin distinguir tildes y tolerante a errores de tipeo
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() es STABLE; el envoltorio IMMUTABLE permite usarlo en índices
+CREATE OR REPLACE FUNCTION f_unaccent(texto text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, texto) $$;
+
+CREATE INDEX IF NOT EXISTS idx_productos_nombre_trgm
+    ON productos USING gin (f_unaccent(lower(nombre)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_productos_codigo_trgm
+    ON productos USING gin (lower(codigo) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categorias_nombre_trgm
+    ON categorias USING gin (f_unaccent(lower(nombre)) gin_trgm_ops);
@@ -0,0 +1,11 @@
+-- Códigos de barras por producto (EAN-13, EAN-8, UPC-A o códigos internos)
+
+CREATE TABLE IF NOT EXISTS codigos_barras (
+    id_codigo_barras SERIAL PRIMARY KEY,
+    id_producto      INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE CASCADE,
+    codigo           VARCHAR(50) NOT NULL UNIQUE,
+    tipo             VARCHAR(10) NOT NULL CHECK (tipo IN ('EAN13', 'EAN8', 'UPC', 'INTERNO')),
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_codigos_barras_producto ON codigos_barras (id_producto);
@@ -0,0 +1,12 @@
+-- Variantes (talla, color, sabor...) de un producto padre. Cada variante es un
+-- producto con su propio código, precio y stock; el padre agrupa a sus variantes.
+
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS id_producto_padre INTEGER
+    REFERENCES productos (id_producto) ON DELETE RESTRICT;
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS atributos JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE productos DROP CONSTRAINT IF EXISTS chk_productos_padre_distinto;
+ALTER TABLE productos ADD CONSTRAINT chk_productos_padre_distinto
+    CHECK (id_producto_padre IS NULL OR id_producto_padre <> id_producto);
+
+CREATE INDEX IF NOT EXISTS idx_productos_padre ON productos (id_producto_padre);
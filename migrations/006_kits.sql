@@ -0,0 +1,15 @@
+-- Kits y combos: productos cuyo stock se calcula a partir de sus componentes.
+-- Al vender un kit se descuenta cada componente y el descuento queda en ajustes_stock.
+
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS tipo VARCHAR(10) NOT NULL DEFAULT 'SIMPLE'
+    CHECK (tipo IN ('SIMPLE', 'KIT'));
+
+CREATE TABLE IF NOT EXISTS kit_componentes (
+    id_kit        INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE CASCADE,
+    id_componente INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE RESTRICT,
+    cantidad      INTEGER NOT NULL CHECK (cantidad > 0),
+    PRIMARY KEY (id_kit, id_componente),
+    CHECK (id_kit <> id_componente)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kit_componentes_componente ON kit_componentes (id_componente);
@@ -0,0 +1,33 @@
+-- Catálogo de unidades de medida y factores de conversión por producto.
+-- productos.unidad_medida sigue siendo la unidad base en la que se lleva el stock.
+
+CREATE TABLE IF NOT EXISTS unidades_medida (
+    id_unidad      SERIAL PRIMARY KEY,
+    codigo         VARCHAR(20) NOT NULL UNIQUE,
+    nombre         VARCHAR(50) NOT NULL,
+    fecha_creacion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO unidades_medida (codigo, nombre) VALUES
+    ('UNIDAD', 'Unidad'),
+    ('CAJA', 'Caja'),
+    ('PAQUETE', 'Paquete'),
+    ('DOCENA', 'Docena'),
+    ('BOLSA', 'Bolsa')
+ON CONFLICT (codigo) DO NOTHING;
+
+-- Las unidades ya usadas por los productos pasan al catálogo
+INSERT INTO unidades_medida (codigo, nombre)
+SELECT DISTINCT upper(unidad_medida), upper(unidad_medida) FROM productos WHERE unidad_medida <> ''
+ON CONFLICT (codigo) DO NOTHING;
+UPDATE productos SET unidad_medida = upper(unidad_medida) WHERE unidad_medida <> upper(unidad_medida);
+
+-- factor: cuántas unidades base contiene una unidad (CAJA = 12 UNIDAD)
+CREATE TABLE IF NOT EXISTS producto_unidades (
+    id_producto INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE CASCADE,
+    unidad      VARCHAR(20) NOT NULL REFERENCES unidades_medida (codigo),
+    factor      INTEGER NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (id_producto, unidad)
+);
+
+CREATE INDEX IF NOT EXISTS idx_producto_unidades_unidad ON producto_unidades (unidad);
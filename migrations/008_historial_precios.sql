@@ -0,0 +1,32 @@
+-- Historial de precios y cambios de precio programados.
+-- Cada cambio de precio_unitario queda en historial_precios; los cambios con fecha
+-- futura esperan en precios_programados hasta que el programador los aplique.
+
+CREATE TABLE IF NOT EXISTS precios_programados (
+    id_programado    SERIAL PRIMARY KEY,
+    id_producto      INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE CASCADE,
+    precio_nuevo     DECIMAL(10, 2) NOT NULL CHECK (precio_nuevo >= 0),
+    fecha_vigencia   DATE NOT NULL,
+    usuario          VARCHAR(50) NOT NULL DEFAULT '',
+    estado           VARCHAR(10) NOT NULL DEFAULT 'PENDIENTE'
+        CHECK (estado IN ('PENDIENTE', 'APLICADO', 'CANCELADO')),
+    fecha_aplicacion TIMESTAMP,
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Un solo cambio pendiente por producto y fecha
+CREATE UNIQUE INDEX IF NOT EXISTS idx_precios_programados_pendiente
+    ON precios_programados (id_producto, fecha_vigencia) WHERE estado = 'PENDIENTE';
+
+CREATE TABLE IF NOT EXISTS historial_precios (
+    id_historial    SERIAL PRIMARY KEY,
+    id_producto     INTEGER NOT NULL REFERENCES productos (id_producto) ON DELETE CASCADE,
+    precio_anterior DECIMAL(10, 2) NOT NULL,
+    precio_nuevo    DECIMAL(10, 2) NOT NULL,
+    usuario         VARCHAR(50) NOT NULL DEFAULT '',
+    motivo          VARCHAR(200) NOT NULL DEFAULT '',
+    id_programado   INTEGER REFERENCES precios_programados (id_programado) ON DELETE SET NULL,
+    fecha_cambio    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_historial_precios_producto ON historial_precios (id_producto, fecha_cambio);
@@ -0,0 +1,33 @@
+-- Promociones evaluadas al registrar una venta. Cada salida guarda la promoción
+-- aplicada y el descuento que generó, aparte del descuento manual del cajero.
+
+CREATE TABLE IF NOT EXISTS promociones (
+    id_promocion   SERIAL PRIMARY KEY,
+    nombre         VARCHAR(100) NOT NULL,
+    tipo           VARCHAR(20) NOT NULL
+        CHECK (tipo IN ('PORCENTAJE', 'MONTO_FIJO', 'NXM', 'PRECIO_PAQUETE')),
+    -- Alcance: un producto (y sus variantes), una categoría o, si ambos son nulos, todo
+    id_producto    INTEGER REFERENCES productos (id_producto) ON DELETE RESTRICT,
+    id_categoria   INTEGER REFERENCES categorias (id_categoria) ON DELETE RESTRICT,
+    -- Porcentaje, monto por unidad o precio del paquete según el tipo
+    valor          DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (valor >= 0),
+    -- NXM: lleva N paga M; PRECIO_PAQUETE: N unidades por 'valor'
+    cantidad_lleva INTEGER CHECK (cantidad_lleva > 0),
+    cantidad_paga  INTEGER CHECK (cantidad_paga >= 0),
+    -- Ventana de vigencia; los nulos no restringen
+    fecha_inicio   DATE,
+    fecha_fin      DATE,
+    hora_inicio    TIME,
+    hora_fin       TIME,
+    dias_semana    INTEGER[] NOT NULL DEFAULT '{}', -- 0 = domingo ... 6 = sábado
+    lugar_venta    VARCHAR(100) NOT NULL DEFAULT '',
+    activa         BOOLEAN NOT NULL DEFAULT TRUE,
+    fecha_creacion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (id_producto IS NULL OR id_categoria IS NULL)
+);
+
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS id_promocion INTEGER
+    REFERENCES promociones (id_promocion) ON DELETE SET NULL;
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS descuento_promocion DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_salidas_promocion ON salidas_productos (id_promocion) WHERE id_promocion IS NOT NULL;
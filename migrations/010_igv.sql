@@ -0,0 +1,39 @@
+-- IGV: afectación y tasa por producto; base imponible e IGV guardados en cada salida
+-- y entrada. Los precios incluyen el IGV.
+
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS afectacion_igv VARCHAR(10) NOT NULL DEFAULT 'GRAVADO'
+    CHECK (afectacion_igv IN ('GRAVADO', 'EXONERADO', 'INAFECTO'));
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS tasa_igv DECIMAL(5, 2) NOT NULL DEFAULT 18.00
+    CHECK (tasa_igv >= 0 AND tasa_igv < 100);
+
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS afectacion_igv VARCHAR(10) NOT NULL DEFAULT 'GRAVADO';
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS tasa_igv DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS base_imponible DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS igv DECIMAL(12, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE entradas_productos ADD COLUMN IF NOT EXISTS afectacion_igv VARCHAR(10) NOT NULL DEFAULT 'GRAVADO';
+ALTER TABLE entradas_productos ADD COLUMN IF NOT EXISTS tasa_igv DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE entradas_productos ADD COLUMN IF NOT EXISTS base_imponible DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE entradas_productos ADD COLUMN IF NOT EXISTS igv DECIMAL(12, 2) NOT NULL DEFAULT 0;
+
+-- Los movimientos anteriores se desglosan con la configuración vigente del producto
+UPDATE salidas_productos sp
+SET afectacion_igv = p.afectacion_igv,
+    tasa_igv       = CASE WHEN p.afectacion_igv = 'GRAVADO' THEN p.tasa_igv ELSE 0 END,
+    base_imponible = CASE WHEN p.afectacion_igv = 'GRAVADO' THEN ROUND(sp.total / (1 + p.tasa_igv / 100), 2) ELSE sp.total END
+FROM productos p
+WHERE p.id_producto = sp.id_producto AND sp.base_imponible = 0 AND sp.igv = 0;
+UPDATE salidas_productos SET igv = total - base_imponible WHERE igv = 0;
+
+UPDATE entradas_productos ep
+SET afectacion_igv = p.afectacion_igv,
+    tasa_igv       = CASE WHEN p.afectacion_igv = 'GRAVADO' THEN p.tasa_igv ELSE 0 END,
+    base_imponible = CASE WHEN p.afectacion_igv = 'GRAVADO'
+                          THEN ROUND(ep.cantidad * COALESCE(ep.precio_unitario, 0) / (1 + p.tasa_igv / 100), 2)
+                          ELSE ROUND(ep.cantidad * COALESCE(ep.precio_unitario, 0), 2) END
+FROM productos p
+WHERE p.id_producto = ep.id_producto AND ep.base_imponible = 0 AND ep.igv = 0;
+UPDATE entradas_productos SET igv = ROUND(cantidad * COALESCE(precio_unitario, 0), 2) - base_imponible WHERE igv = 0;
+
+CREATE INDEX IF NOT EXISTS idx_salidas_fecha_afectacion ON salidas_productos (fecha_salida, afectacion_igv);
+CREATE INDEX IF NOT EXISTS idx_entradas_fecha_afectacion ON entradas_productos (fecha_entrada, afectacion_igv);
@@ -0,0 +1,51 @@
+-- Comprobantes de venta (boleta/ticket) con numeración correlativa por serie.
+-- Cada lugar de venta usa su serie activa o, si no tiene, la serie activa sin lugar.
+-- El correlativo se incrementa dentro de la transacción de la venta, así que una
+-- venta fallida no consume número y no quedan huecos.
+
+CREATE TABLE IF NOT EXISTS series_comprobante (
+    id_serie       SERIAL PRIMARY KEY,
+    tipo           VARCHAR(10) NOT NULL DEFAULT 'TICKET' CHECK (tipo IN ('BOLETA', 'TICKET')),
+    serie          VARCHAR(4) NOT NULL UNIQUE,
+    lugar_venta    VARCHAR(100) NOT NULL DEFAULT '',
+    correlativo    INTEGER NOT NULL DEFAULT 0 CHECK (correlativo >= 0),
+    activa         BOOLEAN NOT NULL DEFAULT TRUE,
+    fecha_creacion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Una sola serie activa por lugar de venta
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_comprobante_lugar
+    ON series_comprobante (UPPER(lugar_venta)) WHERE activa;
+
+INSERT INTO series_comprobante (tipo, serie) VALUES ('TICKET', 'T001') ON CONFLICT (serie) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS comprobantes (
+    id_comprobante   SERIAL PRIMARY KEY,
+    id_serie         INTEGER NOT NULL REFERENCES series_comprobante (id_serie),
+    tipo             VARCHAR(10) NOT NULL,
+    serie            VARCHAR(4) NOT NULL,
+    numero           INTEGER NOT NULL,
+    fecha_emision    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    lugar_venta      VARCHAR(100) NOT NULL DEFAULT '',
+    tipo_pago        VARCHAR(50) NOT NULL DEFAULT '',
+    usuario_registro VARCHAR(100) NOT NULL DEFAULT '',
+    subtotal         DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    descuento        DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    op_gravada       DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    op_exonerada     DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    op_inafecta      DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    igv              DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    total            DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (serie, numero)
+);
+
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS id_comprobante INTEGER REFERENCES comprobantes (id_comprobante);
+CREATE INDEX IF NOT EXISTS idx_salidas_productos_comprobante ON salidas_productos (id_comprobante);
+
+-- Datos del negocio y otros ajustes editables desde la aplicación
+CREATE TABLE IF NOT EXISTS configuracion (
+    clave               VARCHAR(50) PRIMARY KEY,
+    valor               TEXT NOT NULL DEFAULT '',
+    fecha_actualizacion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
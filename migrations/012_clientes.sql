@@ -0,0 +1,62 @@
+-- Clientes y ventas al crédito ("fiado").
+-- Una venta con tipo_pago CREDITO exige cliente y genera una cuenta por cobrar por el
+-- total del comprobante. Los pagos se aplican a las cuentas y entran a caja como un
+-- registro de control_diario.
+
+CREATE TABLE IF NOT EXISTS clientes (
+    id_cliente          SERIAL PRIMARY KEY,
+    nombre              VARCHAR(150) NOT NULL,
+    tipo_documento      VARCHAR(3) NOT NULL DEFAULT '' CHECK (tipo_documento IN ('', 'DNI', 'RUC', 'CE')),
+    numero_documento    VARCHAR(15) NOT NULL DEFAULT '',
+    telefono            VARCHAR(30) NOT NULL DEFAULT '',
+    direccion           VARCHAR(200) NOT NULL DEFAULT '',
+    email               VARCHAR(100) NOT NULL DEFAULT '',
+    limite_credito      DECIMAL(12, 2) CHECK (limite_credito >= 0),
+    dias_credito        INTEGER NOT NULL DEFAULT 30 CHECK (dias_credito >= 0),
+    activo              BOOLEAN NOT NULL DEFAULT TRUE,
+    fecha_creacion      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    fecha_actualizacion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_documento
+    ON clientes (tipo_documento, numero_documento) WHERE numero_documento <> '';
+
+ALTER TABLE salidas_productos ADD COLUMN IF NOT EXISTS id_cliente INTEGER REFERENCES clientes (id_cliente);
+ALTER TABLE comprobantes ADD COLUMN IF NOT EXISTS id_cliente INTEGER REFERENCES clientes (id_cliente);
+CREATE INDEX IF NOT EXISTS idx_salidas_productos_cliente ON salidas_productos (id_cliente);
+
+CREATE TABLE IF NOT EXISTS cuentas_por_cobrar (
+    id_cuenta         SERIAL PRIMARY KEY,
+    id_cliente        INTEGER NOT NULL REFERENCES clientes (id_cliente),
+    id_comprobante    INTEGER NOT NULL UNIQUE REFERENCES comprobantes (id_comprobante),
+    fecha_emision     DATE NOT NULL,
+    fecha_vencimiento DATE NOT NULL,
+    monto             DECIMAL(12, 2) NOT NULL CHECK (monto >= 0),
+    saldo             DECIMAL(12, 2) NOT NULL CHECK (saldo >= 0),
+    estado            VARCHAR(10) NOT NULL DEFAULT 'PENDIENTE' CHECK (estado IN ('PENDIENTE', 'PAGADA')),
+    fecha_creacion    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (saldo <= monto)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cuentas_por_cobrar_cliente ON cuentas_por_cobrar (id_cliente, estado, fecha_emision);
+
+CREATE TABLE IF NOT EXISTS pagos_clientes (
+    id_pago          SERIAL PRIMARY KEY,
+    id_cliente       INTEGER NOT NULL REFERENCES clientes (id_cliente),
+    fecha            DATE NOT NULL,
+    monto            DECIMAL(12, 2) NOT NULL CHECK (monto > 0),
+    tipo_pago        VARCHAR(50) NOT NULL DEFAULT '',
+    observaciones    TEXT NOT NULL DEFAULT '',
+    usuario_registro VARCHAR(100) NOT NULL DEFAULT '',
+    id_control       INTEGER NOT NULL REFERENCES control_diario (id_control),
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pagos_clientes_cliente ON pagos_clientes (id_cliente, fecha);
+
+CREATE TABLE IF NOT EXISTS aplicaciones_pago (
+    id_pago   INTEGER NOT NULL REFERENCES pagos_clientes (id_pago) ON DELETE CASCADE,
+    id_cuenta INTEGER NOT NULL REFERENCES cuentas_por_cobrar (id_cuenta),
+    monto     DECIMAL(12, 2) NOT NULL CHECK (monto > 0),
+    PRIMARY KEY (id_pago, id_cuenta)
+);
@@ -0,0 +1,32 @@
+-- Devoluciones de clientes.
+-- Cada devolución referencia la salida original y no puede superar lo vendido en ella.
+-- Lo que vuelve al stock queda en ajustes_stock para que la matriz mensual cuadre; lo
+-- dañado no se reingresa. El reembolso entra a control_diario como un ingreso negativo
+-- o, en las ventas al crédito, descuenta el saldo de la cuenta por cobrar. El IGV del
+-- reembolso se descuenta del IGV de ventas del mes de la devolución.
+
+-- El reembolso se registra con monto_entrada negativo
+ALTER TABLE control_diario DROP CONSTRAINT IF EXISTS control_diario_monto_entrada_check;
+
+CREATE TABLE IF NOT EXISTS devoluciones_clientes (
+    id_devolucion    SERIAL PRIMARY KEY,
+    id_salida        INTEGER NOT NULL REFERENCES salidas_productos (id_salida),
+    id_producto      INTEGER NOT NULL REFERENCES productos (id_producto),
+    fecha            DATE NOT NULL,
+    cantidad         INTEGER NOT NULL CHECK (cantidad > 0),
+    destino          VARCHAR(10) NOT NULL DEFAULT 'STOCK' CHECK (destino IN ('STOCK', 'DANADO')),
+    metodo_reembolso VARCHAR(50) NOT NULL DEFAULT '',
+    monto_reembolso  DECIMAL(12, 2) NOT NULL CHECK (monto_reembolso >= 0),
+    -- Desglose del reembolso con la afectación y la tasa de la venta; rebaja el IGV de ventas
+    base_imponible   DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    igv              DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    motivo           TEXT NOT NULL DEFAULT '',
+    usuario_registro VARCHAR(100) NOT NULL DEFAULT '',
+    id_control       INTEGER REFERENCES control_diario (id_control),
+    id_cuenta        INTEGER REFERENCES cuentas_por_cobrar (id_cuenta),
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_devoluciones_clientes_salida ON devoluciones_clientes (id_salida);
+CREATE INDEX IF NOT EXISTS idx_devoluciones_clientes_fecha ON devoluciones_clientes (fecha);
+CREATE INDEX IF NOT EXISTS idx_devoluciones_clientes_cuenta ON devoluciones_clientes (id_cuenta) WHERE id_cuenta IS NOT NULL;
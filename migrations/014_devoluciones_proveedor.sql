@@ -0,0 +1,34 @@
+-- Proveedor de las entradas y devoluciones a proveedores.
+-- Una devolución referencia la entrada original, no puede superar lo recibido en ella
+-- y descuenta el stock al registrarse (queda en ajustes_stock). Queda PENDIENTE hasta
+-- que el proveedor emite la nota de crédito o reembolsa; los reembolsos entran a caja
+-- como un registro de control_diario. El IGV de lo devuelto se descuenta del IGV de
+-- compras del mes de la devolución.
+
+ALTER TABLE entradas_productos ADD COLUMN IF NOT EXISTS proveedor VARCHAR(150) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_entradas_productos_proveedor ON entradas_productos (UPPER(proveedor), fecha_entrada);
+
+CREATE TABLE IF NOT EXISTS devoluciones_proveedores (
+    id_devolucion     SERIAL PRIMARY KEY,
+    id_entrada        INTEGER NOT NULL REFERENCES entradas_productos (id_entrada),
+    id_producto       INTEGER NOT NULL REFERENCES productos (id_producto),
+    proveedor         VARCHAR(150) NOT NULL DEFAULT '',
+    fecha             DATE NOT NULL,
+    cantidad          INTEGER NOT NULL CHECK (cantidad > 0),
+    tipo_credito      VARCHAR(15) NOT NULL DEFAULT 'NOTA_CREDITO' CHECK (tipo_credito IN ('NOTA_CREDITO', 'REEMBOLSO')),
+    monto_esperado    DECIMAL(12, 2) NOT NULL CHECK (monto_esperado >= 0),
+    -- Parte proporcional de la base y el IGV de la entrada; rebaja el IGV de compras
+    base_imponible    DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    igv               DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    motivo            TEXT NOT NULL DEFAULT '',
+    usuario_registro  VARCHAR(100) NOT NULL DEFAULT '',
+    estado            VARCHAR(10) NOT NULL DEFAULT 'PENDIENTE' CHECK (estado IN ('PENDIENTE', 'LIQUIDADA')),
+    numero_nota       VARCHAR(30) NOT NULL DEFAULT '',
+    monto_liquidado   DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (monto_liquidado >= 0),
+    fecha_liquidacion DATE,
+    id_control        INTEGER REFERENCES control_diario (id_control),
+    fecha_creacion    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_devoluciones_proveedores_entrada ON devoluciones_proveedores (id_entrada);
+CREATE INDEX IF NOT EXISTS idx_devoluciones_proveedores_estado ON devoluciones_proveedores (estado, fecha);
@@ -0,0 +1,12 @@
+-- Baja lógica de productos y categorías.
+-- Eliminar un producto o una categoría ya no borra la fila: queda inactiva (descontinuada)
+-- con la fecha de baja, oculta en los listados y en la búsqueda del punto de venta pero
+-- presente en el historial de movimientos y en los reportes. Puede restaurarse.
+
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS activo BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE productos ADD COLUMN IF NOT EXISTS fecha_baja TIMESTAMP;
+
+ALTER TABLE categorias ADD COLUMN IF NOT EXISTS activo BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE categorias ADD COLUMN IF NOT EXISTS fecha_baja TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_productos_inactivos ON productos (id_producto) WHERE NOT activo;
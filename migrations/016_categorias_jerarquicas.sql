@@ -0,0 +1,10 @@
+-- Categorías jerárquicas.
+-- Cada categoría puede tener una categoría padre; las raíces no tienen. La aplicación
+-- evita los ciclos y no permite dar de baja una categoría con subcategorías activas.
+-- Los reportes consolidan los totales en el nivel del árbol que se pida.
+
+ALTER TABLE categorias ADD COLUMN IF NOT EXISTS id_categoria_padre INTEGER REFERENCES categorias (id_categoria);
+ALTER TABLE categorias DROP CONSTRAINT IF EXISTS categorias_padre_distinto;
+ALTER TABLE categorias ADD CONSTRAINT categorias_padre_distinto CHECK (id_categoria_padre <> id_categoria);
+
+CREATE INDEX IF NOT EXISTS idx_categorias_padre ON categorias (id_categoria_padre);
@@ -0,0 +1,21 @@
+-- Imágenes de productos y adjuntos de entradas y control diario.
+-- El contenido va en el almacenamiento de archivos (por defecto un directorio local);
+-- aquí solo se guarda su ruta relativa y la de la miniatura de las imágenes. La tabla
+-- es común a las tres entidades, por eso id_entidad no tiene clave foránea.
+
+CREATE TABLE IF NOT EXISTS archivos (
+    id_archivo       SERIAL PRIMARY KEY,
+    entidad          VARCHAR(20) NOT NULL CHECK (entidad IN ('PRODUCTO', 'ENTRADA', 'CONTROL_DIARIO')),
+    id_entidad       INTEGER NOT NULL,
+    tipo             VARCHAR(10) NOT NULL CHECK (tipo IN ('IMAGEN', 'ADJUNTO')),
+    nombre_original  VARCHAR(255) NOT NULL DEFAULT '',
+    content_type     VARCHAR(100) NOT NULL,
+    tamano           BIGINT NOT NULL CHECK (tamano > 0),
+    ruta             VARCHAR(255) NOT NULL UNIQUE,
+    ruta_miniatura   VARCHAR(255),
+    orden            INTEGER NOT NULL DEFAULT 1,
+    usuario_registro VARCHAR(100) NOT NULL DEFAULT '',
+    fecha_creacion   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_archivos_entidad ON archivos (entidad, id_entidad, orden);
@@ -0,0 +1,5 @@
+-- Índice de trigramas sobre los atributos de las variantes para que la búsqueda las
+-- encuentre por sus valores ("rojo", "xl") sin recorrer todo el catálogo
+
+CREATE INDEX IF NOT EXISTS idx_productos_atributos_trgm
+    ON productos USING gin (f_unaccent(lower(atributos::text)) gin_trgm_ops);